@@ -31,6 +31,37 @@ try {
 // Get all command line arguments (skip node and script name)
 const args = process.argv.slice(2);
 
+// `saligen generate ...` runs the Go generator, which builds the Go SDK in
+// one step without openapi-generator
+if (args[0] === 'generate') {
+  const goModule = path.join(packageDir, 'sdk', 'wrappers', 'go');
+  // The generator runs from its own module, so resolve paths against ours
+  const pathFlags = ['--spec', '-spec', '--out', '-out', '--runtime', '-runtime', '--templates', '-templates'];
+  const goArgs = args.map((arg, i) => {
+    const [flag, value] = arg.split(/=(.*)/s);
+    if (value !== undefined && pathFlags.includes(flag)) {
+      return `${flag}=${path.resolve(value)}`;
+    }
+    if (i > 0 && pathFlags.includes(args[i - 1]) && !arg.includes('=')) {
+      return path.resolve(arg);
+    }
+    return arg;
+  });
+  const child = spawn('go', ['run', './cmd/saligen', ...goArgs], {
+    stdio: 'inherit',
+    cwd: goModule
+  });
+  child.on('error', (error) => {
+    console.error('❌ Error running the Go generator:', error.message);
+    if (error.code === 'ENOENT') {
+      console.error('   Make sure Go 1.21+ is installed and available in your PATH');
+    }
+    process.exit(1);
+  });
+  child.on('exit', (code) => process.exit(code || 0));
+  return;
+}
+
 // Spawn the bash script
 const child = spawn('bash', [generateScript, ...args], {
   stdio: 'inherit',
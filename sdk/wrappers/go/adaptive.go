@@ -0,0 +1,37 @@
+package yourapi
+
+import (
+	"sync"
+	"time"
+)
+
+// adaptivePacer spreads the server's remaining quota over the time left in
+// its rate limit window, based on the last X-RateLimit-* headers seen
+type adaptivePacer struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// reserve returns how long the caller must wait so that requests are spaced
+// evenly across what remains of the server's window. It returns 0 when the
+// server has not reported both a remaining count and a future reset time.
+// Unless wait is set, a slot that is not available immediately is left
+// unreserved and ok is false.
+func (p *adaptivePacer) reserve(state RateLimitState, now time.Time, wait bool) (delay time.Duration, ok bool) {
+	if state.Remaining < 0 || !state.Reset.After(now) {
+		return 0, true
+	}
+	interval := state.Reset.Sub(now) / time.Duration(state.Remaining+1)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	slot := p.next
+	if slot.Before(now) {
+		slot = now
+	}
+	if slot.After(now) && !wait {
+		return slot.Sub(now), false
+	}
+	p.next = slot.Add(interval)
+	return slot.Sub(now), true
+}
@@ -0,0 +1,17 @@
+package yourapi
+
+import "context"
+
+// API is the request surface of Client. Depend on it instead of *Client to
+// substitute a mock, such as yourapimock.Client, in unit tests.
+type API interface {
+	Get(ctx context.Context, path string, result interface{}, opts ...RequestOption) error
+	Post(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error
+	Patch(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error
+	Put(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error
+	Delete(ctx context.Context, path string, opts ...RequestOption) error
+	PaginateCursor(ctx context.Context, path string, callback func(interface{}) error, opts ...RequestOption) error
+	GetAllCursor(ctx context.Context, path string, opts ...RequestOption) ([]interface{}, error)
+}
+
+var _ API = (*Client)(nil)
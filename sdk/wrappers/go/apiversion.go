@@ -0,0 +1,54 @@
+package yourapi
+
+import (
+	"net/http"
+	"net/url"
+	"sync/atomic"
+)
+
+// DefaultAPIVersionHeader is the header the API version is sent in, and in
+// which the server reports the version that served a response
+const DefaultAPIVersionHeader = "X-API-Version"
+
+// WithAPIVersion pins every request to the API version v, see
+// ClientOptions.APIVersion
+func WithAPIVersion(v string) Option {
+	return func(o *ClientOptions) {
+		o.APIVersion = v
+	}
+}
+
+// versionedBaseURL appends the API version to baseURL when it is sent as a
+// path prefix
+func (o *ClientOptions) versionedBaseURL(baseURL string) string {
+	if o.APIVersion == "" || !o.APIVersionInPath {
+		return baseURL
+	}
+	return baseURL + "/" + url.PathEscape(o.APIVersion)
+}
+
+// serverVersion holds the latest API version reported by the server
+type serverVersion struct {
+	v atomic.Pointer[string]
+}
+
+// observe records the version reported in h under name, if any
+func (s *serverVersion) observe(h http.Header, name string) {
+	if v := h.Get(name); v != "" {
+		s.v.Store(&v)
+	}
+}
+
+func (s *serverVersion) get() string {
+	if v := s.v.Load(); v != nil {
+		return *v
+	}
+	return ""
+}
+
+// ServerAPIVersion returns the API version the server reported on the
+// latest response carrying APIVersionHeader, or "" before any did. Compare
+// it with the pinned APIVersion to notice a server rollout.
+func (c *Client) ServerAPIVersion() string {
+	return c.serverVersion.get()
+}
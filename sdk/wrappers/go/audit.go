@@ -0,0 +1,69 @@
+package yourapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// AuditRecord describes a completed mutating request
+type AuditRecord struct {
+	Time           time.Time
+	Method         string
+	Path           string
+	IdempotencyKey string
+	// Actor is the identity stored on the context with WithActor
+	Actor string
+	// Status is the final HTTP status, 0 if no response was received
+	Status int
+	// RequestID is the server's request ID, when reported
+	RequestID string
+	Duration  time.Duration
+	Err       error
+}
+
+// AuditHook receives a record of every POST, PUT, PATCH and DELETE request
+// once it completes
+type AuditHook func(ctx context.Context, record AuditRecord)
+
+type actorKey struct{}
+
+// WithActor returns a context whose mutating requests are attributed to
+// actor in audit records
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx, if any
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// isMutating reports whether requests with method change server state
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// audit reports a completed mutating request to the audit hook
+func (c *Client) audit(ctx context.Context, ci *callInfo, headers map[string]string, duration time.Duration, err error) {
+	record := AuditRecord{
+		Time:           ci.start,
+		Method:         ci.method,
+		Path:           ci.path,
+		IdempotencyKey: headers[IdempotencyKeyHeader],
+		Status:         ci.status,
+		RequestID:      ci.requestID,
+		Duration:       duration,
+		Err:            err,
+	}
+	record.Actor, _ = ActorFromContext(ctx)
+	if apiErr, ok := AsAPIError(err); ok && apiErr.RequestID != "" {
+		record.RequestID = apiErr.RequestID
+	}
+	c.auditHook(ctx, record)
+}
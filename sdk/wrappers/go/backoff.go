@@ -0,0 +1,79 @@
+package yourapi
+
+import "time"
+
+const (
+	// DefaultRetryBackoffBase is the wait before the first retry
+	DefaultRetryBackoffBase = time.Second
+	// DefaultRetryBackoffMax caps the exponential wait between retries
+	DefaultRetryBackoffMax = 8 * time.Second
+)
+
+// RetryBackoff configures the wait between retry attempts. Tests can shrink
+// it to make retry paths run in milliseconds.
+type RetryBackoff struct {
+	// Base is the wait before the first retry, doubled for each further
+	// retry (default: 1s)
+	Base time.Duration
+	// Max caps the exponential wait (default: 8s)
+	Max time.Duration
+	// MaxRetryAfter caps waits requested by the server through Retry-After
+	// (default: uncapped)
+	MaxRetryAfter time.Duration
+}
+
+// RetryPolicy overrides the client's retry settings for one request, set
+// with WithRetryPolicy
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt; 0
+	// disables retrying
+	MaxRetries int
+	// Backoff configures the waits between attempts; unset fields use the
+	// client's RetryBackoff
+	Backoff RetryBackoff
+}
+
+// over returns the backoff with unset fields taken from base
+func (b RetryBackoff) over(base RetryBackoff) RetryBackoff {
+	if b.Base <= 0 {
+		b.Base = base.Base
+	}
+	if b.Max <= 0 {
+		b.Max = base.Max
+	}
+	if b.MaxRetryAfter <= 0 {
+		b.MaxRetryAfter = base.MaxRetryAfter
+	}
+	return b
+}
+
+// withDefaults fills unset fields
+func (b RetryBackoff) withDefaults() RetryBackoff {
+	if b.Base <= 0 {
+		b.Base = DefaultRetryBackoffBase
+	}
+	if b.Max <= 0 {
+		b.Max = DefaultRetryBackoffMax
+	}
+	return b
+}
+
+// exponential returns the wait after the given zero-based attempt
+func (b RetryBackoff) exponential(attempt int) time.Duration {
+	d := b.Base
+	for i := 0; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+// retryAfter applies MaxRetryAfter to a server-requested wait
+func (b RetryBackoff) retryAfter(d time.Duration) time.Duration {
+	if b.MaxRetryAfter > 0 && d > b.MaxRetryAfter {
+		return b.MaxRetryAfter
+	}
+	return d
+}
@@ -0,0 +1,37 @@
+package yourapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// normalizeBaseURL validates a base URL and returns it in the form paths
+// are appended to: an absolute http or https URL with a lowercase scheme
+// and host and no trailing slash
+func normalizeBaseURL(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "", fmt.Errorf("base URL is required")
+	}
+	if !strings.Contains(s, "://") {
+		return "", fmt.Errorf("invalid base URL %q: missing scheme, e.g. https://%s", raw, strings.TrimPrefix(s, "//"))
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", raw, err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	switch {
+	case u.Scheme != "http" && u.Scheme != "https":
+		return "", fmt.Errorf("invalid base URL %q: scheme must be http or https", raw)
+	case u.Host == "":
+		return "", fmt.Errorf("invalid base URL %q: missing host", raw)
+	case u.RawQuery != "" || u.ForceQuery || u.Fragment != "":
+		return "", fmt.Errorf("invalid base URL %q: must not have a query or fragment", raw)
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+	return u.String(), nil
+}
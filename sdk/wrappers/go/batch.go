@@ -0,0 +1,172 @@
+package yourapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// BatchItemResult is the outcome of a single item in a batch response
+type BatchItemResult[T any] struct {
+	// Index is the position of the item in the original request
+	Index int
+	// Status is the per-item HTTP status
+	Status int
+	// Value is the decoded item on success
+	Value T
+	// Err is set when the item failed
+	Err *APIError
+}
+
+// OK reports whether the item succeeded
+func (r BatchItemResult[T]) OK() bool {
+	return r.Err == nil
+}
+
+// BatchResult decodes batch responses (typically 207 Multi-Status) that
+// report a status per item, so partial failures are not mistaken for
+// success or failure of the whole call. Pass a *BatchResult[T] as the
+// result of Post, Put or Patch.
+//
+// Both a bare array and an object holding the array under results, items
+// or responses are accepted. Each entry carries a status, its payload
+// under data, body or result, and, on failure, an error object.
+type BatchResult[T any] struct {
+	Items []BatchItemResult[T]
+}
+
+// Succeeded returns the values of the items that succeeded
+func (r *BatchResult[T]) Succeeded() []T {
+	var values []T
+	for _, item := range r.Items {
+		if item.OK() {
+			values = append(values, item.Value)
+		}
+	}
+	return values
+}
+
+// Failed returns the items that failed
+func (r *BatchResult[T]) Failed() []BatchItemResult[T] {
+	var failed []BatchItemResult[T]
+	for _, item := range r.Items {
+		if !item.OK() {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Err returns a *BatchError describing the failed items, or nil if every
+// item succeeded
+func (r *BatchResult[T]) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	batchErr := &BatchError{Total: len(r.Items)}
+	for _, item := range failed {
+		batchErr.Failures = append(batchErr.Failures, BatchFailure{Index: item.Index, Err: item.Err})
+	}
+	return batchErr
+}
+
+// batchEntry is the wire shape of a single batch item
+type batchEntry struct {
+	Index  *int                   `json:"index"`
+	Status int                    `json:"status"`
+	Data   json.RawMessage        `json:"data"`
+	Body   json.RawMessage        `json:"body"`
+	Result json.RawMessage        `json:"result"`
+	Error  map[string]interface{} `json:"error"`
+}
+
+// UnmarshalJSON decodes the batch envelope and each item
+func (r *BatchResult[T]) UnmarshalJSON(data []byte) error {
+	var entries []batchEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return fmt.Errorf("failed to decode batch response: %w", err)
+		}
+		list := firstRaw(envelope, "results", "items", "responses")
+		if list == nil {
+			return errors.New("failed to decode batch response: no results array")
+		}
+		if err := json.Unmarshal(list, &entries); err != nil {
+			return fmt.Errorf("failed to decode batch response: %w", err)
+		}
+	}
+
+	r.Items = make([]BatchItemResult[T], 0, len(entries))
+	for i, entry := range entries {
+		item := BatchItemResult[T]{Index: i, Status: entry.Status}
+		if entry.Index != nil {
+			item.Index = *entry.Index
+		}
+
+		if entry.Error != nil || entry.Status >= 400 {
+			status := entry.Status
+			if status == 0 {
+				status = http.StatusInternalServerError
+			}
+			errorBody := entry.Error
+			if errorBody == nil {
+				errorBody = map[string]interface{}{}
+			}
+			item.Err = newAPIError(status, errorBody, "")
+		} else if payload := firstNonNull(entry.Data, entry.Body, entry.Result); payload != nil {
+			if err := json.Unmarshal(payload, &item.Value); err != nil {
+				return fmt.Errorf("failed to decode batch item %d: %w", item.Index, err)
+			}
+		}
+
+		r.Items = append(r.Items, item)
+	}
+	return nil
+}
+
+// BatchFailure identifies a failed batch item
+type BatchFailure struct {
+	Index int
+	Err   *APIError
+}
+
+// BatchError reports the failed items of a partially successful batch
+type BatchError struct {
+	Total    int
+	Failures []BatchFailure
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d batch items failed (first: item %d: %v)",
+		len(e.Failures), e.Total, e.Failures[0].Index, e.Failures[0].Err)
+}
+
+// Unwrap returns the per-item errors so errors.Is and errors.As match them
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+func firstRaw(envelope map[string]json.RawMessage, keys ...string) json.RawMessage {
+	for _, k := range keys {
+		if raw, ok := envelope[k]; ok {
+			return raw
+		}
+	}
+	return nil
+}
+
+func firstNonNull(values ...json.RawMessage) json.RawMessage {
+	for _, v := range values {
+		if len(v) > 0 && string(v) != "null" {
+			return v
+		}
+	}
+	return nil
+}
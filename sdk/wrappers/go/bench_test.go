@@ -0,0 +1,159 @@
+package yourapi_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+	"github.com/devdraft/devdraft-sdk-go/yourapitest"
+)
+
+type benchCustomer struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Phone     *string   `json:"phone"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+var benchBody = map[string]interface{}{
+	"id":        "123e4567-e89b-12d3-a456-426614174000",
+	"email":     "ada@example.com",
+	"name":      "Ada Lovelace",
+	"phone":     nil,
+	"createdAt": "2024-01-01T00:00:00Z",
+	"updatedAt": "2024-01-01T00:00:00Z",
+}
+
+func newBenchClient(b *testing.B, srv *yourapitest.Server) *yourapi.Client {
+	b.Helper()
+	client, err := yourapi.NewClient(srv.ClientOptions())
+	if err != nil {
+		b.Fatal(err)
+	}
+	return client
+}
+
+func reportRPS(b *testing.B, start time.Time) {
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "req/s")
+}
+
+func BenchmarkGet(b *testing.B) {
+	srv := yourapitest.NewServer(b)
+	srv.JSON(http.MethodGet, "/customers/{id}", http.StatusOK, benchBody)
+	client := newBenchClient(b, srv)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		var c benchCustomer
+		if err := client.Get(ctx, "/customers/123e4567-e89b-12d3-a456-426614174000", &c); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportRPS(b, start)
+}
+
+func BenchmarkGetParallel(b *testing.B) {
+	srv := yourapitest.NewServer(b)
+	srv.JSON(http.MethodGet, "/customers/{id}", http.StatusOK, benchBody)
+	client := newBenchClient(b, srv)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			var c benchCustomer
+			if err := client.Get(ctx, "/customers/123e4567-e89b-12d3-a456-426614174000", &c); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+	reportRPS(b, start)
+}
+
+func BenchmarkPost(b *testing.B) {
+	srv := yourapitest.NewServer(b)
+	srv.JSON(http.MethodPost, "/customers", http.StatusCreated, benchBody)
+	client := newBenchClient(b, srv)
+	ctx := context.Background()
+	req := map[string]interface{}{"email": "ada@example.com", "name": "Ada Lovelace"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		var c benchCustomer
+		if err := client.Post(ctx, "/customers", req, &c); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportRPS(b, start)
+}
+
+// BenchmarkRetry measures a request that fails once with 503 and succeeds
+// on the retry, covering error parsing and backoff bookkeeping
+func BenchmarkRetry(b *testing.B) {
+	srv := yourapitest.NewServer(b)
+	var calls atomic.Int64
+	srv.HandleFunc(http.MethodGet, "/customers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1)%2 == 1 {
+			yourapitest.WriteError(w, http.StatusServiceUnavailable, "unavailable", "try again")
+			return
+		}
+		yourapitest.WriteJSON(w, http.StatusOK, benchBody)
+	})
+	opts := srv.ClientOptions()
+	opts.RetryBackoff = yourapi.RetryBackoff{Base: time.Nanosecond, Max: time.Nanosecond}
+	client, err := yourapi.NewClient(opts)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		var c benchCustomer
+		if err := client.Get(ctx, "/customers/123e4567-e89b-12d3-a456-426614174000", &c); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportRPS(b, start)
+}
+
+// BenchmarkGetStub isolates the client from the network by serving from a
+// StubTransport
+func BenchmarkGetStub(b *testing.B) {
+	stub := yourapitest.NewStubTransport()
+	resp := yourapitest.StubResponse{JSON: benchBody}
+	client, err := yourapi.NewClient(stub.ClientOptions())
+	if err != nil {
+		b.Fatal(err)
+	}
+	responses := make([]yourapitest.StubResponse, b.N)
+	for i := range responses {
+		responses[i] = resp
+	}
+	stub.On(http.MethodGet, "/customers/{id}", responses...)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var c benchCustomer
+		if err := client.Get(ctx, "/customers/123e4567-e89b-12d3-a456-426614174000", &c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package yourapi
+
+// BudgetReporter is implemented by RateLimiters that can report how many
+// requests they would admit right now
+type BudgetReporter interface {
+	// Available returns the number of permits immediately available for the
+	// endpoint, or -1 if it is not limited
+	Available(method, pathTemplate string) int
+}
+
+// Budget combines the client-side limiter state with the server's last
+// reported rate limit
+type Budget struct {
+	// Local is the number of requests the client-side rate limiter would
+	// admit immediately, -1 if requests are not limited locally or the
+	// RateLimiter does not implement BudgetReporter
+	Local int `json:"local"`
+	// Server is the rate limit last reported by the server
+	Server RateLimitState `json:"server"`
+	// Remaining is the smaller of Local and Server.Remaining, ignoring
+	// unknown values; -1 if both are unknown
+	Remaining int `json:"remaining"`
+	// InFlight is the number of requests currently being sent
+	InFlight int64 `json:"inFlight"`
+	// Queued is the number of requests waiting for a MaxConcurrentRequests
+	// slot
+	Queued int64 `json:"queued"`
+}
+
+// RemainingBudget reports how many more requests can be sent right now
+// without being throttled by the client or, as far as the client knows, the
+// server. Local reflects the client-wide RateLimit.
+func (c *Client) RemainingBudget() Budget {
+	return c.budget("", "")
+}
+
+// EndpointBudget is RemainingBudget for requests to method and path, taking
+// EndpointRateLimits into account
+func (c *Client) EndpointBudget(method, path string) Budget {
+	return c.budget(method, pathTemplate(path))
+}
+
+func (c *Client) budget(method, template string) Budget {
+	b := Budget{
+		Local:    -1,
+		Server:   c.rateLimit.get(),
+		InFlight: c.inFlight.Load(),
+		Queued:   c.queued(),
+	}
+	if reporter, ok := c.rateLimiter.(BudgetReporter); ok {
+		b.Local = reporter.Available(method, template)
+	}
+
+	b.Remaining = b.Local
+	if server := b.Server.Remaining; server >= 0 && !b.Server.UpdatedAt.IsZero() {
+		if !b.Server.Reset.IsZero() && !b.Server.Reset.After(c.clock.Now()) {
+			// The server's window has reset since it last reported
+			server = -1
+		}
+		if server >= 0 && (b.Remaining < 0 || server < b.Remaining) {
+			b.Remaining = server
+		}
+	}
+	return b
+}
@@ -0,0 +1,66 @@
+package yourapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// callInfo tracks one logical request across its retry attempts
+type callInfo struct {
+	method   string
+	path     string
+	template string
+	start    time.Time
+	// status is the status of the last response received, 0 if none
+	status int
+	// attempts is the number of attempts made so far
+	attempts int
+	// debug enables verbose logging for this request
+	debug bool
+	// failFast makes client-side throttling return an error instead of
+	// waiting
+	failFast bool
+	// priority orders the call among requests queued by client-side limits
+	priority Priority
+	// retry overrides the client's retry settings when set
+	retry *RetryPolicy
+	// baseURL replaces the client's BaseURL when set
+	baseURL string
+	// attemptTimeout replaces the client's Timeout for each attempt when
+	// set
+	attemptTimeout time.Duration
+	// attemptDurations is the time spent in each attempt, up to response
+	// headers or a transport error
+	attemptDurations []time.Duration
+	// backoff is the total time spent waiting between attempts
+	backoff time.Duration
+	// requestID is the X-Request-Id of the last response received
+	requestID string
+	// header holds the headers of the last response received
+	header http.Header
+	// timings holds connection-level timings of the last attempt when
+	// ConnectionTimings is enabled, guarded by timingsMu
+	timings   *ConnTimings
+	timingsMu *sync.Mutex
+}
+
+type callContextKey struct{}
+
+// withCall starts tracking a logical request
+func withCall(ctx context.Context, method, path string, start time.Time) (context.Context, *callInfo) {
+	ci := &callInfo{
+		method:   method,
+		path:     path,
+		template: pathTemplate(path),
+		start:    start,
+	}
+	return context.WithValue(ctx, callContextKey{}, ci), ci
+}
+
+// callFromContext returns the logical request being tracked, if any
+func callFromContext(ctx context.Context) *callInfo {
+	ci, _ := ctx.Value(callContextKey{}).(*callInfo)
+	return ci
+}
@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
+	"net"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,58 +24,252 @@ const (
 	DefaultTimeout = 15 * time.Second
 	// DefaultMaxRetries is the default maximum number of retries
 	DefaultMaxRetries = 3
+	// DefaultMaxErrorBodySize is the default cap on error response bodies
+	DefaultMaxErrorBodySize = 1 << 20
+	// DefaultMaxLoggedBodySize is the default cap on bodies logged with
+	// DebugBodies
+	DefaultMaxLoggedBodySize = 4 << 10
 )
 
 // ClientOptions contains configuration options for the SDK client
 type ClientOptions struct {
-	// BaseURL is the base URL for the API (required)
+	// BaseURL is the base URL for the API (required unless Environment is
+	// set). A unix:// URL such as "unix:///var/run/yourapi.sock" reaches a
+	// local agent or sidecar over its socket.
 	BaseURL string
+	// Environment selects a known deployment of the API, such as
+	// EnvironmentSandbox, when BaseURL is empty
+	Environment Environment
 	// APIKey is the API key for authentication (optional)
 	APIKey string
-	// BearerToken is the bearer token for authentication (optional)
+	// BearerToken is the bearer token for authentication (optional). It
+	// cannot be combined with APIKey.
 	BearerToken string
-	// Timeout is the request timeout (default: 15s)
+	// Timeout is the request timeout (default: 15s; NoTimeout disables it)
 	Timeout time.Duration
-	// MaxRetries is the maximum number of retry attempts (default: 3)
+	// MaxRetries is the maximum number of retry attempts (default: 3;
+	// negative disables retries)
 	MaxRetries int
+	// RetryBackoff tunes the wait between retries (default: 1s doubling up
+	// to 8s, honoring Retry-After)
+	RetryBackoff RetryBackoff
 	// UserAgent is the custom user agent string
 	UserAgent string
 	// CustomHeaders are additional headers to include in all requests
 	CustomHeaders map[string]string
 	// HTTPClient is a custom HTTP client (optional)
 	HTTPClient *http.Client
-	// Debug enables debug logging
+	// ProxyURL sends requests through this proxy, e.g.
+	// "http://proxy.internal:3128" (default: the proxy named by the
+	// HTTP_PROXY and HTTPS_PROXY environment variables, if any)
+	ProxyURL string
+	// NoProxy lists hosts reached directly rather than through the proxy,
+	// written like NO_PROXY entries: domains, IP addresses or CIDR ranges,
+	// optionally with a port, or "*" to bypass proxies entirely
+	NoProxy []string
+	// TLS sets the trusted CAs, minimum version and server name used to
+	// verify the API's certificate
+	TLS TLSOptions
+	// DialContext opens the connections of requests in place of the default
+	// dialer, e.g. to reach the API through a tunnel (optional)
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// Resolver looks up the API's host in place of the system resolver,
+	// e.g. a split-horizon DNS server (optional)
+	Resolver *net.Resolver
+	// Hosts maps host names to the IP address dialed for them, overriding
+	// DNS like /etc/hosts, e.g. to route to a canary. Certificates are still
+	// verified against the host name.
+	Hosts map[string]string
+	// Redirects controls how 3xx responses are followed. It is applied to a
+	// custom HTTPClient only when that client has no CheckRedirect of its own
+	Redirects RedirectPolicy
+	// Debug enables debug logging when no Logger is set
 	Debug bool
+	// DebugWriter receives debug output (default: os.Stdout)
+	DebugWriter io.Writer
+	// DebugFormat selects text or JSON-lines debug output (default: text)
+	DebugFormat DebugFormat
+	// RedactHeaders are masked in all log output, in addition to the
+	// built-in Authorization, X-API-Key and cookie headers
+	RedactHeaders []string
+	// RedactBodyFields are JSON field names masked at any depth in logged
+	// bodies
+	RedactBodyFields []string
+	// DebugBodies logs request and response bodies at debug level, after
+	// redaction and capped at MaxLoggedBodySize
+	DebugBodies bool
+	// MaxLoggedBodySize caps each logged body (default: 4 KiB)
+	MaxLoggedBodySize int
+	// SlowRequestThreshold logs a warning and emits a SlowRequest event for
+	// requests taking at least this long, retries included (optional)
+	SlowRequestThreshold time.Duration
+	// AutoIdempotencyKeys generates a UUIDv4 Idempotency-Key for each POST
+	// made without one, reused across its retries (optional)
+	AutoIdempotencyKeys bool
+	// IdempotencyKeyGenerator replaces the UUIDv4 keys of
+	// AutoIdempotencyKeys; setting it enables AutoIdempotencyKeys (optional)
+	IdempotencyKeyGenerator IdempotencyKeyGenerator
+	// CoalesceWrites merges concurrent identical POST, PUT, PATCH and
+	// DELETE requests into one outbound request whose result every caller
+	// receives. Writes are identical when they share an idempotency key or,
+	// without one, the same method, path and body (optional)
+	CoalesceWrites bool
+	// RateLimit paces requests client-side to stay within the API's
+	// published quota (optional)
+	RateLimit RateLimit
+	// MaxConcurrentRequests caps the number of requests in flight at once;
+	// further requests queue until a slot frees up (optional)
+	MaxConcurrentRequests int
+	// AdaptiveThrottling paces requests from the server's
+	// X-RateLimit-Remaining and X-RateLimit-Reset headers, spreading the
+	// remaining quota over the rest of the window (optional)
+	AdaptiveThrottling bool
+	// EndpointRateLimits override RateLimit for matching endpoints; the
+	// first matching entry applies (optional)
+	EndpointRateLimits []EndpointRateLimit
+	// RateLimiter replaces the in-process limiter built from RateLimit and
+	// EndpointRateLimits, e.g. with a RedisRateLimiter shared across
+	// processes (optional)
+	RateLimiter RateLimiter
+	// ConnectionTimings records DNS, connect, TLS and time-to-first-byte
+	// timings for each request, reported through RequestFinished events and
+	// RequestMetrics (optional)
+	ConnectionTimings bool
+	// AuditHook receives a record of every POST, PUT, PATCH and DELETE
+	// request (optional)
+	AuditHook AuditHook
+	// Recorder logs every request sent, including retries, for test
+	// assertions (optional)
+	Recorder *RequestRecorder
+	// CorrelationIDHeader is the header carrying the correlation ID set with
+	// WithCorrelationID (default: X-Correlation-ID)
+	CorrelationIDHeader string
+	// Clock is the time source for backoff, Retry-After handling and
+	// latency measurement (default: the system clock)
+	Clock Clock
+	// Logger receives the client's log events. When set, every event is
+	// passed on and the logger's own level decides what is kept (optional)
+	Logger Logger
+	// Locale is a BCP 47 language tag (e.g. "fr-CA") sent as Accept-Language
+	// and used to pick localized error messages (optional)
+	Locale string
+	// APIVersion pins requests to a version of the API, e.g. "2024-06-01",
+	// so server rollouts do not change behavior under the integration
+	// (optional)
+	APIVersion string
+	// APIVersionHeader is the header carrying APIVersion and the server's
+	// reported version (default: DefaultAPIVersionHeader)
+	APIVersionHeader string
+	// APIVersionInPath sends APIVersion as a path segment appended to the
+	// base URL instead of a header
+	APIVersionInPath bool
+	// Tenant scopes every request to a tenant, so multi-tenant callers do
+	// not pass its ID to each call (optional). Clone a client per tenant.
+	Tenant string
+	// Organization scopes every request to an organization (optional)
+	Organization string
+	// Scoping selects whether Tenant and Organization are sent as headers
+	// or path prefixes (default: ScopeHeader)
+	Scoping Scoping
+	// TenantHeader is the header carrying Tenant (default:
+	// DefaultTenantHeader)
+	TenantHeader string
+	// OrganizationHeader is the header carrying Organization (default:
+	// DefaultOrganizationHeader)
+	OrganizationHeader string
+	// ErrorObserver is called with every error returned to the caller, after
+	// retries have been exhausted (optional)
+	ErrorObserver ErrorObserver
+	// ErrorCounter is incremented for every terminal error, keyed by method,
+	// path template, status and error code (optional)
+	ErrorCounter ErrorCounter
+	// MaxErrorBodySize caps how many bytes of an error response body are
+	// read (default: 1 MiB)
+	MaxErrorBodySize int64
+	// TracerProvider enables a span per request, with retries recorded as
+	// span events (optional)
+	TracerProvider TracerProvider
+	// Propagator injects trace headers into each request. Defaults to
+	// forwarding the W3C trace context set with WithTraceContext
+	Propagator Propagator
+	// PropagateB3 additionally sends the default trace context as a B3
+	// single header
+	PropagateB3 bool
+	// Metrics receives request totals, durations, retries and in-flight
+	// counts (optional)
+	Metrics MetricsSink
+	// DisableValidation skips the client-side checks of request bodies and
+	// parameters against the spec's constraints, leaving them to the API
+	DisableValidation bool
+	// Protocol selects the transport of the service methods. ProtocolConnect
+	// calls the API's Connect procedures instead of its REST endpoints, with
+	// the same auth, retries and observability; methods generated without
+	// a Connect procedure keep using REST. Defaults to ProtocolREST.
+	Protocol Protocol
+	// ConnectBaseURL is the base URL of the Connect procedures (default: the
+	// scheme and host of BaseURL)
+	ConnectBaseURL string
+	// Profile names the config file profile filling the options left unset
+	// (optional), see LoadProfile
+	Profile string
+
+	// loadEnv is set by WithEnv
+	loadEnv bool
+	// err is set by an Option that could not be applied, failing NewClient
+	err error
 }
 
 // Client is the main SDK client
 type Client struct {
-	baseURL       string
-	httpClient    *http.Client
-	maxRetries    int
-	userAgent     string
-	customHeaders map[string]string
-	debug         bool
-}
-
-// APIError represents a structured API error
-type APIError struct {
-	Message   string                 `json:"message"`
-	Code      string                 `json:"code,omitempty"`
-	Details   interface{}            `json:"details,omitempty"`
-	RequestID string                 `json:"requestId,omitempty"`
-	Status    int                    `json:"-"`
-	Body      map[string]interface{} `json:"-"`
-}
-
-func (e *APIError) Error() string {
-	if e.RequestID != "" {
-		return fmt.Sprintf("[%d] %s (code=%s, request_id=%s)", e.Status, e.Message, e.Code, e.RequestID)
-	}
-	if e.Code != "" {
-		return fmt.Sprintf("[%d] %s (code=%s)", e.Status, e.Message, e.Code)
-	}
-	return fmt.Sprintf("[%d] %s", e.Status, e.Message)
+	// Services gives typed access to the API's resources, e.g.
+	// client.Customers.Get
+	Services
+
+	baseURL           string
+	httpClient        *http.Client
+	maxRetries        int
+	backoff           RetryBackoff
+	apiKey            string
+	bearerToken       string
+	userAgent         string
+	customHeaders     map[string]string
+	logger            Logger
+	debugLogger       Logger
+	redactor          *redactor
+	debugBodies       bool
+	maxLoggedBodySize int
+	locale            string
+	apiVersion        string
+	apiVersionHeader  string
+	serverVersion     serverVersion
+	errorObserver     ErrorObserver
+	errorCounter      ErrorCounter
+	maxErrorBodySize  int64
+	tracer            Tracer
+	propagator        Propagator
+	metrics           MetricsSink
+	stats             *latencyStats
+	clock             Clock
+	events            *eventBus
+	correlationHeader string
+	slowThreshold     time.Duration
+	rateLimit         *rateLimitTracker
+	auditHook         AuditHook
+	connTimings       bool
+	skipValidation    bool
+	rateLimiter       RateLimiter
+	pacer             *adaptivePacer
+	gate              *concurrencyGate
+	rateWaiters       *priorityWaiters
+	idempotencyKeys   IdempotencyKeyGenerator
+	writes            *writeGroup
+	recorder          *RequestRecorder
+	protocol          Protocol
+	connectBaseURL    string
+	pathPrefix        string
+	options           ClientOptions
+	inFlight          atomic.Int64
+	conns             connCounters
 }
 
 // CursorPaginatedResponse represents a cursor-based paginated response
@@ -91,100 +290,313 @@ type PagePaginatedResponse struct {
 
 // NewClient creates a new SDK client
 func NewClient(opts ClientOptions) (*Client, error) {
+	return newClient(opts, nil)
+}
+
+// newClient creates a client, sharing parent's transport and client-side
+// rate limiting when parent is not nil
+func newClient(opts ClientOptions, parent *Client) (*Client, error) {
+	// Every problem with the options is collected, then reported at once
+	var problems []error
+	if opts.err != nil {
+		problems = append(problems, opts.err)
+	}
+	if opts.loadEnv {
+		if err := opts.LoadEnv(); err != nil {
+			problems = append(problems, err)
+		}
+	} else if opts.Profile != "" {
+		if err := opts.LoadProfile(opts.Profile); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	problems = append(problems, opts.validate()...)
 	if opts.BaseURL == "" {
-		return nil, fmt.Errorf("base URL is required")
+		opts.BaseURL = opts.Environment.BaseURL()
+	}
+	socket, err := parseUnixBaseURL(opts.BaseURL)
+	baseURL := unixRequestBase
+	switch {
+	case err != nil:
+		problems = append(problems, err)
+		opts.BaseURL = ""
+	case socket != "":
+		opts.BaseURL = "unix://" + socket
+	default:
+		if baseURL, err = normalizeBaseURL(opts.BaseURL); err != nil {
+			problems = append(problems, err)
+		}
+		opts.BaseURL = baseURL
 	}
+	if opts.ConnectBaseURL != "" {
+		if opts.ConnectBaseURL, err = normalizeBaseURL(opts.ConnectBaseURL); err != nil {
+			problems = append(problems, fmt.Errorf("ConnectBaseURL: %w", err))
+		}
+	}
+	// The API version and scope prefixes are appended to root below
+	root := baseURL
+	// Clones start from the options as configured, defaults not yet applied
+	configured := opts
+	configured.loadEnv, configured.Profile = false, ""
 
 	// Set defaults
 	if opts.Timeout == 0 {
 		opts.Timeout = DefaultTimeout
+	} else if opts.Timeout == NoTimeout {
+		opts.Timeout = 0
 	}
 	if opts.MaxRetries == 0 {
 		opts.MaxRetries = DefaultMaxRetries
+	} else if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.MaxErrorBodySize == 0 {
+		opts.MaxErrorBodySize = DefaultMaxErrorBodySize
+	}
+	if opts.MaxLoggedBodySize == 0 {
+		opts.MaxLoggedBodySize = DefaultMaxLoggedBodySize
+	}
+	if opts.CorrelationIDHeader == "" {
+		opts.CorrelationIDHeader = DefaultCorrelationIDHeader
+	}
+	if opts.APIVersionHeader == "" {
+		opts.APIVersionHeader = DefaultAPIVersionHeader
+	}
+	if opts.APIVersionInPath {
+		// The version travels in the path instead
+		opts.APIVersion, baseURL = "", opts.versionedBaseURL(baseURL)
+	}
+	if opts.TenantHeader == "" {
+		opts.TenantHeader = DefaultTenantHeader
+	}
+	if opts.OrganizationHeader == "" {
+		opts.OrganizationHeader = DefaultOrganizationHeader
+	}
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
 	}
 	if opts.UserAgent == "" {
 		opts.UserAgent = fmt.Sprintf("yourapi-go-sdk/%s", Version)
 	}
+	if opts.ConnectBaseURL == "" {
+		opts.ConnectBaseURL = defaultConnectBaseURL(baseURL)
+	}
+
+	if baseURL, opts.CustomHeaders, err = opts.scope(baseURL); err != nil {
+		problems = append(problems, err)
+	}
+	transport, err := opts.transport()
+	if err != nil {
+		problems = append(problems, err)
+	}
+	if len(problems) > 0 {
+		return nil, &ConfigError{Problems: problems}
+	}
 
 	// Create HTTP client with timeout
 	httpClient := opts.HTTPClient
-	if httpClient == nil {
+	if parent != nil && httpClient == parent.options.HTTPClient && sameSocket(parent.options.BaseURL, socket) {
+		// Share the parent's transport, only changing its timeout if asked
+		shared := *parent.httpClient
+		if configured.Timeout != parent.options.Timeout {
+			shared.Timeout = opts.Timeout
+		}
+		httpClient = &shared
+	} else if httpClient == nil {
 		httpClient = &http.Client{
-			Timeout: opts.Timeout,
+			Transport:     transport,
+			Timeout:       opts.Timeout,
+			CheckRedirect: opts.Redirects.checkRedirect(),
 		}
+	} else if httpClient.CheckRedirect == nil {
+		custom := *httpClient
+		custom.CheckRedirect = opts.Redirects.checkRedirect()
+		httpClient = &custom
 	}
 
-	return &Client{
-		baseURL:       opts.BaseURL,
-		httpClient:    httpClient,
-		maxRetries:    opts.MaxRetries,
-		userAgent:     opts.UserAgent,
-		customHeaders: opts.CustomHeaders,
-		debug:         opts.Debug,
-	}, nil
-}
+	logger := opts.Logger
+	debugLogger := Logger(promotedLogger{opts.Logger})
+	if logger == nil {
+		logger = defaultLogger(opts.Debug, opts.DebugWriter, opts.DebugFormat)
+		debugLogger = defaultLogger(true, opts.DebugWriter, opts.DebugFormat)
+	}
+	if opts.TLS.InsecureSkipVerify {
+		logger.Warn("TLS certificate verification is disabled: traffic to the API can be read and altered in transit", "event", EventInsecureTLS, "baseUrl", opts.BaseURL)
+	}
+
+	var tracer Tracer
+	if opts.TracerProvider != nil {
+		tracer = opts.TracerProvider.Tracer(TracerName)
+	}
+
+	propagator := opts.Propagator
+	if propagator == nil {
+		propagator = traceContextPropagator{b3: opts.PropagateB3}
+	}
+
+	idempotencyKeys := opts.IdempotencyKeyGenerator
+	if idempotencyKeys == nil && opts.AutoIdempotencyKeys {
+		idempotencyKeys = uuidKeys
+	}
 
-// logDebug logs debug messages if debug mode is enabled
-func (c *Client) logDebug(format string, args ...interface{}) {
-	if c.debug {
-		timestamp := time.Now().Format(time.RFC3339)
-		fmt.Printf("[%s] [YourAPI] %s\n", timestamp, fmt.Sprintf(format, args...))
+	var writes *writeGroup
+	if opts.CoalesceWrites {
+		writes = newWriteGroup()
 	}
+
+	rateLimiter := opts.RateLimiter
+	if rules := newLimitRules(opts.RateLimit, opts.EndpointRateLimits); rateLimiter == nil && !rules.empty() {
+		rateLimiter = newMemoryRateLimiter(rules, opts.Clock)
+	}
+
+	var pacer *adaptivePacer
+	if opts.AdaptiveThrottling {
+		pacer = &adaptivePacer{}
+	}
+
+	gate := newConcurrencyGate(opts.MaxConcurrentRequests)
+	// Nothing has been reported yet, which RateLimitState marks with -1
+	rateLimit := &rateLimitTracker{state: RateLimitState{Limit: -1, Remaining: -1}}
+	rateWaiters := &priorityWaiters{}
+	if parent != nil {
+		rateLimiter, pacer, gate = parent.rateLimiter, parent.pacer, parent.gate
+		rateLimit, rateWaiters = parent.rateLimit, parent.rateWaiters
+	}
+
+	c := &Client{
+		baseURL:           baseURL,
+		httpClient:        httpClient,
+		maxRetries:        opts.MaxRetries,
+		backoff:           opts.RetryBackoff.withDefaults(),
+		apiKey:            opts.APIKey,
+		bearerToken:       opts.BearerToken,
+		userAgent:         opts.UserAgent,
+		customHeaders:     opts.CustomHeaders,
+		logger:            logger,
+		debugLogger:       debugLogger,
+		redactor:          newRedactor(opts.RedactHeaders, opts.RedactBodyFields),
+		debugBodies:       opts.DebugBodies,
+		maxLoggedBodySize: opts.MaxLoggedBodySize,
+		locale:            opts.Locale,
+		apiVersion:        opts.APIVersion,
+		apiVersionHeader:  opts.APIVersionHeader,
+		errorObserver:     opts.ErrorObserver,
+		errorCounter:      opts.ErrorCounter,
+		maxErrorBodySize:  opts.MaxErrorBodySize,
+		tracer:            tracer,
+		propagator:        propagator,
+		metrics:           opts.Metrics,
+		stats:             newLatencyStats(),
+		clock:             opts.Clock,
+		events:            newEventBus(),
+		correlationHeader: opts.CorrelationIDHeader,
+		slowThreshold:     opts.SlowRequestThreshold,
+		auditHook:         opts.AuditHook,
+		connTimings:       opts.ConnectionTimings,
+		skipValidation:    opts.DisableValidation,
+		rateLimiter:       rateLimiter,
+		pacer:             pacer,
+		gate:              gate,
+		rateLimit:         rateLimit,
+		rateWaiters:       rateWaiters,
+		idempotencyKeys:   idempotencyKeys,
+		writes:            writes,
+		recorder:          opts.Recorder,
+		protocol:          opts.Protocol,
+		connectBaseURL:    opts.ConnectBaseURL,
+		pathPrefix:        strings.TrimPrefix(baseURL, root),
+		options:           configured,
+	}
+	c.Services = newServices(c)
+	return c, nil
 }
 
-// buildHeaders creates headers for the request
-func (c *Client) buildHeaders(opts ClientOptions, additionalHeaders map[string]string) map[string]string {
+// buildHeaders creates headers for the request. Keys are canonical, so
+// request headers replace client ones however either was spelled.
+func (c *Client) buildHeaders(additionalHeaders map[string]string) map[string]string {
 	headers := map[string]string{
 		"User-Agent":     c.userAgent,
-		"X-SDK-Language": "go",
-		"X-SDK-Version":  Version,
+		"X-Sdk-Language": "go",
+		"X-Sdk-Version":  Version,
 		"Content-Type":   "application/json",
 	}
 
 	// Add auth headers
-	if opts.BearerToken != "" {
-		headers["Authorization"] = "Bearer " + opts.BearerToken
-	} else if opts.APIKey != "" {
-		headers["X-API-Key"] = opts.APIKey
+	if c.bearerToken != "" {
+		headers["Authorization"] = "Bearer " + c.bearerToken
+	} else if c.apiKey != "" {
+		headers["X-Api-Key"] = c.apiKey
+	}
+
+	if c.locale != "" {
+		headers["Accept-Language"] = c.locale
+	}
+	if c.apiVersion != "" {
+		headers[http.CanonicalHeaderKey(c.apiVersionHeader)] = c.apiVersion
 	}
 
 	// Add custom headers
 	for k, v := range c.customHeaders {
-		headers[k] = v
+		headers[http.CanonicalHeaderKey(k)] = v
 	}
 
 	// Add request-specific headers
 	for k, v := range additionalHeaders {
-		headers[k] = v
+		headers[http.CanonicalHeaderKey(k)] = v
 	}
 
 	return headers
 }
 
+// requestURL returns the URL of path for the call in ctx
+func (c *Client) requestURL(ctx context.Context, path string) string {
+	if ci := callFromContext(ctx); ci != nil && ci.baseURL != "" {
+		return ci.baseURL + path
+	}
+	return c.baseURL + path
+}
+
 // doRequest performs an HTTP request with retry logic
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}, headers map[string]string) (*http.Response, error) {
-	url := c.baseURL + path
+	url := c.requestURL(ctx, path)
+	headers = c.buildHeaders(headers)
 
-	var bodyReader io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewReader(jsonData)
 	}
 
+	ci := callFromContext(ctx)
+	if ci == nil {
+		ctx, ci = withCall(ctx, method, path, c.clock.Now())
+	}
+	logger, logBodies := c.callLogger(ctx)
+	maxRetries, backoffPolicy := c.retrySettings(ci)
+	httpClient := c.attemptClient(ci)
+
 	var lastErr error
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		c.logDebug("%s %s (attempt %d/%d)", method, url, attempt+1, c.maxRetries+1)
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if err := c.throttle(ctx, ci); err != nil {
+			return nil, err
+		}
+		ci.attempts = attempt + 1
+		c.events.emit(RequestStarted{Method: method, Path: path, Attempt: attempt + 1, Time: c.clock.Now()})
 
 		// Reset body reader for retries
-		if body != nil {
-			jsonData, _ := json.Marshal(body)
+		var bodyReader io.Reader
+		if jsonData != nil {
 			bodyReader = bytes.NewReader(jsonData)
 		}
 
-		req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+		reqCtx := c.conns.withConnTracking(ctx)
+		if c.connTimings {
+			reqCtx = c.withTimings(reqCtx, ci)
+		}
+		req, err := http.NewRequestWithContext(reqCtx, method, url, bodyReader)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
@@ -193,20 +605,73 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		for k, v := range headers {
 			req.Header.Set(k, v)
 		}
+		c.propagator.Inject(ctx, req.Header)
+		if id, ok := CorrelationIDFromContext(ctx); ok {
+			req.Header.Set(c.correlationHeader, id)
+		}
+
+		logger.Debug("sending request", "event", EventRequestStart, "method", method, "url", url, "attempt", attempt+1, "maxAttempts", maxRetries+1,
+			"headers", c.redactor.Header(req.Header))
+		if logBodies && jsonData != nil {
+			logger.Debug("request body", "event", EventRequestBody, "method", method, "url", url, "body", c.loggableBody(jsonData))
+		}
 
-		resp, err := c.httpClient.Do(req)
+		if c.gate != nil {
+			if ci.failFast {
+				if !c.gate.tryAcquire() {
+					return nil, &ThrottledLocallyError{Limiter: "concurrency"}
+				}
+			} else if err := c.gate.acquire(ctx, ci.priority); err != nil {
+				return nil, err
+			}
+		}
+		attemptStart := c.clock.Now()
+		if c.recorder != nil {
+			c.recorder.record(req, jsonData, attempt+1, attemptStart)
+		}
+		resp, err := httpClient.Do(req)
+		ci.attemptDurations = append(ci.attemptDurations, c.clock.Now().Sub(attemptStart))
+		if c.gate != nil {
+			if err != nil {
+				c.gate.release()
+			} else {
+				resp.Body = &releasingBody{ReadCloser: resp.Body, release: c.gate.release}
+			}
+		}
 		if err != nil {
+			// The caller gave up; report that rather than a transport failure
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			err = classifyNetworkError(err)
 			lastErr = err
-			if attempt < c.maxRetries {
-				backoff := c.calculateBackoff(attempt, nil)
-				c.logDebug("Request error, retrying after %v: %v", backoff, err)
-				time.Sleep(backoff)
+			if attempt < maxRetries {
+				backoff := c.calculateBackoff(backoffPolicy, attempt, nil)
+				logger.Info("request error, retrying", "event", EventRetry, "method", method, "url", url, "attempt", attempt+1, "backoffMs", backoff.Milliseconds(), "error", err)
+				traceRetry(ctx, attempt, backoff, err.Error())
+				c.events.emit(RetryScheduled{Method: method, Path: path, Attempt: attempt + 1, Backoff: backoff, Err: err})
+				ci.backoff += backoff
+				if err := c.sleep(ctx, backoff); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			return nil, fmt.Errorf("request failed: %w", err)
 		}
 
-		c.logDebug("Response: %d", resp.StatusCode)
+		logger.Debug("received response", "event", EventResponse, "method", method, "url", url, "status", resp.StatusCode)
+		if logBodies {
+			c.logResponseBody(logger, method, url, resp)
+		}
+		traceResponse(ctx, resp.StatusCode)
+		ci.status = resp.StatusCode
+		ci.requestID = resp.Header.Get("X-Request-Id")
+		ci.header = resp.Header
+		c.rateLimit.observe(resp.Header, c.clock.Now())
+		c.serverVersion.observe(resp.Header, c.apiVersionHeader)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			c.emitRateLimited(method, path, resp)
+		}
 
 		// Success
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
@@ -218,15 +683,20 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 			429: true, 500: true, 502: true, 503: true, 504: true,
 		}
 
-		if retryableStatuses[resp.StatusCode] && attempt < c.maxRetries {
-			backoff := c.calculateBackoff(attempt, resp)
-			c.logDebug("Retrying after %v", backoff)
-			
+		if retryableStatuses[resp.StatusCode] && attempt < maxRetries {
+			backoff := c.calculateBackoff(backoffPolicy, attempt, resp)
+			logger.Info("retryable status, retrying", "event", EventRetry, "method", method, "url", url, "attempt", attempt+1, "status", resp.StatusCode, "backoffMs", backoff.Milliseconds())
+			traceRetry(ctx, attempt, backoff, http.StatusText(resp.StatusCode))
+			c.events.emit(RetryScheduled{Method: method, Path: path, Attempt: attempt + 1, Backoff: backoff, Status: resp.StatusCode})
+
 			// Drain and close the response body
 			io.Copy(io.Discard, resp.Body)
 			resp.Body.Close()
-			
-			time.Sleep(backoff)
+
+			ci.backoff += backoff
+			if err := c.sleep(ctx, backoff); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
@@ -237,41 +707,137 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
 }
 
+// sleep waits for d on the client's clock, returning early with ctx's error
+// if it is cancelled first
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	timer := c.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
+
+// emitRateLimited reports a 429 response to event subscribers
+func (c *Client) emitRateLimited(method, path string, resp *http.Response) {
+	event := RateLimited{Method: method, Path: path}
+	event.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"), c.clock.Now())
+	event.Reset, _ = parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"))
+	c.events.emit(event)
+}
+
+// attemptClient returns the HTTP client for the attempts of a call, with
+// its WithAttemptTimeout applied
+func (c *Client) attemptClient(ci *callInfo) *http.Client {
+	if ci.attemptTimeout == 0 {
+		return c.httpClient
+	}
+	hc := *c.httpClient
+	hc.Timeout = ci.attemptTimeout
+	if hc.Timeout < 0 {
+		hc.Timeout = 0
+	}
+	return &hc
+}
+
+// retrySettings returns the retry limit and backoff for a call, applying
+// its RetryPolicy over the client's settings
+func (c *Client) retrySettings(ci *callInfo) (int, RetryBackoff) {
+	if ci.retry == nil {
+		return c.maxRetries, c.backoff
+	}
+	maxRetries := ci.retry.MaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	return maxRetries, ci.retry.Backoff.over(c.backoff)
+}
+
 // calculateBackoff calculates the backoff duration for retries
-func (c *Client) calculateBackoff(attempt int, resp *http.Response) time.Duration {
+func (c *Client) calculateBackoff(b RetryBackoff, attempt int, resp *http.Response) time.Duration {
 	// Check for Retry-After header
 	if resp != nil {
-		retryAfter := resp.Header.Get("Retry-After")
-		if retryAfter != "" {
-			// Try parsing as seconds
-			if seconds, err := strconv.Atoi(retryAfter); err == nil {
-				return time.Duration(seconds) * time.Second
-			}
-			// Try parsing as date
-			if retryDate, err := time.Parse(time.RFC1123, retryAfter); err == nil {
-				duration := time.Until(retryDate)
-				if duration > 0 {
-					return duration
-				}
-			}
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), c.clock.Now()); ok {
+			return b.retryAfter(retryAfter)
 		}
 	}
 
-	// Exponential backoff: 2^attempt seconds, capped at 8 seconds
-	backoff := math.Min(math.Pow(2, float64(attempt)), 8)
-	return time.Duration(backoff) * time.Second
+	// Exponential backoff: Base * 2^attempt, capped at Max (1s doubling to 8s
+	// by default)
+	return b.exponential(attempt)
+}
+
+// parseRetryAfter parses a Retry-After header given either as seconds or as
+// an HTTP date, relative to now
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	// Try parsing as seconds
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		// Saturate rather than overflow on absurd values
+		if seconds > int64(math.MaxInt64/time.Second) {
+			return time.Duration(math.MaxInt64), true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	// Try parsing as date
+	if retryDate, err := http.ParseTime(value); err == nil {
+		duration := retryDate.Sub(now)
+		if duration > 0 {
+			return duration, true
+		}
+	}
+	return 0, false
+}
+
+// parseRateLimitReset parses an X-RateLimit-Reset header holding a unix
+// timestamp
+func parseRateLimitReset(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
 }
 
 // parseError parses an error response
 func (c *Client) parseError(resp *http.Response) error {
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return &APIError{
+	// Read one byte past the limit to detect truncation
+	bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, c.maxErrorBodySize+1))
+	truncated := int64(len(bodyBytes)) > c.maxErrorBodySize
+	if truncated {
+		bodyBytes = bodyBytes[:c.maxErrorBodySize]
+	}
+
+	var err error
+	if readErr != nil {
+		err = &APIError{
 			Message: "Failed to read error response",
 			Status:  resp.StatusCode,
 		}
+	} else {
+		err = c.decodeError(resp, bodyBytes)
 	}
 
+	if apiErr, ok := AsAPIError(err); ok {
+		apiErr.RawBody = bodyBytes
+		apiErr.Truncated = truncated
+		apiErr.Header = resp.Header.Clone()
+		apiErr.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"), c.clock.Now())
+		apiErr.RateLimitReset, _ = parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"))
+	}
+	return err
+}
+
+// decodeError decodes the body of an error response
+func (c *Client) decodeError(resp *http.Response, bodyBytes []byte) error {
 	var errorBody map[string]interface{}
 	if err := json.Unmarshal(bodyBytes, &errorBody); err != nil {
 		return &APIError{
@@ -280,105 +846,135 @@ func (c *Client) parseError(resp *http.Response) error {
 		}
 	}
 
-	apiErr := &APIError{
-		Status: resp.StatusCode,
-		Body:   errorBody,
+	apiErr := newAPIError(resp.StatusCode, errorBody, c.locale)
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-Id")
 	}
 
-	if msg, ok := errorBody["message"].(string); ok {
-		apiErr.Message = msg
-	} else {
-		apiErr.Message = "Request failed"
-	}
-
-	if code, ok := errorBody["code"].(string); ok {
-		apiErr.Code = code
+	if isProblemJSON(resp.Header.Get("Content-Type")) {
+		apiErr.applyProblem(errorBody)
 	}
 
-	if details, ok := errorBody["details"]; ok {
-		apiErr.Details = details
-	}
-
-	if requestID, ok := errorBody["requestId"].(string); ok {
-		apiErr.RequestID = requestID
-	} else if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
-		apiErr.RequestID = requestID
+	fields := parseFieldErrors(errorBody)
+	if resp.StatusCode == http.StatusUnprocessableEntity ||
+		(resp.StatusCode == http.StatusBadRequest && len(fields) > 0) {
+		return &ValidationError{APIError: apiErr, Fields: fields}
 	}
 
 	return apiErr
 }
 
-// Get performs a GET request
-func (c *Client) Get(ctx context.Context, path string, result interface{}) error {
-	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, nil)
-	if err != nil {
+// send performs a request and decodes the response into result
+func (c *Client) send(ctx context.Context, method, path string, body interface{}, headers map[string]string, result interface{}, ro requestOptions) error {
+	if ro.err != nil {
+		return ro.err
+	}
+	if err := c.validateRequest(body); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return c.parseError(resp)
+	template := ""
+	if ro.pathParams != nil {
+		expanded, err := ExpandPath(path, ro.pathParams)
+		if err != nil {
+			return err
+		}
+		template, path = path, expanded
 	}
-
-	if result != nil && resp.StatusCode != http.StatusNoContent {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+	if ro.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ro.timeout)
+		defer cancel()
+	}
+	path = ro.withQuery(path)
+	headers = ro.withHeaders(headers)
+
+	key := ro.idempotencyKey
+	if key == "" && method == http.MethodPost && c.idempotencyKeys != nil {
+		var err error
+		if key, err = c.idempotencyKeys.IdempotencyKey(ctx, method, path, body); err != nil {
+			return fmt.Errorf("failed to generate idempotency key: %w", err)
 		}
 	}
-
-	return nil
-}
-
-// Post performs a POST request
-func (c *Client) Post(ctx context.Context, path string, body interface{}, result interface{}, idempotencyKey string) error {
-	headers := make(map[string]string)
-	if idempotencyKey != "" {
-		headers["Idempotency-Key"] = idempotencyKey
+	if key != "" {
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[IdempotencyKeyHeader] = key
 	}
 
-	resp, err := c.doRequest(ctx, http.MethodPost, path, body, headers)
-	if err != nil {
-		return err
+	ctx, ci := withCall(ctx, method, path, c.clock.Now())
+	ci.debug = ro.debug
+	ci.failFast = ro.failFast
+	ci.priority = ro.priority
+	ci.retry = ro.retry
+	ci.baseURL = ro.baseURL
+	if ci.baseURL != "" && !ro.rawBaseURL {
+		// Another host serves the same API, versioned and scoped alike
+		ci.baseURL += c.pathPrefix
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return c.parseError(resp)
+	ci.attemptTimeout = ro.attemptTimeout
+	if template != "" {
+		// Named parameters become {id}, matching the heuristic templates
+		// and the EndpointRateLimits rules
+		ci.template = normalizeTemplate(template)
 	}
-
-	if result != nil && resp.StatusCode != http.StatusNoContent {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
-		}
+	if c.metrics != nil {
+		c.metrics.RequestStarted(method, ci.template)
 	}
 
-	return nil
-}
+	c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
 
-// Patch performs a PATCH request
-func (c *Client) Patch(ctx context.Context, path string, body interface{}, result interface{}) error {
-	resp, err := c.doRequest(ctx, http.MethodPatch, path, body, nil)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+	ctx, span := c.startSpan(ctx, method, path)
+	err := c.roundTrip(ctx, method, path, body, headers, result)
+	endSpan(span, err)
 
-	if resp.StatusCode >= 400 {
-		return c.parseError(resp)
+	duration := c.clock.Now().Sub(ci.start)
+	if apiErr, ok := AsAPIError(err); ok && key != "" {
+		apiErr.IdempotencyKey = key
 	}
-
-	if result != nil && resp.StatusCode != http.StatusNoContent {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+	if ro.metadata != nil {
+		*ro.metadata = ResponseMetadata{
+			Status:         ci.status,
+			Header:         ci.header,
+			RequestID:      ci.requestID,
+			APIVersion:     ci.header.Get(c.apiVersionHeader),
+			IdempotencyKey: key,
+			Attempts:       ci.attempts,
+			Duration:       duration,
 		}
 	}
-
-	return nil
+	c.stats.record(method, ci.template, duration, err != nil)
+	c.events.emit(RequestFinished{Method: method, Path: path, Status: ci.status, Attempts: ci.attempts, Duration: duration, Timings: ci.connTimings(), Err: err})
+	if c.slowThreshold > 0 && duration >= c.slowThreshold {
+		c.reportSlowRequest(ci, duration, err)
+	}
+	if c.auditHook != nil && isMutating(method) {
+		c.audit(ctx, ci, headers, duration, err)
+	}
+	if c.metrics != nil {
+		c.recordMetrics(ci, err)
+	}
+	if err != nil {
+		if c.errorObserver != nil {
+			c.errorObserver(ctx, method, path, err)
+		}
+		if c.errorCounter != nil {
+			c.errorCounter(errorMetricKey(method, ci.template, err))
+		}
+	}
+	return err
 }
 
-// Put performs a PUT request
-func (c *Client) Put(ctx context.Context, path string, body interface{}, result interface{}) error {
-	resp, err := c.doRequest(ctx, http.MethodPut, path, body, nil)
+// roundTrip executes the request and decodes a successful response
+func (c *Client) roundTrip(ctx context.Context, method, path string, body interface{}, headers map[string]string, result interface{}) error {
+	var resp *http.Response
+	var err error
+	if c.writes != nil && isMutating(method) {
+		resp, err = c.doCoalesced(ctx, method, path, body, headers)
+	} else {
+		resp, err = c.doRequest(ctx, method, path, body, headers)
+	}
 	if err != nil {
 		return err
 	}
@@ -388,6 +984,11 @@ func (c *Client) Put(ctx context.Context, path string, body interface{}, result
 		return c.parseError(resp)
 	}
 
+	if resp.StatusCode >= 300 {
+		io.Copy(io.Discard, resp.Body)
+		return redirectError(resp)
+	}
+
 	if result != nil && resp.StatusCode != http.StatusNoContent {
 		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
 			return fmt.Errorf("failed to decode response: %w", err)
@@ -397,39 +998,66 @@ func (c *Client) Put(ctx context.Context, path string, body interface{}, result
 	return nil
 }
 
-// Delete performs a DELETE request
-func (c *Client) Delete(ctx context.Context, path string) error {
-	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil, nil)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+// Get performs a GET request
+func (c *Client) Get(ctx context.Context, path string, result interface{}, opts ...RequestOption) error {
+	return c.send(ctx, http.MethodGet, path, nil, nil, result, newRequestOptions(opts))
+}
 
-	if resp.StatusCode >= 400 {
-		return c.parseError(resp)
-	}
+// Post performs a POST request
+func (c *Client) Post(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	return c.send(ctx, http.MethodPost, path, body, nil, result, newRequestOptions(opts))
+}
 
-	return nil
+// Patch performs a PATCH request
+func (c *Client) Patch(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	return c.send(ctx, http.MethodPatch, path, body, nil, result, newRequestOptions(opts))
+}
+
+// Put performs a PUT request
+func (c *Client) Put(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	return c.send(ctx, http.MethodPut, path, body, nil, result, newRequestOptions(opts))
+}
+
+// Delete performs a DELETE request
+func (c *Client) Delete(ctx context.Context, path string, opts ...RequestOption) error {
+	return c.send(ctx, http.MethodDelete, path, nil, nil, nil, newRequestOptions(opts))
 }
 
 // PaginateCursor provides cursor-based pagination using a callback function
-func (c *Client) PaginateCursor(ctx context.Context, path string, callback func(interface{}) error) error {
+func (c *Client) PaginateCursor(ctx context.Context, path string, callback func(interface{}) error, opts ...RequestOption) error {
+	ro := newRequestOptions(opts)
 	cursor := ""
 	hasMore := true
+	var paused time.Duration
+	// seen guards against servers that hand back a cursor already visited,
+	// which would otherwise loop forever
+	seen := map[string]bool{"": true}
 
 	for hasMore {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		fullPath := path
 		if cursor != "" {
 			if bytes.Contains([]byte(path), []byte("?")) {
-				fullPath = fmt.Sprintf("%s&cursor=%s", path, cursor)
+				fullPath = fmt.Sprintf("%s&cursor=%s", path, url.QueryEscape(cursor))
 			} else {
-				fullPath = fmt.Sprintf("%s?cursor=%s", path, cursor)
+				fullPath = fmt.Sprintf("%s?cursor=%s", path, url.QueryEscape(cursor))
 			}
 		}
 
 		var response CursorPaginatedResponse
-		if err := c.Get(ctx, fullPath, &response); err != nil {
-			return err
+		if err := c.Get(ctx, fullPath, &response, opts...); err != nil {
+			pause, ok := c.rateLimitPause(ro, err, paused)
+			if !ok {
+				return err
+			}
+			c.logger.Info("rate limited, pausing pagination", "method", http.MethodGet, "path", fullPath, "pauseMs", pause.Milliseconds())
+			if err := c.sleep(ctx, pause); err != nil {
+				return err
+			}
+			paused += pause
+			continue
 		}
 
 		for _, item := range response.Items {
@@ -439,23 +1067,41 @@ func (c *Client) PaginateCursor(ctx context.Context, path string, callback func(
 		}
 
 		hasMore = response.HasMore
-		if response.NextCursor != nil {
-			cursor = *response.NextCursor
-		} else {
+		if response.NextCursor == nil {
 			break
 		}
+		cursor = *response.NextCursor
+		if hasMore && seen[cursor] {
+			return fmt.Errorf("pagination cursor %q was already returned", cursor)
+		}
+		seen[cursor] = true
 	}
 
 	return nil
 }
 
+// rateLimitPause returns how long a paginated walk should pause before
+// requesting the same page again after err, if WithPauseOnRateLimit allows it
+func (c *Client) rateLimitPause(ro requestOptions, err error, paused time.Duration) (time.Duration, bool) {
+	if !ro.pauseOnRateLimit || !errors.Is(err, ErrRateLimited) {
+		return 0, false
+	}
+	pause, ok := retryAfter(err, c.clock.Now())
+	if !ok {
+		pause = defaultRateLimitPause
+	}
+	if ro.maxPause > 0 && paused+pause > ro.maxPause {
+		return 0, false
+	}
+	return pause, true
+}
+
 // GetAllCursor fetches all pages and returns them as a slice
-func (c *Client) GetAllCursor(ctx context.Context, path string) ([]interface{}, error) {
+func (c *Client) GetAllCursor(ctx context.Context, path string, opts ...RequestOption) ([]interface{}, error) {
 	var items []interface{}
 	err := c.PaginateCursor(ctx, path, func(item interface{}) error {
 		items = append(items, item)
 		return nil
-	})
+	}, opts...)
 	return items, err
 }
-
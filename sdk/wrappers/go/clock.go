@@ -0,0 +1,32 @@
+package yourapi
+
+import "time"
+
+// Clock abstracts time so retry backoff, Retry-After handling and latency
+// measurement can be driven deterministically in tests and simulations
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// NewTimer creates a timer that fires once after d
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single-shot timer created by a Clock
+type Timer interface {
+	// C returns the channel the timer fires on
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, reporting whether it was active
+	Stop() bool
+}
+
+// realClock is the Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.t.C }
+func (t realTimer) Stop() bool          { return t.t.Stop() }
@@ -0,0 +1,59 @@
+// Command saligen-go generates the SDK's typed Go code from an OpenAPI
+// document:
+//
+//	go run ./cmd/saligen-go -spec ../../openapi.json -out .
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/devdraft/devdraft-sdk-go/internal/codegen"
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+)
+
+func main() {
+	spec := flag.String("spec", "openapi.json", "path of the OpenAPI document")
+	out := flag.String("out", ".", "root directory of the SDK module")
+	module := flag.String("module", "", "import path of the SDK module (default: read from go.mod in -out)")
+	models := flag.String("models", "models", "directory and package name of the generated models")
+	templates := flag.String("templates", "", "directory of templates overriding the built-in ones (optional)")
+	preserveUnknown := flag.Bool("preserve-unknown", false, "keep JSON members missing from the spec in the models and send them back")
+	connectPackage := flag.String("connect-package", "", "protobuf package of the API's Connect services, generating a Connect transport (default: the spec's x-connect-package)")
+	flag.Parse()
+
+	if err := run(*spec, *out, *module, *models, *templates, *connectPackage, *preserveUnknown); err != nil {
+		fmt.Fprintln(os.Stderr, "saligen-go:", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, out, module, models, templates, connectPackage string, preserveUnknown bool) error {
+	doc, err := openapi.Load(specPath)
+	if err != nil {
+		return err
+	}
+	if module == "" {
+		if module, err = codegen.ModulePath(filepath.Join(out, "go.mod")); err != nil {
+			return err
+		}
+	}
+	cfg := codegen.Config{
+		Spec:            doc,
+		ModulePath:      module,
+		ModelsPackage:   models,
+		PreserveUnknown: preserveUnknown,
+		ConnectPackage:  connectPackage,
+	}
+	if templates != "" {
+		cfg.Templates = os.DirFS(templates)
+	}
+	files, err := codegen.Generate(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = codegen.WriteFiles(out, files)
+	return err
+}
@@ -0,0 +1,271 @@
+// Command saligen generates a complete Go SDK, client runtime included,
+// from an OpenAPI document in JSON or YAML:
+//
+//	saligen generate --lang go --spec api.yaml --out ./sdk
+//
+// Runs are deterministic and only rewrite files whose content changed, so
+// regenerating after a spec change yields a diff of just that change.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/devdraft/devdraft-sdk-go/internal/codegen"
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+)
+
+const usage = `Usage: saligen generate --lang go --spec FILE --out DIR [flags]
+
+Generates a Go SDK from an OpenAPI 3 document in JSON or YAML: the client
+runtime, models, services and webhook types.
+
+Flags:
+`
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "generate" {
+		fmt.Fprint(os.Stderr, usage)
+		generateFlags(&options{}).PrintDefaults()
+		os.Exit(2)
+	}
+	if err := generate(os.Args[2:], os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "saligen:", err)
+		os.Exit(1)
+	}
+}
+
+// options are the flags of saligen generate
+type options struct {
+	lang            string
+	spec            string
+	out             string
+	module          string
+	models          string
+	runtime         string
+	templates       string
+	connectPackage  string
+	preserveUnknown bool
+}
+
+func generateFlags(o *options) *flag.FlagSet {
+	flags := flag.NewFlagSet("saligen generate", flag.ContinueOnError)
+	flags.StringVar(&o.lang, "lang", "go", "language to generate; only go is supported")
+	flags.StringVar(&o.spec, "spec", "", "path of the OpenAPI document, JSON or YAML (required)")
+	flags.StringVar(&o.out, "out", "", "directory to write the SDK module to (required)")
+	flags.StringVar(&o.module, "module", "", "import path of the generated module (default: read from go.mod in --out, else the template module's)")
+	flags.StringVar(&o.models, "models", "models", "directory and package name of the generated models")
+	flags.StringVar(&o.runtime, "runtime", defaultRuntime(), "root of the SDK module whose client runtime is copied")
+	flags.StringVar(&o.templates, "templates", "", "directory of templates overriding the built-in ones (optional)")
+	flags.StringVar(&o.connectPackage, "connect-package", "", "protobuf package of the API's Connect services, generating a Connect transport (default: the spec's x-connect-package)")
+	flags.BoolVar(&o.preserveUnknown, "preserve-unknown", false, "keep JSON members missing from the spec in the models and send them back")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// defaultRuntime locates the SDK module this command was built from
+func defaultRuntime() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
+
+func generate(args []string, stdout io.Writer) error {
+	var o options
+	if err := generateFlags(&o).Parse(args); err != nil {
+		return err
+	}
+	switch {
+	case o.lang != "go":
+		return fmt.Errorf("unsupported language %q: only go is supported", o.lang)
+	case o.spec == "":
+		return errors.New("--spec is required")
+	case o.out == "":
+		return errors.New("--out is required")
+	}
+
+	doc, err := openapi.Load(o.spec)
+	if err != nil {
+		return err
+	}
+	runtimeModule, err := codegen.ModulePath(filepath.Join(o.runtime, "go.mod"))
+	if err != nil {
+		return fmt.Errorf("locating the client runtime (set --runtime): %w", err)
+	}
+	module := o.module
+	if module == "" {
+		if module, err = codegen.ModulePath(filepath.Join(o.out, "go.mod")); err != nil {
+			module = runtimeModule
+		}
+	}
+
+	files, err := runtimeFiles(o.runtime, o.out, runtimeModule, module)
+	if err != nil {
+		return err
+	}
+	cfg := codegen.Config{
+		Spec:            doc,
+		ModulePath:      module,
+		ModelsPackage:   o.models,
+		PreserveUnknown: o.preserveUnknown,
+		ConnectPackage:  o.connectPackage,
+	}
+	if o.templates != "" {
+		cfg.Templates = os.DirFS(o.templates)
+	}
+	generated, err := codegen.Generate(cfg)
+	if err != nil {
+		return err
+	}
+	files = append(files, generated...)
+
+	written, err := codegen.WriteFiles(o.out, files)
+	if err != nil {
+		return err
+	}
+	removed, err := removeStale(o.out, files)
+	if err != nil {
+		return err
+	}
+	for _, p := range written {
+		fmt.Fprintln(stdout, "wrote", p)
+	}
+	for _, p := range removed {
+		fmt.Fprintln(stdout, "removed", p)
+	}
+	fmt.Fprintf(stdout, "%d files written, %d removed, %d unchanged\n", len(written), len(removed), len(files)-len(written))
+	return nil
+}
+
+// skippedDirs hold the generator and its tests rather than SDK code
+var skippedDirs = map[string]bool{
+	"cmd":              true,
+	"integration":      true,
+	"testdata":         true,
+	"internal/codegen": true,
+}
+
+// runtimeFiles returns the hand-written SDK sources under root, with import
+// paths moved from the runtime's module to module. Generated files are left
+// out, since this run produces them afresh.
+func runtimeFiles(root, out, runtimeModule, module string) ([]codegen.File, error) {
+	absOut, err := filepath.Abs(out)
+	if err != nil {
+		return nil, err
+	}
+	var files []codegen.File
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if skippedDirs[rel] || (rel != "." && strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			// An output directory inside the runtime is not copied into itself
+			if abs, err := filepath.Abs(path); err == nil && rel != "." && abs == absOut {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		switch {
+		case rel == "go.mod":
+			// An existing go.mod belongs to the output module
+			if _, err := os.Stat(filepath.Join(out, "go.mod")); err == nil {
+				return nil
+			}
+		case rel == "README.md":
+		case !strings.HasSuffix(name, ".go"):
+			return nil
+		case strings.HasSuffix(name, "_test.go"), strings.HasSuffix(name, "_gen.go"), rel == "generate.go":
+			// generate.go points go generate at the template's own spec
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if module != runtimeModule {
+			content = rewriteModule(rel, content, runtimeModule, module)
+		}
+		files = append(files, codegen.File{Path: rel, Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// rewriteModule moves a copied file from the runtime's module path to the
+// output's
+func rewriteModule(rel string, content []byte, from, to string) []byte {
+	switch {
+	case rel == "go.mod":
+		return bytes.Replace(content, []byte("module "+from), []byte("module "+to), 1)
+	case strings.HasSuffix(rel, ".go"):
+		return bytes.ReplaceAll(content, []byte(`"`+from), []byte(`"`+to))
+	}
+	return content
+}
+
+// removeStale deletes generated files left in out by earlier runs that this
+// run no longer produces, e.g. the example of a removed operation. Only
+// files carrying the generated-code header are touched.
+func removeStale(out string, files []codegen.File) ([]string, error) {
+	keep := make(map[string]bool, len(files))
+	for _, f := range files {
+		keep[f.Path] = true
+	}
+	var removed []string
+	err := filepath.WalkDir(out, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !(strings.HasSuffix(path, "_gen.go") || strings.HasSuffix(path, "_test.go")) {
+			return err
+		}
+		rel, err := filepath.Rel(out, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if keep[rel] {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(content, []byte(codegen.Header)) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed = append(removed, rel)
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,116 @@
+package yourapi
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// concurrencyGate limits the number of requests in flight, queueing the
+// rest by priority and then arrival order
+type concurrencyGate struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	waiters [numPriorities][]chan struct{}
+	queued  atomic.Int64
+}
+
+// newConcurrencyGate returns a gate admitting limit requests at a time, or
+// nil if limit is not positive
+func newConcurrencyGate(limit int) *concurrencyGate {
+	if limit <= 0 {
+		return nil
+	}
+	return &concurrencyGate{limit: limit}
+}
+
+// acquire blocks until a slot is free or ctx is done
+func (g *concurrencyGate) acquire(ctx context.Context, p Priority) error {
+	g.mu.Lock()
+	if g.active < g.limit && !g.hasWaiters() {
+		g.active++
+		g.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	level := p.level()
+	g.waiters[level] = append(g.waiters[level], ready)
+	g.queued.Add(1)
+	g.mu.Unlock()
+
+	select {
+	case <-ready:
+		g.queued.Add(-1)
+		return nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		for i, w := range g.waiters[level] {
+			if w == ready {
+				g.waiters[level] = append(g.waiters[level][:i], g.waiters[level][i+1:]...)
+				g.queued.Add(-1)
+				return ctx.Err()
+			}
+		}
+		// The slot was handed over while we were giving up; pass it on
+		g.queued.Add(-1)
+		g.releaseLocked()
+		return ctx.Err()
+	}
+}
+
+// tryAcquire takes a slot only if one is free without queueing
+func (g *concurrencyGate) tryAcquire() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.active < g.limit && !g.hasWaiters() {
+		g.active++
+		return true
+	}
+	return false
+}
+
+// hasWaiters reports whether any request is queued; g.mu must be held
+func (g *concurrencyGate) hasWaiters() bool {
+	for _, w := range g.waiters {
+		if len(w) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// release frees a slot, handing it to the longest-waiting request of the
+// highest waiting priority, if any
+func (g *concurrencyGate) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.releaseLocked()
+}
+
+func (g *concurrencyGate) releaseLocked() {
+	for level := range g.waiters {
+		if len(g.waiters[level]) > 0 {
+			ready := g.waiters[level][0]
+			g.waiters[level] = g.waiters[level][1:]
+			close(ready)
+			return
+		}
+	}
+	g.active--
+}
+
+// releasingBody frees its concurrency slot when the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
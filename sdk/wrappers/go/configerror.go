@@ -0,0 +1,144 @@
+package yourapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConfigError reports every problem NewClient found in its options, so
+// they can all be fixed at once instead of surfacing one at a time or at
+// the first request. Problems keeps each one, for errors.Is and errors.As.
+type ConfigError struct {
+	Problems []error
+}
+
+func (e *ConfigError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid client options: " + e.Problems[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid client options (%d problems):", len(e.Problems))
+	for _, p := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(p.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the problems, so errors.Is and errors.As look into them
+func (e *ConfigError) Unwrap() []error {
+	return e.Problems
+}
+
+// validate checks the options that need no parsing or I/O, returning
+// every problem found
+func (o *ClientOptions) validate() []error {
+	var problems []error
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if o.APIKey != "" && o.BearerToken != "" {
+		add("APIKey and BearerToken are both set; set only one, or use WithAPIKey or WithBearerToken which replace each other")
+	}
+	if o.Timeout < 0 && o.Timeout != NoTimeout {
+		add("Timeout is negative (%s); use NoTimeout to disable it", o.Timeout)
+	}
+	for _, d := range []struct {
+		field string
+		value time.Duration
+	}{
+		{"RetryBackoff.Base", o.RetryBackoff.Base},
+		{"RetryBackoff.Max", o.RetryBackoff.Max},
+		{"RetryBackoff.MaxRetryAfter", o.RetryBackoff.MaxRetryAfter},
+		{"SlowRequestThreshold", o.SlowRequestThreshold},
+	} {
+		if d.value < 0 {
+			add("%s is negative (%s)", d.field, d.value)
+		}
+	}
+	if o.MaxConcurrentRequests < 0 {
+		add("MaxConcurrentRequests is negative (%d); leave it zero for no limit", o.MaxConcurrentRequests)
+	}
+	if o.MaxErrorBodySize < 0 {
+		add("MaxErrorBodySize is negative (%d)", o.MaxErrorBodySize)
+	}
+	if o.MaxLoggedBodySize < 0 {
+		add("MaxLoggedBodySize is negative (%d)", o.MaxLoggedBodySize)
+	}
+	if o.Protocol != ProtocolREST && o.Protocol != ProtocolConnect {
+		add("unknown Protocol %d", o.Protocol)
+	}
+
+	names := make([]string, 0, len(o.CustomHeaders))
+	for name := range o.CustomHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !validHeaderName(name) {
+			add("CustomHeaders: invalid header name %q", name)
+		} else if !validHeaderValue(o.CustomHeaders[name]) {
+			add("CustomHeaders[%q]: value contains control characters", name)
+		}
+	}
+	for _, h := range []struct{ field, name string }{
+		{"CorrelationIDHeader", o.CorrelationIDHeader},
+		{"APIVersionHeader", o.APIVersionHeader},
+		{"TenantHeader", o.TenantHeader},
+		{"OrganizationHeader", o.OrganizationHeader},
+	} {
+		if h.name != "" && !validHeaderName(h.name) {
+			add("%s: invalid header name %q", h.field, h.name)
+		}
+	}
+	for _, name := range o.RedactHeaders {
+		if !validHeaderName(name) {
+			add("RedactHeaders: invalid header name %q", name)
+		}
+	}
+	// Values sent as headers; credentials are not echoed
+	for _, v := range []struct{ field, value string }{
+		{"APIKey", o.APIKey},
+		{"BearerToken", o.BearerToken},
+		{"UserAgent", o.UserAgent},
+		{"Locale", o.Locale},
+		{"APIVersion", o.APIVersion},
+		{"Tenant", o.Tenant},
+		{"Organization", o.Organization},
+	} {
+		if !validHeaderValue(v.value) {
+			add("%s contains control characters, which cannot be sent in a header", v.field)
+		}
+	}
+	return problems
+}
+
+// validHeaderName reports whether name is an RFC 9110 token
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validHeaderValue reports whether v holds no control characters but tabs
+func validHeaderValue(v string) bool {
+	for i := 0; i < len(v); i++ {
+		if c := v[i]; c < ' ' && c != '\t' || c == 0x7f {
+			return false
+		}
+	}
+	return true
+}
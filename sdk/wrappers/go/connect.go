@@ -0,0 +1,316 @@
+package yourapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Protocol is the wire protocol the generated service methods speak
+type Protocol int
+
+const (
+	// ProtocolREST calls the HTTP/JSON API described by the OpenAPI spec
+	ProtocolREST Protocol = iota
+	// ProtocolConnect calls the API's Connect services with the Connect
+	// unary protocol and JSON messages, which gRPC deployments fronted by
+	// Connect or a gRPC-Web/Connect proxy also accept. It needs an SDK
+	// generated with a Connect package.
+	ProtocolConnect
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolREST:
+		return "rest"
+	case ProtocolConnect:
+		return "connect"
+	}
+	return "Protocol(" + strconv.Itoa(int(p)) + ")"
+}
+
+// connectProtocolVersion is sent in Connect-Protocol-Version
+const connectProtocolVersion = "1"
+
+// defaultConnectBaseURL returns the origin of baseURL, where Connect
+// procedures are served by default
+func defaultConnectBaseURL(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// sendConnect calls a Connect unary procedure, e.g.
+// "/acme.v1.CustomersService/GetCustomer", through the same pipeline as
+// send, so auth, retries, limits and observability apply alike. The request
+// message holds the members of body, the query parameters of params and
+// fields, which carries the path parameters. The caller has validated
+// params. Header parameters are sent as
+// headers, as over REST.
+func (c *Client) sendConnect(ctx context.Context, procedure string, body, params interface{}, fields map[string]interface{}, headers map[string]string, result interface{}, ro requestOptions) error {
+	if ro.err != nil {
+		return ro.err
+	}
+	if err := c.validateRequest(body); err != nil {
+		return err
+	}
+	msg, err := connectMessage(body, params, fields)
+	if err != nil {
+		return err
+	}
+
+	h := make(map[string]string, len(headers)+2)
+	for k, v := range headers {
+		h[k] = v
+	}
+	h["Connect-Protocol-Version"] = connectProtocolVersion
+	if timeout, ok := connectTimeout(ctx, ro.timeout, c.clock.Now()); ok {
+		h["Connect-Timeout-Ms"] = strconv.FormatInt(timeout.Milliseconds(), 10)
+	}
+	if ro.baseURL == "" {
+		ro.baseURL = c.connectBaseURL
+	}
+	ro.rawBaseURL = true
+
+	var raw json.RawMessage
+	if err := c.send(ctx, http.MethodPost, procedure, msg, h, &raw, ro); err != nil {
+		return err
+	}
+	if result == nil || len(raw) == 0 {
+		return nil
+	}
+	return connectUnmarshal(raw, result)
+}
+
+// connectTimeout returns the time left for the call, from ctx's deadline or
+// the per-request timeout, whichever comes first
+func connectTimeout(ctx context.Context, timeout time.Duration, now time.Time) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if ok && (timeout <= 0 || deadline.Sub(now) < timeout) {
+		timeout = deadline.Sub(now)
+	}
+	if timeout <= 0 {
+		return 0, false
+	}
+	return timeout, true
+}
+
+// connectMessage builds the JSON request message of a Connect call
+func connectMessage(body, params interface{}, fields map[string]interface{}) (map[string]json.RawMessage, error) {
+	msg := make(map[string]json.RawMessage)
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		if t := bytes.TrimSpace(data); len(t) > 0 && t[0] == '{' {
+			if err := json.Unmarshal(t, &msg); err != nil {
+				return nil, err
+			}
+		} else if string(t) != "null" {
+			// A body that is not an object becomes the body field
+			msg["body"] = t
+		}
+	}
+	if err := addQueryFields(msg, params); err != nil {
+		return nil, err
+	}
+	for name, v := range fields {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		msg[name] = data
+	}
+	return msg, nil
+}
+
+// addQueryFields adds the set fields of a Params struct carrying url tags
+// to msg, under their query parameter names
+func addQueryFields(msg map[string]json.RawMessage, params interface{}) error {
+	v := reflect.ValueOf(params)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := parseQueryTag(t.Field(i))
+		if !ok || !t.Field(i).IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		data, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return err
+		}
+		msg[tag.name] = data
+	}
+	return nil
+}
+
+// connectUnmarshal decodes a Connect response message. Connect servers
+// encode int64 and uint64 fields as JSON strings, which encoding/json only
+// reads into string fields, so the message is first decoded generically and
+// every numeric string whose field in result is a number is unquoted.
+func connectUnmarshal(data []byte, result interface{}) error {
+	var tree interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if _, changed := unquoteNumbers(tree, reflect.TypeOf(result)); changed {
+		var err error
+		if data, err = json.Marshal(tree); err != nil {
+			return err
+		}
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unquoteNumbers walks v, decoded with UseNumber, alongside t, the type it
+// will be decoded into. It returns v with numeric strings replaced where t
+// expects a number, and whether it replaced any; maps and slices are
+// updated in place. Types decoding themselves are left alone.
+func unquoteNumbers(v interface{}, t reflect.Type) (interface{}, bool) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return v, false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if n, ok := numericString(v); ok {
+			return n, true
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := v.([]interface{})
+		if !ok {
+			return v, false
+		}
+		changed := false
+		for i, item := range items {
+			var c bool
+			if items[i], c = unquoteNumbers(item, t.Elem()); c {
+				changed = true
+			}
+		}
+		return v, changed
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return v, false
+		}
+		changed := false
+		for k, item := range obj {
+			var c bool
+			if obj[k], c = unquoteNumbers(item, t.Elem()); c {
+				changed = true
+			}
+		}
+		return v, changed
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return v, false
+		}
+		fields := jsonFields(t)
+		changed := false
+		for k, item := range obj {
+			ft, ok := fields[k]
+			if !ok {
+				// encoding/json falls back to a case-insensitive match
+				for name, typ := range fields {
+					if strings.EqualFold(name, k) {
+						ft, ok = typ, true
+						break
+					}
+				}
+			}
+			if !ok {
+				continue
+			}
+			var c bool
+			if obj[k], c = unquoteNumbers(item, ft); c {
+				changed = true
+			}
+		}
+		return v, changed
+	}
+	return v, false
+}
+
+// jsonFields returns the types of the JSON members of struct type t by
+// name, including those promoted from embedded structs. Fields with the
+// ",string" option are left out, since encoding/json unquotes them itself.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for n, typ := range jsonFields(ft) {
+					if _, ok := fields[n]; !ok {
+						fields[n] = typ
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() || strings.Contains(","+opts+",", ",string,") {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// numericString returns v as a number if it is a string holding one
+func numericString(v interface{}) (json.Number, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return "", false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return "", false
+	}
+	return json.Number(s), true
+}
@@ -0,0 +1,69 @@
+package yourapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type connectItem struct {
+	N     int64   `json:"n"`
+	U     *uint64 `json:"u,omitempty"`
+	Label string  `json:"label"`
+}
+
+type connectPage struct {
+	Items  []connectItem     `json:"items"`
+	Totals map[string]int64  `json:"totals"`
+	Quoted int64             `json:"quoted,string"`
+	Raw    json.RawMessage   `json:"raw"`
+	Any    interface{}       `json:"any"`
+	Named  map[string]string `json:"named"`
+	connectEmbedded
+}
+
+type connectEmbedded struct {
+	Total int64 `json:"total"`
+}
+
+func TestConnectUnmarshal(t *testing.T) {
+	for _, n := range []int{0, 1, 32, 33, 500} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			items := make([]string, n)
+			for i := range items {
+				items[i] = fmt.Sprintf(`{"n":"%d","u":"%d","label":"7"}`, i, i*2)
+			}
+			data := `{"items":[` + strings.Join(items, ",") + `],` +
+				`"totals":{"a":"9007199254740993"},"quoted":"5","raw":{"x":"1"},` +
+				`"any":"12","named":{"k":"3"},"TOTAL":"42"}`
+			var page connectPage
+			if err := connectUnmarshal([]byte(data), &page); err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Items) != n {
+				t.Fatalf("decoded %d items, want %d", len(page.Items), n)
+			}
+			for i, item := range page.Items {
+				if item.N != int64(i) || item.U == nil || *item.U != uint64(2*i) || item.Label != "7" {
+					t.Fatalf("item %d decoded as %+v", i, item)
+				}
+			}
+			if page.Totals["a"] != 9007199254740993 || page.Quoted != 5 || page.Total != 42 {
+				t.Errorf("decoded %+v", page)
+			}
+			if string(page.Raw) != `{"x":"1"}` || page.Any != "12" || page.Named["k"] != "3" {
+				t.Errorf("values not meant as numbers were changed: %+v", page)
+			}
+		})
+	}
+}
+
+func TestConnectUnmarshalErrors(t *testing.T) {
+	var page connectPage
+	for _, data := range []string{`{"items":[{"n":"x"}]}`, `{"items":`} {
+		if err := connectUnmarshal([]byte(data), &page); err == nil {
+			t.Errorf("%s decoded", data)
+		}
+	}
+}
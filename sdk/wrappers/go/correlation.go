@@ -0,0 +1,20 @@
+package yourapi
+
+import "context"
+
+// DefaultCorrelationIDHeader is the header correlation IDs are sent in
+const DefaultCorrelationIDHeader = "X-Correlation-ID"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context whose requests carry id in the
+// correlation ID header
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in ctx, if any
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
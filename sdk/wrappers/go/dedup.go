@@ -0,0 +1,158 @@
+package yourapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// bufferedResponse is a fully read response that can be handed to several
+// callers
+type bufferedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// response returns a fresh *http.Response reading the buffered body
+func (b *bufferedResponse) response() *http.Response {
+	return &http.Response{
+		StatusCode: b.status,
+		Status:     fmt.Sprintf("%d %s", b.status, http.StatusText(b.status)),
+		Header:     b.header,
+		Body:       io.NopCloser(bytes.NewReader(b.body)),
+	}
+}
+
+// inflightWrite is a write whose outcome is shared by identical concurrent
+// writes
+type inflightWrite struct {
+	done chan struct{}
+	resp *bufferedResponse
+	err  error
+	// waiters counts the callers still waiting for the outcome, guarded by
+	// the group's mu; the write is cancelled when none is left
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// writeGroup coalesces concurrent identical writes into one request
+type writeGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightWrite
+}
+
+func newWriteGroup() *writeGroup {
+	return &writeGroup{calls: make(map[string]*inflightWrite)}
+}
+
+// do runs fn once for all concurrent callers with the same key, returning
+// the finished call, or ctx's error when the caller stopped waiting. fn
+// runs on the first caller's context values without its cancellation, so
+// that caller leaving does not fail the others; it is cancelled once every
+// caller has stopped waiting.
+func (g *writeGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (*bufferedResponse, error)) (*inflightWrite, bool, error) {
+	g.mu.Lock()
+	call, shared := g.calls[key]
+	if !shared {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &inflightWrite{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+		go func() {
+			resp, err := fn(callCtx)
+			cancel()
+			g.mu.Lock()
+			call.resp, call.err = resp, err
+			g.forget(key, call)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call, shared, nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		if call.waiters--; call.waiters == 0 {
+			call.cancel()
+			// Later callers start afresh rather than join a cancelled write
+			g.forget(key, call)
+		}
+		g.mu.Unlock()
+		return nil, shared, ctx.Err()
+	}
+}
+
+// forget removes call from the group, unless key already belongs to a
+// newer one. g.mu must be held.
+func (g *writeGroup) forget(key string, call *inflightWrite) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// writeKey identifies identical writes: by idempotency key when one is
+// sent, otherwise by method, path and canonical JSON body
+func writeKey(method, path string, headers map[string]string, body interface{}) (string, error) {
+	if key := headers[IdempotencyKeyHeader]; key != "" {
+		return "idempotency:" + key, nil
+	}
+	return requestFingerprint(method, path, body)
+}
+
+// doCoalesced performs a write through the write group, returning a
+// response each caller can consume independently
+func (c *Client) doCoalesced(ctx context.Context, method, path string, body interface{}, headers map[string]string) (*http.Response, error) {
+	key, err := writeKey(method, path, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	// The write tracks its attempts apart from the caller, which may stop
+	// waiting before it ends
+	ci := callFromContext(ctx)
+	var tracked *callInfo
+	if ci != nil {
+		copied := *ci
+		tracked = &copied
+	}
+	call, shared, err := c.writes.do(ctx, key, func(ctx context.Context) (*bufferedResponse, error) {
+		if tracked != nil {
+			ctx = context.WithValue(ctx, callContextKey{}, tracked)
+		}
+		resp, err := c.doRequest(ctx, method, path, body, headers)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		return &bufferedResponse{status: resp.StatusCode, header: resp.Header, body: data}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !shared && ci != nil {
+		*ci = *tracked
+	}
+	buf, err := call.resp, call.err
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		if ci != nil {
+			ci.status = buf.status
+			ci.header = buf.header
+			ci.requestID = buf.header.Get("X-Request-Id")
+		}
+		c.logger.Debug("coalesced duplicate write", "method", method, "path", path, "status", buf.status)
+	}
+	return buf.response(), nil
+}
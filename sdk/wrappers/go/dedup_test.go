@@ -0,0 +1,126 @@
+package yourapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// blockingTransport holds every request until release is closed or the
+// request is cancelled, reporting cancellations on cancelled
+type blockingTransport struct {
+	started   chan struct{}
+	release   chan struct{}
+	cancelled chan struct{}
+}
+
+func newBlockingTransport() *blockingTransport {
+	return &blockingTransport{
+		started:   make(chan struct{}, 10),
+		release:   make(chan struct{}),
+		cancelled: make(chan struct{}, 10),
+	}
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.started <- struct{}{}
+	select {
+	case <-b.release:
+		return fuzzTransport{status: 200, body: []byte(`{"ok":true}`)}.RoundTrip(req)
+	case <-req.Context().Done():
+		b.cancelled <- struct{}{}
+		return nil, req.Context().Err()
+	}
+}
+
+func newCoalescingClient(t *testing.T, rt http.RoundTripper) *Client {
+	t.Helper()
+	client, err := NewClient(ClientOptions{
+		BaseURL:        "http://dedup.test",
+		HTTPClient:     &http.Client{Transport: rt},
+		CoalesceWrites: true,
+		MaxRetries:     -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestCoalescedWriteOutlivesFirstCaller(t *testing.T) {
+	rt := newBlockingTransport()
+	client := newCoalescingClient(t, rt)
+	body := map[string]string{"name": "Ada"}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() { leader <- client.Post(leaderCtx, "/customers", body, nil) }()
+	<-rt.started
+
+	follower := make(chan error, 1)
+	var result struct{ OK bool }
+	go func() { follower <- client.Post(context.Background(), "/customers", body, &result) }()
+	// Let the follower join the in-flight write
+	for client.writes.waiters() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancelLeader()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Fatalf("first caller: got %v, want context.Canceled", err)
+	}
+	close(rt.release)
+	if err := <-follower; err != nil {
+		t.Fatalf("second caller: %v", err)
+	}
+	if !result.OK {
+		t.Error("second caller did not receive the shared response")
+	}
+	if len(rt.started) != 0 {
+		t.Errorf("%d extra requests sent", len(rt.started))
+	}
+}
+
+func TestCoalescedWriteCancelledWhenAllCallersLeave(t *testing.T) {
+	rt := newBlockingTransport()
+	client := newCoalescingClient(t, rt)
+	body := map[string]string{"name": "Ada"}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	go func() { errs <- client.Post(ctx1, "/customers", body, nil) }()
+	<-rt.started
+	go func() { errs <- client.Post(ctx2, "/customers", body, nil) }()
+	for client.writes.waiters() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel1()
+	<-errs
+	select {
+	case <-rt.cancelled:
+		t.Fatal("write cancelled while a caller still waits")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel2()
+	<-errs
+	select {
+	case <-rt.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("write not cancelled once every caller left")
+	}
+}
+
+// waiters returns the number of callers waiting on in-flight writes
+func (g *writeGroup) waiters() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n := 0
+	for _, call := range g.calls {
+		n += call.waiters
+	}
+	return n
+}
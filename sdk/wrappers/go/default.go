@@ -0,0 +1,48 @@
+package yourapi
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// defaultClient is the client set with SetDefault or created by Default
+var defaultClient atomic.Pointer[Client]
+
+// SetDefault makes c the client of the package-level Get, Post, Patch and
+// Put when they are given a nil client, much like http.DefaultClient:
+//
+//	yourapi.SetDefault(client)
+//	customer, err := yourapi.Get[Customer](ctx, nil, "/customers/cus_123")
+//
+// It suits scripts and small tools; libraries and services should pass
+// explicit clients. A nil c goes back to the client Default creates.
+func SetDefault(c *Client) {
+	defaultClient.Store(c)
+}
+
+// Default returns the client set with SetDefault. Without one, it creates
+// a client configured by the YOURAPI_* environment variables and the config
+// file (see ClientOptions.LoadEnv) on first use.
+func Default() (*Client, error) {
+	if c := defaultClient.Load(); c != nil {
+		return c, nil
+	}
+	c, err := New("", WithEnv())
+	if err != nil {
+		return nil, fmt.Errorf("no default client: call SetDefault or set %s: %w", EnvBaseURL, err)
+	}
+	defaultClient.CompareAndSwap(nil, c)
+	return defaultClient.Load(), nil
+}
+
+// orDefault returns c, or the default client when c is nil. A nil *Client
+// counts as nil, so a client variable left unset does not panic.
+func orDefault(c API) (API, error) {
+	if client, ok := c.(*Client); ok && client == nil {
+		c = nil
+	}
+	if c != nil {
+		return c, nil
+	}
+	return Default()
+}
@@ -0,0 +1,31 @@
+package yourapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestNilClientUsesDefault(t *testing.T) {
+	rt := &urlRecorder{fuzzTransport: fuzzTransport{status: 200, body: []byte(`{"id":"cus_123"}`)}}
+	client, err := NewClient(ClientOptions{BaseURL: "https://api.test/v1", HTTPClient: &http.Client{Transport: rt}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetDefault(client)
+	defer SetDefault(nil)
+
+	var unset *Client
+	for _, c := range []API{nil, unset} {
+		got, err := Get[map[string]string](context.Background(), c, "/customers/cus_123")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got["id"] != "cus_123" {
+			t.Errorf("decoded %v", got)
+		}
+	}
+	if len(rt.urls) != 2 {
+		t.Errorf("default client sent %d requests, want 2", len(rt.urls))
+	}
+}
@@ -0,0 +1,81 @@
+package yourapi
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by LoadEnv
+const (
+	// EnvBaseURL sets BaseURL
+	EnvBaseURL = "YOURAPI_BASE_URL"
+	// EnvAPIKey sets APIKey
+	EnvAPIKey = "YOURAPI_API_KEY"
+	// EnvTimeout sets Timeout, as a duration such as "30s" or a number of
+	// seconds
+	EnvTimeout = "YOURAPI_TIMEOUT"
+	// EnvDebug sets Debug, as a boolean such as "true" or "1"
+	EnvDebug = "YOURAPI_DEBUG"
+)
+
+// LoadEnv fills the fields of o that are still unset from the YOURAPI_*
+// environment variables, then from the config file profile named by
+// o.Profile or $YOURAPI_PROFILE (see LoadProfile). Values set in code take
+// precedence over the environment, which takes precedence over the
+// profile. Variables that are unset or empty are ignored; one that cannot
+// be parsed is an error.
+func (o *ClientOptions) LoadEnv() error {
+	// An Environment set in code selects the base URL too
+	if v := os.Getenv(EnvBaseURL); v != "" && o.BaseURL == "" && o.Environment == "" {
+		o.BaseURL = v
+	}
+	if v := os.Getenv(EnvAPIKey); v != "" && o.APIKey == "" && o.BearerToken == "" {
+		o.APIKey = v
+	}
+	if v := os.Getenv(EnvTimeout); v != "" && o.Timeout == 0 {
+		d, err := parseEnvDuration(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvTimeout, err)
+		}
+		o.Timeout = d
+	}
+	if v := os.Getenv(EnvDebug); v != "" && !o.Debug {
+		debug, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a boolean", EnvDebug, v)
+		}
+		o.Debug = debug
+	}
+	profile := o.Profile
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	return o.LoadProfile(profile)
+}
+
+// WithEnv makes NewClient apply LoadEnv after the other Options, so it
+// only fills the options they leave unset wherever it appears in the list
+func WithEnv() Option {
+	return func(o *ClientOptions) {
+		o.loadEnv = true
+	}
+}
+
+// parseEnvDuration parses a positive duration, either with a unit or as a
+// number of seconds
+func parseEnvDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		secs, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil {
+			return 0, fmt.Errorf("%q is not a duration", s)
+		}
+		d = time.Duration(secs * float64(time.Second))
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q is not a positive duration", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,49 @@
+package yourapi
+
+import "testing"
+
+func TestBaseURLFillRespectsEnvironment(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ClientOptions
+		want ClientOptions
+	}{
+		{
+			name: "unset",
+			want: ClientOptions{BaseURL: "https://from.test/v1"},
+		},
+		{
+			name: "base URL set in code",
+			opts: ClientOptions{BaseURL: "https://code.test"},
+			want: ClientOptions{BaseURL: "https://code.test"},
+		},
+		{
+			name: "environment set in code",
+			opts: ClientOptions{Environment: EnvironmentSandbox},
+			want: ClientOptions{Environment: EnvironmentSandbox},
+		},
+	}
+	for _, tt := range tests {
+		t.Run("env/"+tt.name, func(t *testing.T) {
+			t.Setenv(EnvBaseURL, "https://from.test/v1")
+			t.Setenv(EnvProfile, "")
+			t.Setenv(EnvConfigFile, t.TempDir()+"/missing.yaml")
+			o := tt.opts
+			if err := o.LoadEnv(); err != nil {
+				t.Fatal(err)
+			}
+			if o.BaseURL != tt.want.BaseURL || o.Environment != tt.want.Environment {
+				t.Errorf("got BaseURL %q, Environment %q; want %q, %q", o.BaseURL, o.Environment, tt.want.BaseURL, tt.want.Environment)
+			}
+		})
+		t.Run("profile/"+tt.name, func(t *testing.T) {
+			o := tt.opts
+			if err := o.applyProfile(map[string]string{"base_url": "https://from.test/v1"}); err != nil {
+				t.Fatal(err)
+			}
+			if o.BaseURL != tt.want.BaseURL || o.Environment != tt.want.Environment {
+				t.Errorf("got BaseURL %q, Environment %q; want %q, %q", o.BaseURL, o.Environment, tt.want.BaseURL, tt.want.Environment)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package yourapi
+
+import "strings"
+
+// Environment is a deployment of the API, identified by its base URL. The
+// generated Environment constants name the servers listed in the spec, and
+// any other deployment converts directly:
+//
+//	yourapi.Environment("https://eu.api.yourorg.com/v1")
+type Environment string
+
+// BaseURL returns the environment's base URL
+func (e Environment) BaseURL() string {
+	return string(e)
+}
+
+// Name returns the environment's name in the spec, e.g. "sandbox", or ""
+// for a custom environment
+func (e Environment) Name() string {
+	for _, env := range environments {
+		if env.env == e {
+			return env.name
+		}
+	}
+	return ""
+}
+
+// namedEnvironment pairs a known environment with its name
+type namedEnvironment struct {
+	name string
+	env  Environment
+}
+
+// Environments returns the API's known environments, in spec order
+func Environments() []Environment {
+	out := make([]Environment, len(environments))
+	for i, env := range environments {
+		out[i] = env.env
+	}
+	return out
+}
+
+// LookupEnvironment returns the known environment named name, ignoring
+// case, e.g. "Sandbox"
+func LookupEnvironment(name string) (Environment, bool) {
+	for _, env := range environments {
+		if strings.EqualFold(env.name, name) {
+			return env.env, true
+		}
+	}
+	return "", false
+}
+
+// WithEnvironment sends requests to e, see ClientOptions.Environment
+func WithEnvironment(e Environment) Option {
+	return func(o *ClientOptions) {
+		o.Environment = e
+	}
+}
@@ -0,0 +1,20 @@
+// Code generated by saligen-go. DO NOT EDIT.
+
+package yourapi
+
+// Environments of the API, from the servers in the spec
+const (
+	// EnvironmentProduction is the Production environment
+	EnvironmentProduction Environment = "https://api.yourorg.com/v1"
+	// EnvironmentStaging is the Staging environment
+	EnvironmentStaging Environment = "https://staging-api.yourorg.com/v1"
+	// EnvironmentSandbox is the Sandbox environment
+	EnvironmentSandbox Environment = "https://sandbox-api.yourorg.com/v1"
+)
+
+// environments lists the known environments by name, in spec order
+var environments = []namedEnvironment{
+	{"production", EnvironmentProduction},
+	{"staging", EnvironmentStaging},
+	{"sandbox", EnvironmentSandbox},
+}
@@ -0,0 +1,393 @@
+package yourapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/devdraft/devdraft-sdk-go/validate"
+)
+
+// Sentinel errors matched by APIError via errors.Is
+var (
+	// ErrNotFound matches API errors with a 404 status
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited matches API errors with a 429 status
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnauthorized matches API errors with a 401 status
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// ErrCode is an error code returned by the API. The documented codes are
+// generated as ErrCode constants, and errors.Is(err, code) reports whether
+// err is an APIError carrying that code.
+type ErrCode string
+
+func (c ErrCode) Error() string { return "api error " + string(c) }
+
+// ErrorCodeInfo describes a documented error code
+type ErrorCodeInfo struct {
+	// Status is the HTTP status the code is returned with, 0 when it varies
+	Status      int
+	Description string
+}
+
+// Info returns the spec's documentation of c, reporting false for codes
+// the spec does not list
+func (c ErrCode) Info() (ErrorCodeInfo, bool) {
+	info, ok := errorCodes[c]
+	return info, ok
+}
+
+// ErrorObserver receives every terminal error returned by the client, so
+// error reporting and metrics can be wired up in one place
+type ErrorObserver func(ctx context.Context, method, path string, err error)
+
+// APIError represents a structured API error
+type APIError struct {
+	Message   string                 `json:"message"`
+	Code      string                 `json:"code,omitempty"`
+	Details   interface{}            `json:"details,omitempty"`
+	RequestID string                 `json:"requestId,omitempty"`
+	Status    int                    `json:"-"`
+	Body      map[string]interface{} `json:"-"`
+	// LocalizedMessage is the error message in the client's Locale, when
+	// the API provides translations
+	LocalizedMessage string `json:"localizedMessage,omitempty"`
+
+	// Type, Title, Detail and Instance are populated from RFC 7807
+	// application/problem+json responses
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Extensions holds the non-standard members of a problem+json response
+	Extensions map[string]interface{} `json:"-"`
+
+	// RawBody is the unparsed response body
+	RawBody []byte `json:"-"`
+	// Truncated reports whether RawBody was cut off at MaxErrorBodySize
+	Truncated bool `json:"-"`
+	// Header is a copy of the response headers
+	Header http.Header `json:"-"`
+
+	// RetryAfter is the wait requested by the server's Retry-After header
+	RetryAfter time.Duration `json:"-"`
+	// RateLimitReset is when the server's rate limit window resets, taken
+	// from X-RateLimit-Reset
+	RateLimitReset time.Time `json:"-"`
+
+	// IdempotencyKey is the Idempotency-Key the request was sent with,
+	// whether provided or generated
+	IdempotencyKey string `json:"-"`
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("[%d] %s (code=%s, request_id=%s)", e.Status, e.Message, e.Code, e.RequestID)
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("[%d] %s (code=%s)", e.Status, e.Message, e.Code)
+	}
+	return fmt.Sprintf("[%d] %s", e.Status, e.Message)
+}
+
+// apiErrorJSON is the stable serialized shape of an APIError
+type apiErrorJSON struct {
+	Status    int          `json:"status"`
+	Code      string       `json:"code,omitempty"`
+	Message   string       `json:"message"`
+	RequestID string       `json:"requestId,omitempty"`
+	Details   interface{}  `json:"details,omitempty"`
+	Type      string       `json:"type,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Fields    []FieldError `json:"fields,omitempty"`
+
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+func (e *APIError) toJSON() apiErrorJSON {
+	return apiErrorJSON{
+		Status:    e.Status,
+		Code:      e.Code,
+		Message:   e.Message,
+		RequestID: e.RequestID,
+		Details:   e.Details,
+		Type:      e.Type,
+		Instance:  e.Instance,
+
+		IdempotencyKey: e.IdempotencyKey,
+	}
+}
+
+// MarshalJSON encodes the error as
+// {status, code, message, requestId, details, type, instance,
+// idempotencyKey}
+func (e *APIError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON())
+}
+
+// LogValue implements slog.LogValuer so errors log as structured groups
+func (e *APIError) LogValue() slog.Value {
+	return slog.GroupValue(e.logAttrs()...)
+}
+
+func (e *APIError) logAttrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.Int("status", e.Status),
+		slog.String("message", e.Message),
+	}
+	if e.Code != "" {
+		attrs = append(attrs, slog.String("code", e.Code))
+	}
+	if e.RequestID != "" {
+		attrs = append(attrs, slog.String("requestId", e.RequestID))
+	}
+	if e.Details != nil {
+		attrs = append(attrs, slog.Any("details", e.Details))
+	}
+	if e.Type != "" {
+		attrs = append(attrs, slog.String("type", e.Type))
+	}
+	if e.Instance != "" {
+		attrs = append(attrs, slog.String("instance", e.Instance))
+	}
+	if e.IdempotencyKey != "" {
+		attrs = append(attrs, slog.String("idempotencyKey", e.IdempotencyKey))
+	}
+	return attrs
+}
+
+// newAPIError builds an APIError from a decoded {message, code, details,
+// requestId} error body
+func newAPIError(status int, errorBody map[string]interface{}, locale string) *APIError {
+	apiErr := &APIError{
+		Status: status,
+		Body:   errorBody,
+	}
+
+	apiErr.LocalizedMessage = localizedMessage(errorBody, locale)
+	if msg, ok := errorBody["message"].(string); ok {
+		apiErr.Message = msg
+	} else if apiErr.LocalizedMessage != "" {
+		apiErr.Message = apiErr.LocalizedMessage
+	} else {
+		apiErr.Message = "Request failed"
+	}
+
+	if code, ok := errorBody["code"].(string); ok {
+		apiErr.Code = code
+	}
+
+	if details, ok := errorBody["details"]; ok {
+		apiErr.Details = details
+	}
+
+	if requestID, ok := errorBody["requestId"].(string); ok {
+		apiErr.RequestID = requestID
+	}
+
+	return apiErr
+}
+
+// Is reports whether the error matches one of the package sentinel errors
+// or carries the ErrCode target
+func (e *APIError) Is(target error) bool {
+	if code, ok := target.(ErrCode); ok {
+		return e.Code != "" && e.Code == string(code)
+	}
+	switch target {
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.Status == http.StatusUnauthorized
+	}
+	return false
+}
+
+// AsAPIError returns the APIError in err's chain, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// StatusCode returns the HTTP status of the APIError in err's chain, or 0
+func StatusCode(err error) int {
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.Status
+	}
+	return 0
+}
+
+// RetryAfter returns how long the server asked callers to wait before
+// retrying the request that produced err. It falls back to the rate limit
+// reset time when no Retry-After header was sent.
+func RetryAfter(err error) (time.Duration, bool) {
+	return retryAfter(err, time.Now())
+}
+
+// retryAfter is RetryAfter with the rate limit reset measured from now
+func retryAfter(err error, now time.Time) (time.Duration, bool) {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return 0, false
+	}
+	if apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+	if !apiErr.RateLimitReset.IsZero() {
+		if wait := apiErr.RateLimitReset.Sub(now); wait > 0 {
+			return wait, true
+		}
+	}
+	return 0, false
+}
+
+// ContentType returns the Content-Type of the error response
+func (e *APIError) ContentType() string {
+	return e.Header.Get("Content-Type")
+}
+
+// ErrorCode returns the API error code of the APIError in err's chain, or ""
+func ErrorCode(err error) string {
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.Code
+	}
+	return ""
+}
+
+// problemMembers are the members defined by RFC 7807; everything else in a
+// problem document is an extension member
+var problemMembers = map[string]bool{
+	"type": true, "title": true, "status": true, "detail": true, "instance": true,
+}
+
+// isProblemJSON reports whether the content type is application/problem+json
+func isProblemJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/problem+json"
+}
+
+// applyProblem maps an RFC 7807 problem document onto the error
+func (e *APIError) applyProblem(problem map[string]interface{}) {
+	e.Type, _ = problem["type"].(string)
+	e.Title, _ = problem["title"].(string)
+	e.Detail, _ = problem["detail"].(string)
+	e.Instance, _ = problem["instance"].(string)
+
+	e.Extensions = make(map[string]interface{})
+	for k, v := range problem {
+		if !problemMembers[k] {
+			e.Extensions[k] = v
+		}
+	}
+
+	switch {
+	case e.Detail != "":
+		e.Message = e.Detail
+	case e.Title != "":
+		e.Message = e.Title
+	}
+}
+
+// FieldError describes a validation failure for a single request field
+type FieldError = validate.FieldError
+
+// ValidationError is returned for 422 responses, for 400 responses that
+// carry per-field errors, and for requests failing the client-side checks
+// generated from the spec
+type ValidationError struct {
+	*APIError
+	Fields []FieldError
+	// Local reports whether the client found the errors before sending the
+	// request. Local errors have no status, request ID or response.
+	Local bool
+}
+
+func (e *ValidationError) Error() string {
+	if e.Local {
+		return "invalid request: " + validate.Errors(e.Fields).Error()
+	}
+	return e.APIError.Error()
+}
+
+// Unwrap returns the underlying APIError
+func (e *ValidationError) Unwrap() error {
+	return e.APIError
+}
+
+// MarshalJSON encodes the error like APIError, adding the field errors
+func (e *ValidationError) MarshalJSON() ([]byte, error) {
+	out := e.APIError.toJSON()
+	out.Fields = e.Fields
+	return json.Marshal(out)
+}
+
+// LogValue implements slog.LogValuer, adding the field errors
+func (e *ValidationError) LogValue() slog.Value {
+	attrs := e.APIError.logAttrs()
+	if len(e.Fields) > 0 {
+		attrs = append(attrs, slog.Any("fields", e.Fields))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Field returns the errors reported for the named field
+func (e *ValidationError) Field(name string) []FieldError {
+	var matches []FieldError
+	for _, f := range e.Fields {
+		if f.Field == name {
+			matches = append(matches, f)
+		}
+	}
+	return matches
+}
+
+// fieldErrorLists are the body members known to carry per-field errors
+var fieldErrorLists = []string{"details", "errors", "invalid-params"}
+
+// parseFieldErrors extracts per-field errors from an error body
+func parseFieldErrors(body map[string]interface{}) []FieldError {
+	var fields []FieldError
+	for _, key := range fieldErrorLists {
+		list, ok := body[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, entry := range list {
+			obj, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fe := FieldError{
+				Field:   firstString(obj, "field", "name", "path", "pointer"),
+				Code:    firstString(obj, "code"),
+				Message: firstString(obj, "message", "issue", "reason", "detail"),
+			}
+			if fe.Field != "" {
+				fields = append(fields, fe)
+			}
+		}
+	}
+	return fields
+}
+
+// firstString returns the first non-empty string value among keys
+func firstString(obj map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := obj[k].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+// Code generated by saligen-go. DO NOT EDIT.
+
+package yourapi
+
+// Error codes documented by the API. An APIError matches its code with
+// errors.Is.
+const (
+	// ErrCodeConflict is returned when the resource already exists
+	ErrCodeConflict ErrCode = "CONFLICT"
+	// ErrCodeInternalError is returned when the server failed to process the
+	// request
+	ErrCodeInternalError ErrCode = "INTERNAL_ERROR"
+	// ErrCodeInvalidRequest is returned when the request parameters are invalid
+	ErrCodeInvalidRequest ErrCode = "INVALID_REQUEST"
+	// ErrCodeNotFound is returned when the requested resource does not exist
+	ErrCodeNotFound ErrCode = "NOT_FOUND"
+	// ErrCodeRateLimitExceeded is returned when too many requests were sent,
+	// retry after the Retry-After delay
+	ErrCodeRateLimitExceeded ErrCode = "RATE_LIMIT_EXCEEDED"
+	// ErrCodeUnauthorized is returned when authentication credentials are
+	// missing or invalid
+	ErrCodeUnauthorized ErrCode = "UNAUTHORIZED"
+)
+
+// errorCodes maps the documented error codes to their descriptions
+var errorCodes = map[ErrCode]ErrorCodeInfo{
+	ErrCodeConflict:          {Status: 409, Description: "The resource already exists"},
+	ErrCodeInternalError:     {Status: 500, Description: "The server failed to process the request"},
+	ErrCodeInvalidRequest:    {Status: 400, Description: "The request parameters are invalid"},
+	ErrCodeNotFound:          {Status: 404, Description: "The requested resource does not exist"},
+	ErrCodeRateLimitExceeded: {Status: 429, Description: "Too many requests were sent, retry after the Retry-After delay"},
+	ErrCodeUnauthorized:      {Status: 401, Description: "Authentication credentials are missing or invalid"},
+}
@@ -0,0 +1,159 @@
+package yourapi
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is a client lifecycle event delivered to subscribers
+type Event interface {
+	// EventType returns a stable name for the event, e.g. "retry_scheduled"
+	EventType() string
+}
+
+// RequestStarted is emitted before each attempt of a request
+type RequestStarted struct {
+	Method  string
+	Path    string
+	Attempt int
+	Time    time.Time
+}
+
+// RetryScheduled is emitted when a failed attempt will be retried
+type RetryScheduled struct {
+	Method  string
+	Path    string
+	Attempt int
+	Backoff time.Duration
+	// Status is the status that triggered the retry, 0 for transport errors
+	Status int
+	// Err is the transport error that triggered the retry, if any
+	Err error
+}
+
+// RateLimited is emitted for every 429 response
+type RateLimited struct {
+	Method     string
+	Path       string
+	RetryAfter time.Duration
+	// Reset is when the server's rate limit window resets, if reported
+	Reset time.Time
+}
+
+// RequestFinished is emitted once a logical request completes, after retries
+type RequestFinished struct {
+	Method   string
+	Path     string
+	Status   int
+	Attempts int
+	Duration time.Duration
+	// Timings is set when ClientOptions.ConnectionTimings is enabled
+	Timings *ConnTimings
+	Err     error
+}
+
+// SlowRequest is emitted when a request exceeds SlowRequestThreshold
+type SlowRequest struct {
+	Method   string
+	Path     string
+	Status   int
+	Duration time.Duration
+	// Attempts holds the duration of each attempt, up to response headers
+	Attempts []time.Duration
+	// Backoff is the total time spent waiting between attempts
+	Backoff time.Duration
+	Err     error
+}
+
+func (RequestStarted) EventType() string  { return "request_started" }
+func (RetryScheduled) EventType() string  { return "retry_scheduled" }
+func (RateLimited) EventType() string     { return "rate_limited" }
+func (RequestFinished) EventType() string { return "request_finished" }
+func (SlowRequest) EventType() string     { return "slow_request" }
+
+// eventBus fans events out to subscribers
+type eventBus struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]func(Event)
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subscribers: make(map[int]func(Event))}
+}
+
+func (b *eventBus) subscribe(fn func(Event)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = fn
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// emit calls every subscriber with e. They are called without the lock
+// held, so a subscriber may subscribe or unsubscribe.
+func (b *eventBus) emit(e Event) {
+	b.mu.RLock()
+	subscribers := make([]func(Event), 0, len(b.subscribers))
+	for _, fn := range b.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	b.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(e)
+	}
+}
+
+// Subscribe registers fn to receive every lifecycle event and returns a
+// function that unsubscribes it. fn is called synchronously on the request
+// goroutine, so it must not block.
+func (c *Client) Subscribe(fn func(Event)) (unsubscribe func()) {
+	return c.events.subscribe(fn)
+}
+
+// Events returns a channel receiving lifecycle events and a function that
+// stops delivery. Events are dropped rather than blocking requests when the
+// buffer is full. The channel is never closed.
+func (c *Client) Events(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+	unsubscribe := c.events.subscribe(func(e Event) {
+		select {
+		case ch <- e:
+		default:
+		}
+	})
+	return ch, unsubscribe
+}
+
+// reportSlowRequest logs and emits a request that exceeded the slow
+// request threshold, with a breakdown of where the time went
+func (c *Client) reportSlowRequest(ci *callInfo, duration time.Duration, err error) {
+	event := SlowRequest{
+		Method:   ci.method,
+		Path:     ci.path,
+		Status:   ci.status,
+		Duration: duration,
+		Attempts: ci.attemptDurations,
+		Backoff:  ci.backoff,
+		Err:      err,
+	}
+
+	attemptsMs := make([]int64, len(ci.attemptDurations))
+	for i, d := range ci.attemptDurations {
+		attemptsMs[i] = d.Milliseconds()
+	}
+	logger, _ := c.loggerFor(ci)
+	logger.Warn("slow request", "event", EventSlowRequest, "method", ci.method, "path", ci.path, "status", ci.status,
+		"durationMs", duration.Milliseconds(), "thresholdMs", c.slowThreshold.Milliseconds(),
+		"attemptsMs", attemptsMs, "backoffMs", ci.backoff.Milliseconds())
+
+	c.events.emit(event)
+}
@@ -0,0 +1,32 @@
+package yourapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscriberCanUnsubscribeDuringEmit(t *testing.T) {
+	b := newEventBus()
+	var calls int
+	var unsubscribe func()
+	unsubscribe = b.subscribe(func(Event) {
+		calls++
+		unsubscribe()
+		b.subscribe(func(Event) {})
+	})
+
+	done := make(chan struct{})
+	go func() {
+		b.emit(RequestStarted{})
+		b.emit(RequestStarted{})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("emit deadlocked on a subscriber changing subscriptions")
+	}
+	if calls != 1 {
+		t.Errorf("subscriber called %d times, want 1", calls)
+	}
+}
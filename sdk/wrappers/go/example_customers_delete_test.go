@@ -0,0 +1,26 @@
+// Code generated by saligen-go. DO NOT EDIT.
+
+package yourapi_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+)
+
+func ExampleCustomersService_Delete() {
+	client, err := yourapi.NewClient(yourapi.ClientOptions{
+		BaseURL: "https://api.yourorg.com/v1",
+		APIKey:  os.Getenv("API_KEY"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := client.Customers.Delete(context.Background(), "123e4567-e89b-12d3-a456-426614174000"); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("done")
+}
@@ -0,0 +1,45 @@
+// Code generated by saligen-go. DO NOT EDIT.
+
+package yourapi_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+)
+
+func ExampleCustomersService_List() {
+	client, err := yourapi.NewClient(yourapi.ClientOptions{
+		BaseURL: "https://api.yourorg.com/v1",
+		APIKey:  os.Getenv("API_KEY"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	result, err := client.Customers.List(context.Background(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%+v\n", result)
+}
+
+func ExampleCustomersService_ListIter() {
+	client, err := yourapi.NewClient(yourapi.ClientOptions{
+		BaseURL: "https://api.yourorg.com/v1",
+		APIKey:  os.Getenv("API_KEY"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	it := client.Customers.ListIter(context.Background(), nil)
+	for it.Next() {
+		fmt.Printf("%+v\n", it.Item())
+	}
+	if err := it.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,123 @@
+package yourapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fuzzTransport answers every request with the same status, headers and
+// body
+type fuzzTransport struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (f fuzzTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: f.status,
+		Header:     f.header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+func newFuzzClient(t *testing.T, rt http.RoundTripper) *Client {
+	client, err := NewClient(ClientOptions{
+		BaseURL:          "http://fuzz.test",
+		HTTPClient:       &http.Client{Transport: rt},
+		MaxErrorBodySize: 4096,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func FuzzParseError(f *testing.F) {
+	f.Add(400, "application/json", "", []byte(`{"code":"invalid","message":"bad","details":[{"field":"email","message":"required"}]}`))
+	f.Add(422, "application/problem+json", "5", []byte(`{"type":"about:blank","title":"Invalid","status":422,"invalid-params":[{"name":"age","reason":"too low"}]}`))
+	f.Add(429, "application/json", "Wed, 21 Oct 2015 07:28:00 GMT", []byte(`{"message":{"en":"x"},"code":7}`))
+	f.Add(500, "text/html", "-1", []byte(`<html>oops</html>`))
+	f.Add(404, "application/json", "99999999999999999999", []byte(`null`))
+	f.Add(400, "application/json", "", []byte(`{"errors":[null,1,"x",{"field":{}}],"details":{"a":1}}`))
+	f.Add(503, "", "", []byte{})
+
+	f.Fuzz(func(t *testing.T, status int, contentType, retryAfter string, body []byte) {
+		if status < 400 || status > 599 {
+			status = 400 + (status%200+200)%200
+		}
+		header := http.Header{}
+		header.Set("Content-Type", contentType)
+		header.Set("Retry-After", retryAfter)
+		header.Set("X-RateLimit-Reset", retryAfter)
+		client := newFuzzClient(t, nil)
+
+		err := client.parseError(&http.Response{
+			StatusCode: status,
+			Header:     header,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		})
+		apiErr, ok := AsAPIError(err)
+		if !ok {
+			t.Fatalf("parseError returned %T, want *APIError", err)
+		}
+		if apiErr.Status != status {
+			t.Fatalf("status = %d, want %d", apiErr.Status, status)
+		}
+		if apiErr.RetryAfter < 0 {
+			t.Fatalf("negative RetryAfter %v from %q", apiErr.RetryAfter, retryAfter)
+		}
+		if int64(len(apiErr.RawBody)) > client.maxErrorBodySize {
+			t.Fatalf("RawBody has %d bytes, limit is %d", len(apiErr.RawBody), client.maxErrorBodySize)
+		}
+		_ = err.Error()
+		if _, err := json.Marshal(err); err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		var ve *ValidationError
+		if errors.As(err, &ve) {
+			for _, fe := range ve.Fields {
+				if fe.Field == "" {
+					t.Fatal("field error without a field name")
+				}
+			}
+		}
+	})
+}
+
+func FuzzPaginateCursor(f *testing.F) {
+	f.Add([]byte(`{"items":[{"id":1},{"id":2}],"nextCursor":null,"hasMore":false}`))
+	f.Add([]byte(`{"items":[1],"nextCursor":"c1","hasMore":true}`))
+	f.Add([]byte(`{"items":[],"nextCursor":"","hasMore":true}`))
+	f.Add([]byte(`{"items":null,"hasMore":true}`))
+	f.Add([]byte(`{"items":{"id":1},"nextCursor":3,"hasMore":"yes"}`))
+	f.Add([]byte(`[]`))
+	f.Add([]byte(`{"items":[`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		header := http.Header{"Content-Type": {"application/json"}}
+		client := newFuzzClient(t, fuzzTransport{status: http.StatusOK, header: header, body: body})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var pages int
+		err := client.PaginateCursor(ctx, "/items", func(interface{}) error {
+			pages++
+			return nil
+		})
+		if errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("pagination did not terminate on %q", body)
+		}
+		if err != nil && !strings.Contains(err.Error(), "decode") && !strings.Contains(err.Error(), "cursor") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,5 @@
+package yourapi
+
+// The models and services are generated from the OpenAPI spec; rerun after
+// changing it
+//go:generate go run ./cmd/saligen-go -spec ../../openapi.json -out .
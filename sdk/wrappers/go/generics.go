@@ -0,0 +1,56 @@
+package yourapi
+
+import "context"
+
+// Get performs a GET request through c and returns the response decoded as
+// T, e.g.
+//
+//	customer, err := yourapi.Get[Customer](ctx, client, "/customers/cus_123")
+//
+// A nil c, or a nil *Client, uses the default client, see SetDefault; Post,
+// Patch and Put do the same.
+func Get[T any](ctx context.Context, c API, path string, opts ...RequestOption) (T, error) {
+	var result T
+	c, err := orDefault(c)
+	if err != nil {
+		return result, err
+	}
+	err = c.Get(ctx, path, &result, opts...)
+	return result, err
+}
+
+// Post performs a POST request through c and returns the response decoded
+// as T
+func Post[T any](ctx context.Context, c API, path string, body interface{}, opts ...RequestOption) (T, error) {
+	var result T
+	c, err := orDefault(c)
+	if err != nil {
+		return result, err
+	}
+	err = c.Post(ctx, path, body, &result, opts...)
+	return result, err
+}
+
+// Patch performs a PATCH request through c and returns the response
+// decoded as T
+func Patch[T any](ctx context.Context, c API, path string, body interface{}, opts ...RequestOption) (T, error) {
+	var result T
+	c, err := orDefault(c)
+	if err != nil {
+		return result, err
+	}
+	err = c.Patch(ctx, path, body, &result, opts...)
+	return result, err
+}
+
+// Put performs a PUT request through c and returns the response decoded
+// as T
+func Put[T any](ctx context.Context, c API, path string, body interface{}, opts ...RequestOption) (T, error) {
+	var result T
+	c, err := orDefault(c)
+	if err != nil {
+		return result, err
+	}
+	err = c.Put(ctx, path, body, &result, opts...)
+	return result, err
+}
@@ -0,0 +1,93 @@
+package yourapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// IdempotencyKeyHeader is the header carrying the idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyKeyGenerator creates idempotency keys for requests sent without
+// one, letting keys follow an organization's own scheme (ULIDs, keys derived
+// from business identifiers, ...)
+type IdempotencyKeyGenerator interface {
+	// IdempotencyKey returns the key for a request; body is the value
+	// passed to Post, before JSON encoding. An empty key sends the request
+	// without one.
+	IdempotencyKey(ctx context.Context, method, path string, body interface{}) (string, error)
+}
+
+// IdempotencyKeyFunc adapts a function to IdempotencyKeyGenerator
+type IdempotencyKeyFunc func(ctx context.Context, method, path string, body interface{}) (string, error)
+
+// IdempotencyKey implements IdempotencyKeyGenerator
+func (f IdempotencyKeyFunc) IdempotencyKey(ctx context.Context, method, path string, body interface{}) (string, error) {
+	return f(ctx, method, path, body)
+}
+
+// uuidKeys is the default IdempotencyKeyGenerator
+var uuidKeys = IdempotencyKeyFunc(func(context.Context, string, string, interface{}) (string, error) {
+	return NewUUID()
+})
+
+// FingerprintIdempotencyKeys derives the key from a SHA-256 hash of the
+// method, path and canonicalized JSON body, so identical requests from
+// different processes or restarts share a key and dedupe server-side.
+// Requests meant to be distinct must differ in path or body.
+var FingerprintIdempotencyKeys IdempotencyKeyGenerator = IdempotencyKeyFunc(func(_ context.Context, method, path string, body interface{}) (string, error) {
+	sum, err := requestFingerprint(method, path, body)
+	if err != nil {
+		return "", err
+	}
+	return "fp-" + sum, nil
+})
+
+// requestFingerprint returns the hex SHA-256 of method, path and the
+// canonical JSON encoding of body
+func requestFingerprint(method, path string, body interface{}) (string, error) {
+	canonical, err := canonicalJSON(body)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", method, path)
+	h.Write(canonical)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// canonicalJSON encodes v as compact JSON with object keys sorted, so equal
+// values produce equal bytes regardless of struct field or map order
+func canonicalJSON(v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to canonicalize request body: %w", err)
+	}
+	return json.Marshal(generic)
+}
+
+// NewUUID returns a random RFC 4122 version 4 UUID, the format of the
+// client's default idempotency keys
+func NewUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
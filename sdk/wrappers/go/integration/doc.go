@@ -0,0 +1,10 @@
+// Package integration holds the SDK's integration suite, which runs against
+// a live mock of the API served by Prism from the OpenAPI spec. The tests
+// are behind the integration build tag; run them with
+//
+//	./integration/run.sh
+//
+// or, against an already running mock,
+//
+//	YOURAPI_INTEGRATION_URL=http://localhost:4010 go test -tags integration ./integration/...
+package integration
@@ -0,0 +1,180 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+	"github.com/devdraft/devdraft-sdk-go/yourapitest"
+)
+
+// Environment variables configuring the suite
+const (
+	// URLEnv is the base URL of the mock API; the suite is skipped without it
+	URLEnv = "YOURAPI_INTEGRATION_URL"
+	// SpecEnv is the path of the OpenAPI spec the mock serves, used to
+	// validate traffic (default: ../../../openapi.json)
+	SpecEnv = "YOURAPI_INTEGRATION_SPEC"
+)
+
+const customerID = "123e4567-e89b-12d3-a456-426614174000"
+
+type customer struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Phone     *string   `json:"phone"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type customerList struct {
+	Items      []customer `json:"items"`
+	NextCursor *string    `json:"nextCursor"`
+	HasMore    bool       `json:"hasMore"`
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(URLEnv) == "" {
+		fmt.Printf("skipping integration suite: %s is not set\n", URLEnv)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// newClient returns a client for the mock API whose traffic is checked
+// against the spec. Extra headers, such as Prism's Prefer, are sent on
+// every request.
+func newClient(t *testing.T, headers map[string]string) *yourapi.Client {
+	t.Helper()
+	spec := os.Getenv(SpecEnv)
+	if spec == "" {
+		spec = "../../../openapi.json"
+	}
+	contract := yourapitest.LoadContract(t, spec)
+	client, err := yourapi.NewClient(contract.ClientOptions(t, options(headers)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// newUncheckedClient returns a client whose traffic is not validated, for
+// tests that send invalid requests on purpose
+func newUncheckedClient(t *testing.T, opts yourapi.ClientOptions) *yourapi.Client {
+	t.Helper()
+	client, err := yourapi.NewClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func options(headers map[string]string) yourapi.ClientOptions {
+	return yourapi.ClientOptions{
+		BaseURL:       os.Getenv(URLEnv),
+		APIKey:        "integration-test-key",
+		Timeout:       10 * time.Second,
+		MaxRetries:    1,
+		RetryBackoff:  yourapitest.FastRetries,
+		CustomHeaders: headers,
+	}
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestListCustomers(t *testing.T) {
+	client := newClient(t, nil)
+
+	var list customerList
+	if err := client.Get(testContext(t), "/customers?limit=5", &list); err != nil {
+		t.Fatalf("list customers: %v", err)
+	}
+	for _, c := range list.Items {
+		if c.ID == "" || c.Email == "" {
+			t.Errorf("incomplete customer %+v", c)
+		}
+	}
+}
+
+func TestGetCustomer(t *testing.T) {
+	client := newClient(t, nil)
+
+	var c customer
+	if err := client.Get(testContext(t), "/customers/"+customerID, &c); err != nil {
+		t.Fatalf("get customer: %v", err)
+	}
+	if c.ID == "" || c.CreatedAt.IsZero() {
+		t.Errorf("incomplete customer %+v", c)
+	}
+}
+
+func TestCreateCustomer(t *testing.T) {
+	client := newClient(t, nil)
+
+	var meta yourapi.ResponseMetadata
+	var c customer
+	err := client.Post(testContext(t), "/customers",
+		map[string]interface{}{"email": "ada@example.com", "name": "Ada Lovelace"}, &c,
+		yourapi.WithIdempotencyKey("5f0c7b8e-3f43-4a52-9d0e-1b7b0f0c2a11"), yourapi.WithMetadata(&meta))
+	if err != nil {
+		t.Fatalf("create customer: %v", err)
+	}
+	if meta.Status != http.StatusCreated {
+		t.Errorf("status = %d, want %d", meta.Status, http.StatusCreated)
+	}
+	if c.ID == "" {
+		t.Errorf("created customer has no id: %+v", c)
+	}
+}
+
+func TestDeleteCustomer(t *testing.T) {
+	client := newClient(t, nil)
+
+	if err := client.Delete(testContext(t), "/customers/"+customerID); err != nil {
+		t.Fatalf("delete customer: %v", err)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	client := newClient(t, map[string]string{"Prefer": "code=404"})
+
+	var c customer
+	err := client.Get(testContext(t), "/customers/"+customerID, &c)
+	if !errors.Is(err, yourapi.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestInvalidRequest(t *testing.T) {
+	client := newUncheckedClient(t, options(nil))
+
+	var c customer
+	err := client.Post(testContext(t), "/customers", map[string]interface{}{"name": ""}, &c)
+	if status := yourapi.StatusCode(err); status != http.StatusBadRequest && status != http.StatusUnprocessableEntity {
+		t.Fatalf("err = %v, want a 400 or 422 API error", err)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	opts := options(nil)
+	opts.APIKey = ""
+	client := newUncheckedClient(t, opts)
+
+	var list customerList
+	err := client.Get(testContext(t), "/customers", &list)
+	if !errors.Is(err, yourapi.ErrUnauthorized) {
+		t.Fatalf("err = %v, want ErrUnauthorized", err)
+	}
+}
@@ -0,0 +1,67 @@
+#!/bin/bash
+
+# Runs the SDK integration suite against a Prism mock of the API.
+#
+# Usage: ./integration/run.sh [go test flags...]
+#
+# The mock is started with docker compose, or with npx when docker is not
+# available, and stopped when the suite finishes. Set
+# YOURAPI_INTEGRATION_URL to run against a mock that is already up.
+
+set -euo pipefail
+
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+MODULE_DIR="$(dirname "$SCRIPT_DIR")"
+PORT="${YOURAPI_INTEGRATION_PORT:-4010}"
+SPEC="${YOURAPI_INTEGRATION_SPEC:-$MODULE_DIR/../../openapi.json}"
+
+export YOURAPI_INTEGRATION_PORT="$PORT"
+export YOURAPI_INTEGRATION_SPEC="$(cd "$(dirname "$SPEC")" && pwd)/$(basename "$SPEC")"
+
+cleanup() {
+    if [ -n "${MOCK_PID:-}" ]; then
+        kill "$MOCK_PID" 2>/dev/null || true
+    fi
+    if [ "${USING_COMPOSE:-}" = "1" ]; then
+        docker compose -f "$SCRIPT_DIR/docker-compose.yml" down --remove-orphans >/dev/null 2>&1 || true
+    fi
+}
+
+if [ -z "${YOURAPI_INTEGRATION_URL:-}" ]; then
+    if [ ! -f "$YOURAPI_INTEGRATION_SPEC" ]; then
+        echo "OpenAPI spec not found at $YOURAPI_INTEGRATION_SPEC; set YOURAPI_INTEGRATION_SPEC" >&2
+        exit 1
+    fi
+    trap cleanup EXIT
+
+    if command -v docker >/dev/null 2>&1 && docker info >/dev/null 2>&1; then
+        echo "Starting mock API with docker compose on port $PORT..."
+        USING_COMPOSE=1
+        docker compose -f "$SCRIPT_DIR/docker-compose.yml" up -d
+    elif command -v npx >/dev/null 2>&1; then
+        echo "Docker not available, starting mock API with npx on port $PORT..."
+        npx --yes @stoplight/prism-cli@5 mock --port "$PORT" --dynamic "$YOURAPI_INTEGRATION_SPEC" >/dev/null 2>&1 &
+        MOCK_PID=$!
+    else
+        echo "Neither docker nor npx is available to run the mock API" >&2
+        exit 1
+    fi
+
+    export YOURAPI_INTEGRATION_URL="http://localhost:$PORT"
+
+    echo "Waiting for mock API at $YOURAPI_INTEGRATION_URL..."
+    for _ in $(seq 1 60); do
+        # Any HTTP response, even 401, means the mock is serving
+        if curl -s -o /dev/null "$YOURAPI_INTEGRATION_URL/customers"; then
+            break
+        fi
+        sleep 1
+    done
+    if ! curl -s -o /dev/null "$YOURAPI_INTEGRATION_URL/customers"; then
+        echo "Mock API did not start" >&2
+        exit 1
+    fi
+fi
+
+cd "$MODULE_DIR"
+go test -tags integration -count=1 "$@" ./integration/...
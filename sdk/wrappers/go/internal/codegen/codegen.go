@@ -0,0 +1,326 @@
+// Package codegen generates the SDK's typed Go code (models and services)
+// from an OpenAPI document
+package codegen
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+)
+
+// Header starts every generated file, so tools and reviewers skip them
+const Header = "// Code generated by saligen-go. DO NOT EDIT."
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Config controls a generation run
+type Config struct {
+	// Spec is the OpenAPI document to generate from
+	Spec *openapi.Document
+	// ModulePath is the import path of the SDK module, e.g.
+	// "github.com/devdraft/devdraft-sdk-go"
+	ModulePath string
+	// ModelsPackage is the directory and package name of the models
+	// (default: "models")
+	ModelsPackage string
+	// Templates holds *.tmpl files overriding the built-in templates
+	// (optional). A file named like a built-in one (models.go.tmpl,
+	// services.go.tmpl, errors.go.tmpl, environments.go.tmpl,
+	// webhooks.go.tmpl, example_test.go.tmpl) replaces
+	// it, and {{define}} blocks replace the built-in blocks of the same
+	// name, which stay available as "default.<name>". Any other *.go.tmpl
+	// file is rendered to the same path without ".tmpl".
+	Templates fs.FS
+	// PreserveUnknown gives struct models an UnknownFields map keeping the
+	// JSON members missing from the spec, which MarshalJSON sends back.
+	// Read-modify-write flows then keep data added by newer API versions.
+	PreserveUnknown bool
+	// ConnectPackage is the protobuf package of the API's Connect services
+	// (default: the spec's x-connect-package). When set, every service
+	// method also speaks the Connect protocol, selected with
+	// ClientOptions.Protocol; operations name their procedure with
+	// x-connect-procedure or default to "/<package>.<Service>/<OperationID>".
+	ConnectPackage string
+}
+
+// builtinFiles are the file templates Generate always renders
+var builtinFiles = map[string]bool{
+	"environments.go.tmpl": true,
+	"errors.go.tmpl":       true,
+	"example_test.go.tmpl": true,
+	"models.go.tmpl":       true,
+	"services.go.tmpl":     true,
+	"webhooks.go.tmpl":     true,
+}
+
+// File is a generated source file
+type File struct {
+	// Path is relative to the module root
+	Path    string
+	Content []byte
+}
+
+func (c *Config) withDefaults() {
+	if c.ModelsPackage == "" {
+		c.ModelsPackage = "models"
+	}
+	if c.ConnectPackage == "" && c.Spec != nil {
+		c.ConnectPackage = c.Spec.ConnectPackage
+	}
+}
+
+// Generate produces the SDK's generated files. Output is deterministic: the
+// same spec always yields byte-identical files.
+func Generate(cfg Config) ([]File, error) {
+	cfg.withDefaults()
+	if cfg.Spec == nil {
+		return nil, fmt.Errorf("codegen: no spec")
+	}
+	if cfg.ModulePath == "" {
+		return nil, fmt.Errorf("codegen: no module path")
+	}
+	tmpl, extra, err := parseTemplates(cfg.Templates)
+	if err != nil {
+		return nil, err
+	}
+
+	models, err := buildModels(cfg.Spec)
+	if err != nil {
+		return nil, err
+	}
+	modelsPkg := path.Base(cfg.ModelsPackage)
+	models.typesImport = cfg.ModulePath + "/types"
+	models.validateImport = cfg.ModulePath + "/validate"
+	models.pkg = modelsPkg
+	// Services are built first: they add their inline schemas to models
+	services, err := buildServices(cfg.Spec, models, modelsPkg)
+	if err != nil {
+		return nil, err
+	}
+	webhooks, err := buildWebhooks(cfg.Spec, models, modelsPkg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ConnectPackage != "" {
+		addConnect(services, cfg.ConnectPackage)
+	}
+	models.addValidations(services)
+	if cfg.PreserveUnknown {
+		models.preserveUnknown()
+	}
+	stdImports, moduleImports := splitImports(models.importList())
+	modelsFile, err := render(tmpl, "models.go.tmpl", map[string]interface{}{
+		"Header":        Header,
+		"Package":       modelsPkg,
+		"Imports":       stdImports,
+		"ModuleImports": moduleImports,
+		"Models":        models.sorted(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	std, module := serviceImports(services, cfg.ModulePath+"/"+cfg.ModelsPackage, modelsPkg, models.typesImport, models.validateImport)
+	servicesFile, err := render(tmpl, "services.go.tmpl", map[string]interface{}{
+		"Header":   Header,
+		"Package":  "yourapi",
+		"Imports":  std,
+		"Module":   module,
+		"Services": services,
+	})
+	if err != nil {
+		return nil, err
+	}
+	errorCodes, err := buildErrorCodes(cfg.Spec)
+	if err != nil {
+		return nil, err
+	}
+	errorsFile, err := render(tmpl, "errors.go.tmpl", map[string]interface{}{
+		"Header":     Header,
+		"Package":    "yourapi",
+		"ErrorCodes": errorCodes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	environments := buildEnvironments(cfg.Spec)
+	environmentsFile, err := render(tmpl, "environments.go.tmpl", map[string]interface{}{
+		"Header":       Header,
+		"Package":      "yourapi",
+		"Environments": environments,
+	})
+	if err != nil {
+		return nil, err
+	}
+	webhooksFile, err := render(tmpl, "webhooks.go.tmpl", map[string]interface{}{
+		"Header":       Header,
+		"ModelsImport": cfg.ModulePath + "/" + cfg.ModelsPackage,
+		"Webhooks":     webhooks,
+	})
+	if err != nil {
+		return nil, err
+	}
+	files := []File{
+		{Path: path.Join(cfg.ModelsPackage, "models_gen.go"), Content: modelsFile},
+		{Path: "services_gen.go", Content: servicesFile},
+		{Path: "errors_gen.go", Content: errorsFile},
+		{Path: "environments_gen.go", Content: environmentsFile},
+		{Path: "webhooks/events_gen.go", Content: webhooksFile},
+	}
+	baseURL := "https://api.example.com"
+	if len(cfg.Spec.Servers) > 0 {
+		baseURL = cfg.Spec.Servers[0].URL
+	}
+	for _, ex := range models.buildExamples(services, modelsPkg, cfg.ModulePath+"/"+cfg.ModelsPackage) {
+		content, err := render(tmpl, "example_test.go.tmpl", map[string]interface{}{
+			"Header":     Header,
+			"ModulePath": cfg.ModulePath,
+			"BaseURL":    baseURL,
+			"Example":    ex,
+		})
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: ex.Path, Content: content})
+	}
+	for _, name := range extra {
+		content, err := render(tmpl, name, map[string]interface{}{
+			"Header":       Header,
+			"Package":      "yourapi",
+			"ModulePath":   cfg.ModulePath,
+			"ModelsImport": cfg.ModulePath + "/" + cfg.ModelsPackage,
+			"Models":       models.sorted(),
+			"Services":     services,
+			"Webhooks":     webhooks,
+			"ErrorCodes":   errorCodes,
+			"Environments": environments,
+		})
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: strings.TrimSuffix(name, ".tmpl"), Content: content})
+	}
+	return files, nil
+}
+
+// parseTemplates parses the built-in templates, then the overrides in
+// custom. It returns the names of the extra file templates custom adds.
+func parseTemplates(custom fs.FS) (*template.Template, []string, error) {
+	tmpl := template.New("")
+	tmpl.Funcs(template.FuncMap{
+		"add": func(a, b int) int { return a + b },
+		// include renders a template to a string, so a template can test
+		// whether a block is empty
+		"include": func(name string, data interface{}) (string, error) {
+			var buf bytes.Buffer
+			err := tmpl.ExecuteTemplate(&buf, name, data)
+			return strings.TrimSpace(buf.String()), err
+		},
+	})
+	if _, err := tmpl.ParseFS(templateFS, "templates/*.tmpl"); err != nil {
+		return nil, nil, fmt.Errorf("codegen: parsing templates: %w", err)
+	}
+	if custom == nil {
+		return tmpl, nil, nil
+	}
+	// Keep each built-in block reachable as "default.<name>", so an
+	// override can wrap the original instead of copying it
+	for _, t := range tmpl.Templates() {
+		if name := t.Name(); name != "" && !strings.HasSuffix(name, ".tmpl") && t.Tree != nil {
+			if _, err := tmpl.AddParseTree("default."+name, t.Tree.Copy()); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var extra []string
+	err := fs.WalkDir(custom, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(name, ".tmpl") {
+			return err
+		}
+		src, err := fs.ReadFile(custom, name)
+		if err != nil {
+			return err
+		}
+		if _, err := tmpl.New(name).Parse(string(src)); err != nil {
+			return fmt.Errorf("codegen: parsing template %s: %w", name, err)
+		}
+		if strings.HasSuffix(name, ".go.tmpl") && !builtinFiles[name] {
+			extra = append(extra, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return tmpl, extra, nil
+}
+
+// render executes a template and gofmts the result
+func render(tmpl *template.Template, name string, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, fmt.Errorf("codegen: executing %s: %w", name, err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: formatting %s: %w\n%s", name, err, buf.Bytes())
+	}
+	return src, nil
+}
+
+// WriteFiles writes generated files under the module root out, leaving
+// files whose content is unchanged untouched so their timestamps and diffs
+// stay quiet. It returns the paths it wrote.
+func WriteFiles(out string, files []File) ([]string, error) {
+	var written []string
+	for _, f := range files {
+		p := filepath.Join(out, filepath.FromSlash(f.Path))
+		changed, err := writeIfChanged(p, f.Content)
+		if err != nil {
+			return written, err
+		}
+		if changed {
+			written = append(written, f.Path)
+		}
+	}
+	return written, nil
+}
+
+// writeIfChanged writes content to path unless it already holds it
+func writeIfChanged(path string, content []byte) (bool, error) {
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, content) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ModulePath reads the module path from a go.mod file
+func ModulePath(goMod string) (string, error) {
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return "", fmt.Errorf("reading module path: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no module directive in %s", goMod)
+}
@@ -0,0 +1,53 @@
+package codegen
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+)
+
+// Environment is a deployment of the API listed in the spec's servers
+type Environment struct {
+	// Name is the Go constant, e.g. "EnvironmentSandbox"
+	Name string
+	// Key is the name the environment is looked up by, e.g. "sandbox"
+	Key string
+	URL string
+	Doc string
+}
+
+// buildEnvironments returns an Environment per absolute server URL of the
+// spec, in spec order, named after the server's description or else its
+// host. Templated URLs are skipped: they need variables filled in.
+func buildEnvironments(doc *openapi.Document) []*Environment {
+	var out []*Environment
+	names := make(map[string]bool)
+	for _, s := range doc.Servers {
+		u, err := url.Parse(s.URL)
+		if err != nil || u.Scheme == "" || u.Host == "" || strings.Contains(s.URL, "{") {
+			continue
+		}
+		base := GoName(s.Description)
+		if base == "" {
+			base = GoName(u.Host)
+		}
+		name := "Environment" + base
+		for i := 2; names[name]; i++ {
+			name = "Environment" + base + strconv.Itoa(i)
+		}
+		names[name] = true
+		e := &Environment{
+			Name: name,
+			Key:  strings.ToLower(strings.TrimPrefix(name, "Environment")),
+			URL:  strings.TrimSuffix(s.URL, "/"),
+		}
+		e.Doc = "\t// " + name + " is the API at " + u.Host + "\n"
+		if s.Description != "" {
+			e.Doc = comment("\t", name+" is the", s.Description+" environment")
+		}
+		out = append(out, e)
+	}
+	return out
+}
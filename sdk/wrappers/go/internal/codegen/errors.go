@@ -0,0 +1,129 @@
+package codegen
+
+import (
+	"sort"
+	"strconv"
+	"unicode"
+
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+)
+
+// ErrorCode is an error code documented by the API
+type ErrorCode struct {
+	// Name is the Go constant, e.g. "ErrCodeInsufficientFunds"
+	Name string
+	// Code is the value of APIError.Code, e.g. "INSUFFICIENT_FUNDS"
+	Code string
+	Doc  string
+	// Status is the HTTP status the code comes with, 0 when it varies or is
+	// not known
+	Status      int
+	Description string
+}
+
+// buildErrorCodes collects the error codes the spec documents, from the
+// x-error-codes extension of responses and from enums on the code property
+// of error bodies. Codes are sorted by value.
+func buildErrorCodes(doc *openapi.Document) ([]*ErrorCode, error) {
+	codes := make(map[string]*ErrorCode)
+	// status -1 marks a code seen with conflicting statuses
+	add := func(code, description string, status int) {
+		if code == "" {
+			return
+		}
+		c, ok := codes[code]
+		if !ok {
+			codes[code] = &ErrorCode{Code: code, Status: status, Description: description}
+			return
+		}
+		if c.Description == "" {
+			c.Description = description
+		}
+		if c.Status == 0 {
+			c.Status = status
+		} else if status != 0 && status != c.Status {
+			c.Status = -1
+		}
+	}
+	addResponse := func(r *openapi.Response, status int) error {
+		resp, err := doc.Response(r)
+		if err != nil || resp == nil {
+			return err
+		}
+		for _, c := range resp.ErrorCodes {
+			add(c.Code, c.Description, status)
+		}
+		schema, ok := openapi.JSONSchema(resp.Content)
+		if !ok {
+			return nil
+		}
+		if schema, err = doc.Schema(schema); err != nil || schema == nil {
+			return err
+		}
+		prop, err := doc.Schema(schema.Properties["code"])
+		if err != nil || prop == nil {
+			return err
+		}
+		for _, v := range prop.Enum {
+			if s, ok := v.(string); ok {
+				add(s, "", status)
+			}
+		}
+		return nil
+	}
+
+	for _, path := range doc.OrderedPaths() {
+		for _, op := range doc.Paths[path].Operations() {
+			for key, r := range op.Responses {
+				status, err := strconv.Atoi(key)
+				if err != nil {
+					status = 0
+				} else if status < 400 {
+					continue
+				}
+				if err := addResponse(r, status); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	// Shared responses document codes even when no operation uses them
+	for _, r := range doc.Components.Responses {
+		if err := addResponse(r, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]*ErrorCode, 0, len(codes))
+	for _, c := range codes {
+		if c.Status < 0 {
+			c.Status = 0
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	names := make(map[string]bool)
+	for _, c := range out {
+		name := "ErrCode" + GoName(c.Code)
+		for i := 2; names[name]; i++ {
+			name = "ErrCode" + GoName(c.Code) + strconv.Itoa(i)
+		}
+		names[name] = true
+		c.Name = name
+		c.Doc = comment("\t", name+" is returned when", lowerFirst(c.Description))
+		if c.Doc == "" {
+			c.Doc = "\t// " + name + " is the " + c.Code + " error code\n"
+		}
+	}
+	return out, nil
+}
+
+// lowerFirst lowercases the first letter of a sentence, leaving acronyms
+// such as "API" alone
+func lowerFirst(s string) string {
+	r := []rune(s)
+	if len(r) > 1 && unicode.IsUpper(r[0]) && unicode.IsLower(r[1]) {
+		r[0] = unicode.ToLower(r[0])
+	}
+	return string(r)
+}
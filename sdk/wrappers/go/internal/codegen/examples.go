@@ -0,0 +1,218 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+	"github.com/devdraft/devdraft-sdk-go/types"
+)
+
+// Example is the godoc example of one method, rendered to its own
+// example_*_test.go file
+type Example struct {
+	// Path is the file's path relative to the module root
+	Path    string
+	Service *Service
+	Method  *Method
+	// Args are the method's arguments after ctx, as Go expressions
+	Args string
+	// Imports are the standard library imports; the SDK's own packages
+	// follow in ModuleImports
+	Imports       []string
+	ModuleImports []string
+}
+
+// exampleMaxDepth bounds how deeply nested models are filled in
+const exampleMaxDepth = 3
+
+// buildExamples returns an example for every method. It runs once all
+// models exist, since argument values are built from their fields.
+func (ms *modelSet) buildExamples(services []*Service, modelsPkg, modelsImport string) []*Example {
+	var out []*Example
+	for _, svc := range services {
+		for _, m := range svc.Methods {
+			var args []string
+			for _, a := range m.PathArgs {
+				args = append(args, ms.exampleArg(a.Type, a.schema, modelsPkg))
+			}
+			if m.Body != nil {
+				args = append(args, ms.exampleArg(m.Body.Type, m.Body.schema, modelsPkg))
+			}
+			if m.Params != nil {
+				args = append(args, ms.exampleParams(m.Params, modelsPkg))
+			}
+			ex := &Example{
+				Path:    "example_" + snakeName(svc.Field) + "_" + snakeName(m.Name) + "_test.go",
+				Service: svc,
+				Method:  m,
+				Args:    strings.Join(args, ", "),
+			}
+			ex.Imports = []string{"context", "fmt", "log", "os"}
+			if strings.Contains(ex.Args, "time.") {
+				ex.Imports = append(ex.Imports, "time")
+			}
+			if strings.Contains(ex.Args, modelsPkg+".") {
+				ex.ModuleImports = append(ex.ModuleImports, modelsImport)
+			}
+			if strings.Contains(ex.Args, "types.") {
+				ex.ModuleImports = append(ex.ModuleImports, ms.typesImport)
+			}
+			out = append(out, ex)
+		}
+	}
+	return out
+}
+
+// snakeName lowercases an identifier, separating its words with
+// underscores
+func snakeName(name string) string {
+	return strings.ToLower(strings.Join(words(name), "_"))
+}
+
+// exampleArg returns a value of type t for an argument, falling back to the
+// zero value when none can be made up
+func (ms *modelSet) exampleArg(t string, s *openapi.Schema, modelsPkg string) string {
+	if v, ok := ms.exampleValue(strings.TrimPrefix(t, "*"), s, modelsPkg, 0); ok {
+		if strings.HasPrefix(t, "*") {
+			return "&" + v
+		}
+		return v
+	}
+	if strings.HasPrefix(t, "*") || isNilable(t) {
+		return "nil"
+	}
+	return t + "{}"
+}
+
+// exampleParams returns the params argument, setting only the required
+// fields, or nil when none are required
+func (ms *modelSet) exampleParams(p *Params, modelsPkg string) string {
+	var fields []string
+	for _, f := range p.Fields {
+		if !f.Required {
+			continue
+		}
+		if v, ok := ms.exampleValue(f.Type, f.schema, modelsPkg, 0); ok {
+			fields = append(fields, f.Name+": "+v)
+		}
+	}
+	if len(fields) == 0 {
+		return "nil"
+	}
+	return "&yourapi." + p.Name + "{" + strings.Join(fields, ", ") + "}"
+}
+
+// exampleValue returns a Go expression of type t, taken from the spec's
+// example where there is one
+func (ms *modelSet) exampleValue(t string, s *openapi.Schema, modelsPkg string, depth int) (string, bool) {
+	if m := ms.models[strings.TrimPrefix(t, modelsPkg+".")]; m != nil {
+		return ms.exampleModel(m, modelsPkg, depth)
+	}
+	if s != nil && s.Ref != "" {
+		return "", false
+	}
+	var example interface{}
+	if s != nil {
+		example = s.Example
+	}
+	switch {
+	case t == "string":
+		if str, ok := example.(string); ok {
+			return strconv.Quote(str), true
+		}
+		format := ""
+		if s != nil {
+			format = s.Format
+		}
+		return strconv.Quote(exampleString(format)), true
+	case isInteger(t) || t == "float32" || t == "float64":
+		if n, ok := example.(float64); ok {
+			return formatNumber(n), true
+		}
+		if s != nil && s.Minimum != nil {
+			return formatNumber(*s.Minimum), true
+		}
+		return "1", true
+	case t == "time.Time":
+		return "time.Now()", true
+	case t == "types.Decimal" || t == "types.DecimalString":
+		amount := "1"
+		if n, ok := example.(float64); ok {
+			amount = formatNumber(n)
+		} else if str, ok := example.(string); ok && validDecimal(str) {
+			amount = str
+		} else if s != nil && s.Minimum != nil {
+			amount = formatNumber(*s.Minimum)
+		}
+		if t == "types.DecimalString" {
+			return "types.DecimalString{Decimal: types.MustDecimal(" + strconv.Quote(amount) + ")}", true
+		}
+		return "types.MustDecimal(" + strconv.Quote(amount) + ")", true
+	case t == "types.Date":
+		return "types.DateOf(time.Now())", true
+	case t == "types.UnixTime", t == "types.UnixMilliTime":
+		return t + "{Time: time.Now()}", true
+	case strings.HasPrefix(t, "[]") && t != "[]byte":
+		var items *openapi.Schema
+		if s != nil {
+			items = s.Items
+		}
+		item := strings.TrimPrefix(t, "[]")
+		if v, ok := ms.exampleValue(item, items, modelsPkg, depth+1); ok {
+			return ms.qualify(t, modelsPkg) + "{" + v + "}", true
+		}
+	}
+	return "", false
+}
+
+// exampleModel returns a composite literal of a model with its required
+// fields set, or the first constant of an enum
+func (ms *modelSet) exampleModel(m *Model, modelsPkg string, depth int) (string, bool) {
+	switch m.Kind {
+	case "enum":
+		return modelsPkg + "." + m.Values[0].Name, true
+	case "struct":
+	default:
+		return "", false
+	}
+	if depth >= exampleMaxDepth {
+		return modelsPkg + "." + m.Name + "{}", true
+	}
+	var fields []string
+	for _, f := range m.Fields {
+		if !f.Required || f.schema == nil || f.schema.ReadOnly || strings.HasPrefix(f.Type, "*") || strings.HasPrefix(f.Type, "types.Nullable[") {
+			continue
+		}
+		if v, ok := ms.exampleValue(f.Type, f.schema, modelsPkg, depth+1); ok {
+			fields = append(fields, f.Name+": "+v)
+		}
+	}
+	return fmt.Sprintf("%s.%s{%s}", modelsPkg, m.Name, strings.Join(fields, ", ")), true
+}
+
+// exampleString returns a placeholder valid for a string format
+func exampleString(format string) string {
+	switch format {
+	case "email":
+		return "jane@example.com"
+	case "uuid":
+		return "123e4567-e89b-12d3-a456-426614174000"
+	case "uri", "url":
+		return "https://example.com"
+	case "date":
+		return "2024-01-31"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	}
+	return "example"
+}
+
+// validDecimal reports whether s parses as a types.Decimal
+func validDecimal(s string) bool {
+	_, err := types.ParseDecimal(s)
+	return err == nil
+}
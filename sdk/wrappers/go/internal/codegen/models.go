@@ -0,0 +1,575 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+)
+
+// Model is a generated named type
+type Model struct {
+	Name string
+	// Doc is the rendered doc comment, including comment markers
+	Doc string
+	// Kind is "struct", "enum", "union" or "alias"
+	Kind   string
+	Fields []*Field
+	// Base is the underlying type of an enum or alias
+	Base   string
+	Values []EnumValue
+	// Variants are the member types of a union
+	Variants []*Variant
+	// Validations are the statements of a struct's Validate method
+	Validations []string
+	// Discriminator is the JSON property telling a union's variants apart,
+	// "" when they are told apart by trying each in turn
+	Discriminator string
+	// UnknownField names the struct field keeping JSON members missing from
+	// the spec, "" unless Config.PreserveUnknown is set
+	UnknownField string
+}
+
+// NullableFields returns the struct's types.Nullable fields, which its
+// generated MarshalJSON leaves out when unset
+func (m *Model) NullableFields() []*Field {
+	var out []*Field
+	for _, f := range m.Fields {
+		if strings.HasPrefix(f.Type, "types.Nullable[") {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Variant is a member type of a generated union
+type Variant struct {
+	// Name is used in the accessor names, e.g. "Card" for AsCard
+	Name string
+	Type string
+	// Values are the discriminator values selecting this variant
+	Values []string
+	// UnknownField is the variant model's UnknownField; members it keeps
+	// mean the value does not fit the variant exactly
+	UnknownField string
+}
+
+// Field is a struct field of a generated model
+type Field struct {
+	Name     string
+	JSONName string
+	Type     string
+	Tag      string
+	Doc      string
+	Required bool
+	Nullable bool
+
+	schema *openapi.Schema
+}
+
+// EnumValue is a constant of a generated enum
+type EnumValue struct {
+	Name  string
+	Value string
+}
+
+// modelSet accumulates the models generated from a spec
+type modelSet struct {
+	doc    *openapi.Document
+	models map[string]*Model
+	// typesImport is the import path of the package holding Nullable
+	typesImport string
+	// validateImport is the import path of the validate package
+	validateImport string
+	// pkg is the models package name, qualifying model types used
+	// elsewhere
+	pkg string
+}
+
+func buildModels(doc *openapi.Document) (*modelSet, error) {
+	ms := &modelSet{doc: doc, models: make(map[string]*Model)}
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// Reserve component names first so inline types never take them
+	for _, name := range names {
+		ms.models[GoName(name)] = nil
+	}
+	for _, name := range names {
+		goName := GoName(name)
+		if err := ms.named(goName, doc.Components.Schemas[name]); err != nil {
+			return nil, fmt.Errorf("codegen: schema %s: %w", name, err)
+		}
+		if m := ms.models[goName]; m.Doc == "" {
+			m.Doc = "// " + goName + " is the " + name + " schema\n"
+		}
+	}
+	return ms, nil
+}
+
+func (ms *modelSet) sorted() []*Model {
+	out := make([]*Model, 0, len(ms.models))
+	for _, m := range ms.models {
+		if m != nil {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// preserveUnknown gives every struct model a field keeping the JSON
+// members its schema does not list
+func (ms *modelSet) preserveUnknown() {
+	for _, m := range ms.models {
+		if m == nil || m.Kind != "struct" {
+			continue
+		}
+		name := "UnknownFields"
+		for i := 2; m.hasField(name); i++ {
+			name = "UnknownFields" + strconv.Itoa(i)
+		}
+		m.UnknownField = name
+	}
+	for _, m := range ms.models {
+		if m == nil {
+			continue
+		}
+		for _, v := range m.Variants {
+			if vm := ms.models[v.Type]; vm != nil {
+				v.UnknownField = vm.UnknownField
+			}
+		}
+	}
+}
+
+func (m *Model) hasField(name string) bool {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// importList returns the packages the models use. It looks at the final
+// types rather than tracking imports while building, since services also
+// resolve types through the set without adding them to the models.
+func (ms *modelSet) importList() []string {
+	imports := make(map[string]bool)
+	for _, m := range ms.models {
+		if m == nil {
+			continue
+		}
+		switch m.Kind {
+		case "enum":
+			imports["encoding/json"] = true
+			imports["fmt"] = true
+		case "union":
+			imports["bytes"] = true
+			imports["encoding/json"] = true
+			imports["errors"] = true
+			// Only the discriminator checks format their errors
+			if m.Discriminator != "" {
+				imports["fmt"] = true
+			}
+		}
+		types := []string{m.Base}
+		for _, f := range m.Fields {
+			types = append(types, f.Type)
+		}
+		for _, v := range m.Variants {
+			types = append(types, v.Type)
+		}
+		for _, t := range types {
+			if strings.Contains(t, "time.") {
+				imports["time"] = true
+			}
+			if strings.Contains(t, "types.") {
+				imports[ms.typesImport] = true
+			}
+		}
+		if m.NullableFields() != nil {
+			imports["encoding/json"] = true
+		}
+		if m.UnknownField != "" {
+			imports["encoding/json"] = true
+			imports[ms.typesImport] = true
+		}
+		if len(m.Validations) > 0 {
+			imports[ms.validateImport] = true
+		}
+	}
+	out := make([]string, 0, len(imports))
+	for imp := range imports {
+		out = append(out, imp)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// splitImports separates standard library imports from the rest, for
+// goimports-style grouping
+func splitImports(imports []string) (std, other []string) {
+	for _, imp := range imports {
+		if first, _, _ := strings.Cut(imp, "/"); strings.Contains(first, ".") {
+			other = append(other, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	return std, other
+}
+
+// unique returns name, or name with a numeric suffix if it is taken
+func (ms *modelSet) unique(name string) string {
+	if _, taken := ms.models[name]; !taken {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := name + strconv.Itoa(i)
+		if _, taken := ms.models[candidate]; !taken {
+			return candidate
+		}
+	}
+}
+
+// named generates the model for a named schema
+func (ms *modelSet) named(name string, s *openapi.Schema) error {
+	m := &Model{Name: name, Doc: comment("", name, s.Description)}
+	ms.models[name] = m
+
+	switch {
+	case s.Ref != "":
+		target := GoName(openapi.RefName(s.Ref))
+		m.Kind, m.Base = "alias", target
+	case isUnion(s):
+		return ms.union(m, s)
+	case len(s.Enum) > 0 && (s.Type == "string" || s.Type == ""):
+		m.Kind, m.Base = "enum", "string"
+		m.Values = enumValues(name, s.Enum)
+		if len(m.Values) == 0 {
+			// No string values to name; a plain string type is all we can offer
+			m.Kind = "alias"
+			break
+		}
+	case isObject(s) || len(s.AllOf) > 0:
+		m.Kind = "struct"
+		fields, err := ms.fields(name, s)
+		if err != nil {
+			return err
+		}
+		m.Fields = fields
+	default:
+		t, err := ms.typeOf(s, name+"Item")
+		if err != nil {
+			return err
+		}
+		m.Kind, m.Base = "alias", t
+	}
+	return nil
+}
+
+func isObject(s *openapi.Schema) bool {
+	return len(s.Properties) > 0 || (s.Type == "object" && s.AdditionalProperties == nil && !s.NoAdditionalProperties)
+}
+
+// fields returns the struct fields of an object schema, flattening allOf
+func (ms *modelSet) fields(owner string, s *openapi.Schema) ([]*Field, error) {
+	var fields []*Field
+	seen := make(map[string]bool)
+	var add func(s *openapi.Schema) error
+	add = func(s *openapi.Schema) error {
+		resolved, err := ms.doc.Schema(s)
+		if err != nil {
+			return err
+		}
+		for _, part := range resolved.AllOf {
+			if err := add(part); err != nil {
+				return err
+			}
+		}
+		for _, prop := range propertyNames(resolved) {
+			if seen[prop] {
+				continue
+			}
+			seen[prop] = true
+			f, err := ms.field(owner, prop, resolved.Properties[prop], resolved.IsRequired(prop))
+			if err != nil {
+				return err
+			}
+			fields = append(fields, f)
+		}
+		return nil
+	}
+	if err := add(s); err != nil {
+		return nil, err
+	}
+
+	// Disambiguate properties that map to the same Go name, e.g. "id" and "ID"
+	names := make(map[string]int)
+	for _, f := range fields {
+		names[f.Name]++
+		if n := names[f.Name]; n > 1 {
+			f.Name += strconv.Itoa(n)
+		}
+	}
+	return fields, nil
+}
+
+// propertyNames returns an object's properties in document order
+func propertyNames(s *openapi.Schema) []string {
+	if len(s.PropertyOrder) == len(s.Properties) {
+		return s.PropertyOrder
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (ms *modelSet) field(owner, prop string, s *openapi.Schema, required bool) (*Field, error) {
+	name := GoName(prop)
+	if name == "" {
+		name = "Field"
+	}
+	t, err := ms.typeOf(s, owner+name)
+	if err != nil {
+		return nil, fmt.Errorf("property %s: %w", prop, err)
+	}
+	f := &Field{
+		Name:     name,
+		JSONName: prop,
+		Required: required,
+		Nullable: s.Nullable,
+		Doc:      comment("\t", name, s.Description),
+		schema:   s,
+	}
+	omitempty := !required
+	switch {
+	case !required && s.Nullable:
+		// Absent, null and a value are all distinct, which a pointer cannot
+		// represent
+		t = "types.Nullable[" + t + "]"
+	case !isNilable(t) && (!required || s.Nullable):
+		t = "*" + t
+	}
+	f.Type = t
+	f.Tag = `json:"` + prop
+	if omitempty {
+		f.Tag += ",omitempty"
+	}
+	f.Tag += `"`
+	return f, nil
+}
+
+// isNilable reports whether a Go type already has a nil value
+func isNilable(t string) bool {
+	return strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "interface{}" || strings.HasPrefix(t, "*")
+}
+
+// typeOf returns the Go type for a schema, generating an inline model named
+// hint for inline objects and enums
+func (ms *modelSet) typeOf(s *openapi.Schema, hint string) (string, error) {
+	if s == nil {
+		return "interface{}", nil
+	}
+	if s.Ref != "" {
+		name := openapi.RefName(s.Ref)
+		if _, ok := ms.doc.Components.Schemas[name]; !ok {
+			return "", fmt.Errorf("unknown schema %q", s.Ref)
+		}
+		return GoName(name), nil
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return ms.typeOf(s.AllOf[0], hint)
+	}
+
+	switch {
+	case len(s.Enum) > 0 && (s.Type == "string" || s.Type == ""):
+		return ms.inline(hint, s)
+	case len(s.Properties) > 0 || len(s.AllOf) > 0 || isUnion(s):
+		return ms.inline(hint, s)
+	}
+
+	if t := formatType(s.Type, s.Format); t != "" {
+		return t, nil
+	}
+	switch s.Type {
+	case "object":
+		if s.AdditionalProperties != nil {
+			value, err := ms.typeOf(s.AdditionalProperties, hint+"Value")
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + value, nil
+		}
+		return "map[string]interface{}", nil
+	case "array":
+		item, err := ms.typeOf(s.Items, hint+"Item")
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "time.Time", nil
+		case "byte", "binary":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		if s.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		if s.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	}
+	return "interface{}", nil
+}
+
+// formatType returns the types package type of a format time.Time and
+// float64 cannot represent, or "" for other formats
+func formatType(typ, format string) string {
+	switch {
+	case typ == "string" && format == "date":
+		return "types.Date"
+	case typ == "string" && (format == "decimal" || format == "money"):
+		return "types.DecimalString"
+	case typ != "integer" && typ != "number":
+		return ""
+	}
+	switch format {
+	case "unix-time", "unix-timestamp", "timestamp":
+		return "types.UnixTime"
+	case "unix-time-ms", "unix-millis", "timestamp-ms":
+		return "types.UnixMilliTime"
+	case "decimal", "money":
+		return "types.Decimal"
+	}
+	return ""
+}
+
+// inline generates a model for an inline object or enum schema
+func (ms *modelSet) inline(hint string, s *openapi.Schema) (string, error) {
+	name := ms.unique(hint)
+	if err := ms.named(name, s); err != nil {
+		return "", err
+	}
+	if m := ms.models[name]; m.Doc == "" {
+		m.Doc = "// " + name + " is generated from an inline schema\n"
+	}
+	return name, nil
+}
+
+// enumValues names the constants of an enum, e.g. CustomerStatusActive
+func enumValues(typeName string, values []interface{}) []EnumValue {
+	var out []EnumValue
+	used := make(map[string]bool)
+	for _, v := range values {
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+		suffix := GoName(str)
+		if suffix == "" {
+			suffix = "Empty"
+		}
+		name := typeName + suffix
+		for i := 2; used[name]; i++ {
+			name = typeName + suffix + strconv.Itoa(i)
+		}
+		used[name] = true
+		out = append(out, EnumValue{Name: name, Value: str})
+	}
+	return out
+}
+
+// isUnion reports whether a schema is a oneOf or anyOf without properties
+// of its own
+func isUnion(s *openapi.Schema) bool {
+	return (len(s.OneOf) > 0 || len(s.AnyOf) > 0) && len(s.Properties) == 0
+}
+
+// union fills m with the variants of a oneOf or anyOf schema
+func (ms *modelSet) union(m *Model, s *openapi.Schema) error {
+	members := s.OneOf
+	if len(members) == 0 {
+		members = s.AnyOf
+	}
+	m.Kind = "union"
+	if s.Discriminator != nil {
+		m.Discriminator = s.Discriminator.PropertyName
+	}
+
+	used := make(map[string]bool)
+	for i, member := range members {
+		t, err := ms.typeOf(member, m.Name+"Variant"+strconv.Itoa(i+1))
+		if err != nil {
+			return fmt.Errorf("variant %d: %w", i+1, err)
+		}
+		v := &Variant{Name: variantName(t), Type: t}
+		for n := 2; used[v.Name]; n++ {
+			v.Name = variantName(t) + strconv.Itoa(n)
+		}
+		used[v.Name] = true
+
+		if s.Discriminator != nil {
+			if member.Ref == "" {
+				return fmt.Errorf("discriminated variant %d must be a $ref", i+1)
+			}
+			for _, value := range sortedKeys(s.Discriminator.Mapping) {
+				target := s.Discriminator.Mapping[value]
+				if target == member.Ref || openapi.RefName(target) == openapi.RefName(member.Ref) || target == openapi.RefName(member.Ref) {
+					v.Values = append(v.Values, value)
+				}
+			}
+			if len(v.Values) == 0 {
+				// Without a mapping, the discriminator holds the schema name
+				v.Values = []string{openapi.RefName(member.Ref)}
+			}
+		}
+		m.Variants = append(m.Variants, v)
+	}
+	return nil
+}
+
+// variantName names a union variant after its type, e.g. "[]Card" becomes
+// "CardList"
+func variantName(t string) string {
+	switch {
+	case strings.HasPrefix(t, "[]"):
+		return variantName(strings.TrimPrefix(t, "[]")) + "List"
+	case strings.HasPrefix(t, "map["):
+		return "Map"
+	case t == "interface{}":
+		return "Any"
+	case strings.HasPrefix(t, "time."):
+		return "Time"
+	case strings.HasPrefix(t, "types."):
+		return strings.TrimPrefix(t, "types.")
+	}
+	return GoName(t)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
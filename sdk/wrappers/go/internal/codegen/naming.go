@@ -0,0 +1,120 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms are rendered in all caps, following Go naming conventions
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "CSV": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "JWT": true, "OK": true, "PDF": true,
+	"QPS": true, "RAM": true, "RPC": true, "SKU": true, "SLA": true, "SMS": true,
+	"SQL": true, "SSH": true, "SSO": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "UID": true, "URI": true, "URL": true, "UTC": true,
+	"UTF8": true, "UUID": true, "VAT": true, "XML": true,
+}
+
+// words splits an identifier such as "customer_id", "createdAt" or
+// "HTTPStatus" into its words
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = cur[:0]
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if len(cur) > 0 && unicode.IsUpper(r) {
+			prev := cur[len(cur)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Split fooBar and the end of an acronym: HTTPStatus -> HTTP Status
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return out
+}
+
+// GoName returns the exported Go identifier for a spec name, e.g.
+// "customerId" -> "CustomerID", "next_cursor" -> "NextCursor"
+func GoName(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		upper := strings.ToUpper(w)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(strings.ToLower(w))
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	name := b.String()
+	if name == "" {
+		return ""
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "N" + name
+	}
+	return name
+}
+
+// lowerName returns the unexported form of a Go name, e.g. "CustomerID" ->
+// "customerID", "ID" -> "id"
+func lowerName(name string) string {
+	r := []rune(name)
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) {
+		// Keep the last capital of a leading acronym when a word follows
+		if i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+		i++
+	}
+	s := string(r)
+	if goKeywords[s] {
+		s += "_"
+	}
+	return s
+}
+
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+}
+
+// comment renders a spec description as a Go doc comment for name,
+// wrapped at 77 columns, or "" when there is no description
+func comment(indent, name, description string) string {
+	description = strings.TrimSuffix(strings.Join(strings.Fields(description), " "), ".")
+	if description == "" {
+		return ""
+	}
+	var b strings.Builder
+	line := indent + "//"
+	for _, w := range strings.Fields(name + " " + description) {
+		if len(line)+1+len(w) > 77 && line != indent+"//" {
+			b.WriteString(line + "\n")
+			line = indent + "//"
+		}
+		line += " " + w
+	}
+	b.WriteString(line + "\n")
+	return b.String()
+}
@@ -0,0 +1,667 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+)
+
+// Service groups the operations sharing an OpenAPI tag
+type Service struct {
+	// Field is the name of the service on Client, e.g. "Customers"
+	Field string
+	// Type is the service struct, e.g. "CustomersService"
+	Type    string
+	Tag     string
+	Doc     string
+	Methods []*Method
+}
+
+// Method is a generated service method for one operation
+type Method struct {
+	// Service is the type name of the method's receiver
+	Service     string
+	Name        string
+	Doc         string
+	OperationID string
+	// HTTPMethod is the net/http constant, e.g. "http.MethodGet"
+	HTTPMethod string
+	Verb       string
+	Path       string
+	// PathExpr is the Go string literal of the request's path template
+	PathExpr string
+	// PathParams is the Go expression of the PathParams expanding
+	// PathExpr, "" without path parameters
+	PathParams string
+	PathArgs   []*Arg
+	Params     *Params
+	// Body is the request body argument, nil without one
+	Body *Arg
+	// Result is the decoded response type, "" when there is none
+	Result string
+	// ResultValue is the type of the result variable
+	ResultValue string
+	// Pagination describes how to walk the pages of a list endpoint, nil
+	// for other operations
+	Pagination *Pagination
+	// Connect is the method's Connect procedure, nil when the SDK is
+	// generated without Connect support
+	Connect *Connect
+
+	// opName is the Go name of the operation, procedure its
+	// x-connect-procedure and pathKeys the spec names of PathArgs
+	opName    string
+	procedure string
+	pathKeys  []string
+}
+
+// Connect is the wiring of a method's ProtocolConnect branch
+type Connect struct {
+	// Procedure is the procedure's path, e.g.
+	// "/acme.v1.CustomersService/GetCustomer"
+	Procedure string
+	// Fields is the Go expression for the message fields set from the path
+	// arguments, "nil" when there are none
+	Fields string
+}
+
+// Pagination is the wiring of a generated ...Iter method
+type Pagination struct {
+	// Style is "cursor" or "page"
+	Style    string
+	ItemType string
+	// Items is the response field holding the page's items
+	Items string
+	// SetNext is the statement pointing params p at the page next
+	SetNext string
+	// Next is the expression for the following page's cursor or page
+	// number, from resp
+	Next string
+	// HasMore is the boolean expression telling whether the list continues
+	HasMore string
+}
+
+// Cursor parameters and response fields recognised on list endpoints
+var (
+	cursorParams     = []string{"cursor", "page_token", "pageToken", "starting_after", "after"}
+	nextCursorFields = []string{"nextCursor", "next_cursor", "nextPageToken", "next_page_token", "next"}
+	hasMoreFields    = []string{"hasMore", "has_more"}
+	itemsFields      = []string{"items", "data", "results"}
+	pageParams       = []string{"page"}
+	totalPagesFields = []string{"totalPages", "total_pages"}
+)
+
+// Arg is a method argument
+type Arg struct {
+	Name string
+	Type string
+	Doc  string
+
+	schema *openapi.Schema
+}
+
+// Params is the generated struct holding an operation's optional query and
+// header parameters
+type Params struct {
+	Name   string
+	Fields []*ParamField
+	// Validations are the statements of the struct's Validate method
+	Validations []string
+}
+
+// HasQuery reports whether any parameter is sent in the query string
+func (p *Params) HasQuery() bool { return p.has("query") }
+
+// HasHeader reports whether any parameter is sent as a header
+func (p *Params) HasHeader() bool { return p.has("header") }
+
+func (p *Params) has(in string) bool {
+	if p == nil {
+		return false
+	}
+	for _, f := range p.Fields {
+		if f.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// ParamField is a field of a Params struct
+type ParamField struct {
+	Name     string
+	Type     string
+	In       string
+	Key      string
+	Doc      string
+	Required bool
+	// Tag is the field's struct tag, read by EncodeQuery
+	Tag string
+	// Stmt is the Go statement adding a header field to h
+	Stmt string
+
+	schema *openapi.Schema
+}
+
+// skippedHeaders are header parameters handled by request options instead
+// of generated arguments
+var skippedHeaders = map[string]bool{"idempotency-key": true}
+
+// buildServices groups the spec's operations into services by first tag,
+// registering any inline schemas they use with ms
+func buildServices(doc *openapi.Document, ms *modelSet, modelsPkg string) ([]*Service, error) {
+	services := make(map[string]*Service)
+	var order []string
+	for _, p := range doc.OrderedPaths() {
+		item := doc.Paths[p]
+		ops := item.Operations()
+		for _, verb := range openapi.Methods {
+			op := ops[verb]
+			if op == nil {
+				continue
+			}
+			tag := "default"
+			if len(op.Tags) > 0 {
+				tag = op.Tags[0]
+			}
+			svc := services[tag]
+			if svc == nil {
+				field := GoName(tag)
+				svc = &Service{Field: field, Type: field + "Service", Tag: tag}
+				svc.Doc = "// " + svc.Type + " provides the " + tag + " operations\n"
+				if d := doc.TagDescription(tag); d != "" {
+					svc.Doc += "//\n" + comment("", "", d)
+				}
+				services[tag] = svc
+				order = append(order, tag)
+			}
+			m, err := buildMethod(doc, ms, modelsPkg, svc, p, verb, item, op)
+			if err != nil {
+				return nil, fmt.Errorf("codegen: %s %s: %w", verb, p, err)
+			}
+			svc.Methods = append(svc.Methods, m)
+		}
+	}
+
+	out := make([]*Service, 0, len(order))
+	for _, tag := range order {
+		svc := services[tag]
+		dedupeMethodNames(svc)
+		out = append(out, svc)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out, nil
+}
+
+func buildMethod(doc *openapi.Document, ms *modelSet, modelsPkg string, svc *Service, path, verb string, item *openapi.PathItem, op *openapi.Operation) (*Method, error) {
+	opName := GoName(op.OperationID)
+	if opName == "" {
+		opName = GoName(strings.ToLower(verb) + " " + path)
+	}
+	m := &Method{
+		Service:     svc.Type,
+		Name:        methodName(opName, svc.Field),
+		OperationID: op.OperationID,
+		HTTPMethod:  "http.Method" + strings.ToUpper(verb[:1]) + strings.ToLower(verb[1:]),
+		Verb:        verb,
+		Path:        path,
+		opName:      opName,
+		procedure:   op.ConnectProcedure,
+	}
+	m.Doc = "// " + m.Name + " calls " + verb + " " + path + "\n"
+	if d := op.Description; d != "" || op.Summary != "" {
+		if d == "" {
+			d = op.Summary
+		}
+		m.Doc += "//\n" + comment("", "", d)
+	}
+	if op.Deprecated {
+		m.Doc += "//\n// Deprecated: the operation is deprecated in the API\n"
+	}
+
+	params, err := doc.OperationParameters(item, op)
+	if err != nil {
+		return nil, err
+	}
+	pathArgs := make(map[string]*Arg)
+	for _, p := range params {
+		switch p.In {
+		case "path":
+			t, err := ms.typeOf(p.Schema, svc.Field+m.Name+GoName(p.Name))
+			if err != nil {
+				return nil, err
+			}
+			arg := &Arg{Name: lowerName(GoName(p.Name)), Type: ms.qualify(t, modelsPkg), schema: p.Schema}
+			pathArgs[p.Name] = arg
+		case "query", "header":
+			if p.In == "header" && skippedHeaders[strings.ToLower(p.Name)] {
+				continue
+			}
+			if m.Params == nil {
+				m.Params = &Params{Name: svc.Field + m.Name + "Params"}
+			}
+			t, err := ms.typeOf(p.Schema, m.Params.Name+GoName(p.Name))
+			if err != nil {
+				return nil, err
+			}
+			t = ms.qualify(t, modelsPkg)
+			f := &ParamField{
+				Name:     GoName(p.Name),
+				In:       p.In,
+				Key:      p.Name,
+				Required: p.Required,
+				Doc:      comment("\t", GoName(p.Name), p.Description),
+				schema:   p.Schema,
+			}
+			if !strings.HasPrefix(t, "[]") && !p.Required && !isNilable(t) {
+				t = "*" + t
+			}
+			f.Type = t
+			if p.In == "query" {
+				f.Tag = fmt.Sprintf(`url:"%s"`, p.Name)
+				if !p.Required {
+					f.Tag = fmt.Sprintf(`url:"%s,omitempty"`, p.Name)
+				}
+			} else {
+				f.Tag = `url:"-"`
+				f.Stmt = headerStmt(f)
+			}
+			m.Params.Fields = append(m.Params.Fields, f)
+		}
+	}
+
+	expr, pathParams, args, err := pathExpression(path, pathArgs)
+	if err != nil {
+		return nil, err
+	}
+	m.PathExpr, m.PathParams, m.PathArgs = expr, pathParams, args
+	for _, arg := range args {
+		for key, a := range pathArgs {
+			if a == arg {
+				m.pathKeys = append(m.pathKeys, key)
+			}
+		}
+	}
+
+	if rb, err := doc.RequestBody(op.RequestBody); err != nil {
+		return nil, err
+	} else if rb != nil {
+		if schema, ok := openapi.JSONSchema(rb.Content); ok {
+			t, err := ms.typeOf(schema, svc.Field+m.Name+"Request")
+			if err != nil {
+				return nil, err
+			}
+			t = ms.qualify(t, modelsPkg)
+			if !rb.Required && !isNilable(t) {
+				t = "*" + t
+			}
+			m.Body = &Arg{Name: "body", Type: t, schema: schema}
+		}
+	}
+
+	schema, err := successSchema(doc, op)
+	if err != nil {
+		return nil, err
+	}
+	if schema != nil {
+		t, err := ms.typeOf(schema, svc.Field+m.Name+"Response")
+		if err != nil {
+			return nil, err
+		}
+		m.ResultValue = ms.qualify(t, modelsPkg)
+		m.Result = m.ResultValue
+		if !isNilable(m.Result) {
+			m.Result = "*" + m.Result
+		}
+		if verb == "GET" {
+			m.Pagination = pagination(ms, modelsPkg, t, m.Params)
+		}
+	}
+	return m, nil
+}
+
+// pagination recognises a cursor- or page-numbered list endpoint from its
+// parameters and response fields
+func pagination(ms *modelSet, modelsPkg, result string, params *Params) *Pagination {
+	model := ms.models[result]
+	if model == nil || model.Kind != "struct" || params == nil {
+		return nil
+	}
+	items := findField(model.Fields, itemsFields)
+	if items == nil || !strings.HasPrefix(items.Type, "[]") {
+		return nil
+	}
+	pg := &Pagination{
+		ItemType: ms.qualify(strings.TrimPrefix(items.Type, "[]"), modelsPkg),
+		Items:    "resp." + items.Name,
+	}
+
+	if param := findParam(params, cursorParams); param != nil {
+		next := findField(model.Fields, nextCursorFields)
+		if next == nil || valueType(next.Type) != "string" || strings.TrimPrefix(param.Type, "*") != "string" {
+			return nil
+		}
+		pg.Style = "cursor"
+		pg.SetNext = "p." + param.Name + " = next"
+		if strings.HasPrefix(param.Type, "*") {
+			pg.SetNext = "p." + param.Name + " = &next"
+		}
+		pg.Next = valueExpr("resp."+next.Name, next.Type)
+		pg.HasMore = pg.Next + ` != ""`
+		if more := findField(model.Fields, hasMoreFields); more != nil && valueType(more.Type) == "bool" {
+			pg.HasMore = valueExpr("resp."+more.Name, more.Type)
+		}
+		return pg
+	}
+
+	if param := findParam(params, pageParams); param != nil {
+		page := findField(model.Fields, pageParams)
+		total := findField(model.Fields, totalPagesFields)
+		paramType := strings.TrimPrefix(param.Type, "*")
+		if page == nil || total == nil || !isInteger(paramType) || !isInteger(valueType(page.Type)) || !isInteger(valueType(total.Type)) {
+			return nil
+		}
+		pg.Style = "page"
+		value := "n"
+		if strings.HasPrefix(param.Type, "*") {
+			value = "&n"
+		}
+		pg.SetNext = fmt.Sprintf("n, err := strconv.ParseInt(next, 10, 64)\nif err != nil {\nreturn Page[%s]{}, err\n}\np.%s = %s", pg.ItemType, param.Name, value)
+		if paramType != "int64" {
+			pg.SetNext = fmt.Sprintf("n64, err := strconv.ParseInt(next, 10, 64)\nif err != nil {\nreturn Page[%s]{}, err\n}\nn := %s(n64)\np.%s = %s", pg.ItemType, paramType, param.Name, value)
+		}
+		current := intExpr("resp."+page.Name, page.Type)
+		pg.Next = "strconv.FormatInt(" + current + "+1, 10)"
+		pg.HasMore = current + " < " + intExpr("resp."+total.Name, total.Type)
+		return pg
+	}
+	return nil
+}
+
+func intExpr(field, t string) string {
+	return "int64(" + valueExpr(field, t) + ")"
+}
+
+// valueType returns the type a pointer or types.Nullable field holds
+func valueType(t string) string {
+	if strings.HasPrefix(t, "types.Nullable[") {
+		return strings.TrimSuffix(strings.TrimPrefix(t, "types.Nullable["), "]")
+	}
+	return strings.TrimPrefix(t, "*")
+}
+
+// valueExpr reads a field of type t as its valueType, zero when unset
+func valueExpr(field, t string) string {
+	switch {
+	case strings.HasPrefix(t, "types.Nullable["):
+		var zero string
+		switch valueType(t) {
+		case "string":
+			zero = `""`
+		case "bool":
+			zero = "false"
+		default:
+			zero = "0"
+		}
+		return field + ".Or(" + zero + ")"
+	case strings.HasPrefix(t, "*"):
+		return "deref(" + field + ")"
+	}
+	return field
+}
+
+func isInteger(t string) bool {
+	return t == "int64" || t == "int32"
+}
+
+func findField(fields []*Field, names []string) *Field {
+	for _, name := range names {
+		for _, f := range fields {
+			if f.JSONName == name {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+func findParam(params *Params, names []string) *ParamField {
+	for _, name := range names {
+		for _, f := range params.Fields {
+			if f.In == "query" && f.Key == name {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// headerStmt returns the statement adding a header parameter field of p
+// to h
+func headerStmt(f *ParamField) string {
+	set := fmt.Sprintf("h[%q] = %%s", f.Key)
+	field := "p." + f.Name
+	switch {
+	case strings.HasPrefix(f.Type, "[]"):
+		elem := strings.TrimPrefix(f.Type, "[]")
+		return fmt.Sprintf("if len(%s) > 0 {\nvalues := make([]string, len(%s))\nfor i, v := range %s {\nvalues[i] = %s\n}\n%s\n}", field, field, field, formatValue("v", elem), fmt.Sprintf(set, `strings.Join(values, ",")`))
+	case strings.HasPrefix(f.Type, "*"):
+		return fmt.Sprintf("if %s != nil {\n%s\n}", field, fmt.Sprintf(set, formatValue("*"+field, strings.TrimPrefix(f.Type, "*"))))
+	case f.Type == "string":
+		return fmt.Sprintf("if %s != \"\" {\n%s\n}", field, fmt.Sprintf(set, field))
+	}
+	return fmt.Sprintf(set, formatValue(field, f.Type))
+}
+
+// formatValue converts a parameter value to its string form
+func formatValue(v, t string) string {
+	switch t {
+	case "string":
+		return v
+	case "time.Time":
+		return strings.TrimPrefix(v, "*") + ".Format(time.RFC3339)"
+	case "types.Date":
+		return strings.TrimPrefix(v, "*") + ".String()"
+	case "types.UnixTime":
+		return "strconv.FormatInt(" + strings.TrimPrefix(v, "*") + ".Unix(), 10)"
+	case "types.UnixMilliTime":
+		return "strconv.FormatInt(" + strings.TrimPrefix(v, "*") + ".UnixMilli(), 10)"
+	}
+	return "fmt.Sprint(" + v + ")"
+}
+
+// successSchema returns the JSON schema of the operation's first 2xx
+// response, if it has one
+func successSchema(doc *openapi.Document, op *openapi.Operation) (*openapi.Schema, error) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		resp, err := doc.Response(op.Responses[code])
+		if err != nil {
+			return nil, err
+		}
+		if schema, ok := openapi.JSONSchema(resp.Content); ok {
+			return schema, nil
+		}
+	}
+	return nil, nil
+}
+
+// methodName drops the resource name from an operation name, so
+// "ListCustomers" on the Customers service becomes "List"
+func methodName(opName, service string) string {
+	singular := singularize(service)
+	for _, noun := range []string{service, singular} {
+		if noun == "" {
+			continue
+		}
+		if i := strings.Index(opName, noun); i > 0 {
+			rest := opName[i+len(noun):]
+			// Only strip whole words
+			if rest == "" || (rest[0] >= 'A' && rest[0] <= 'Z') {
+				return opName[:i] + rest
+			}
+		}
+	}
+	return opName
+}
+
+func singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return strings.TrimSuffix(s, "s")
+	}
+	return s
+}
+
+// dedupeMethodNames restores the full operation name where stripping the
+// resource made two methods collide
+func dedupeMethodNames(svc *Service) {
+	count := make(map[string]int)
+	for _, m := range svc.Methods {
+		count[m.Name]++
+	}
+	for _, m := range svc.Methods {
+		if count[m.Name] > 1 && m.OperationID != "" {
+			old := m.Name
+			m.Name = GoName(m.OperationID)
+			m.Doc = strings.Replace(m.Doc, "// "+old+" ", "// "+m.Name+" ", 1)
+			if m.Params != nil {
+				m.Params.Name = svc.Field + m.Name + "Params"
+			}
+		}
+	}
+}
+
+// addConnect gives every method a Connect procedure in the protobuf
+// package pkg: the operation's x-connect-procedure, or by default
+// "/<pkg>.<Service>/<Operation>"
+func addConnect(services []*Service, pkg string) {
+	for _, svc := range services {
+		for _, m := range svc.Methods {
+			c := &Connect{Procedure: m.procedure, Fields: "nil"}
+			if c.Procedure == "" {
+				c.Procedure = "/" + pkg + "." + svc.Type + "/" + m.opName
+			}
+			var fields []string
+			seen := make(map[string]bool)
+			for i, arg := range m.PathArgs {
+				if key := m.pathKeys[i]; !seen[key] {
+					seen[key] = true
+					fields = append(fields, fmt.Sprintf("%q: %s", key, arg.Name))
+				}
+			}
+			if len(fields) > 0 {
+				c.Fields = "map[string]interface{}{" + strings.Join(fields, ", ") + "}"
+			}
+			m.Connect = c
+		}
+	}
+}
+
+// pathExpression returns the Go expressions of a path template and of the
+// PathParams expanding it, so the client reports the template rather than
+// each expanded path, and the path arguments in order
+func pathExpression(path string, args map[string]*Arg) (string, string, []*Arg, error) {
+	var entries []string
+	var ordered []*Arg
+	seen := make(map[string]bool)
+	rest := path
+	for {
+		open := strings.Index(rest, "{")
+		if open < 0 {
+			break
+		}
+		end := strings.Index(rest[open:], "}")
+		if end < 0 {
+			return "", "", nil, fmt.Errorf("unterminated parameter in %q", path)
+		}
+		name := rest[open+1 : open+end]
+		arg, ok := args[name]
+		if !ok {
+			return "", "", nil, fmt.Errorf("path parameter %q is not declared", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			entries = append(entries, fmt.Sprintf("%q: %s", name, arg.Name))
+			ordered = append(ordered, arg)
+		}
+		rest = rest[open+end+1:]
+	}
+	var params string
+	if len(entries) > 0 {
+		params = "PathParams{" + strings.Join(entries, ", ") + "}"
+	}
+	return fmt.Sprintf("%q", path), params, ordered, nil
+}
+
+// serviceImports returns the standard library and module imports used by
+// the generated services
+func serviceImports(services []*Service, modelsImport, modelsPkg, typesImport, validateImport string) (std, module []string) {
+	var code strings.Builder
+	for _, svc := range services {
+		for _, m := range svc.Methods {
+			code.WriteString(m.PathExpr + " " + m.Result + " ")
+			for _, a := range m.PathArgs {
+				code.WriteString(a.Type + " ")
+			}
+			if m.Body != nil {
+				code.WriteString(m.Body.Type + " ")
+			}
+			if m.Pagination != nil {
+				code.WriteString(m.Pagination.SetNext + " " + m.Pagination.Next + " ")
+			}
+			if m.Params != nil {
+				for _, f := range m.Params.Fields {
+					code.WriteString(f.Type + " " + f.Stmt + " ")
+				}
+				if len(m.Params.Validations) > 0 {
+					code.WriteString("validate.Errors ")
+				}
+			}
+		}
+	}
+	src := code.String()
+	std = []string{"context", "net/http"}
+	for pkg, prefix := range map[string]string{"fmt": "fmt.", "net/url": "url.", "strconv": "strconv.", "strings": "strings.", "time": "time."} {
+		if strings.Contains(src, prefix) {
+			std = append(std, pkg)
+		}
+	}
+	sort.Strings(std)
+	if strings.Contains(src, modelsPkg+".") {
+		module = append(module, modelsImport)
+	}
+	if strings.Contains(src, "types.") {
+		module = append(module, typesImport)
+	}
+	if strings.Contains(src, "validate.") {
+		module = append(module, validateImport)
+	}
+	return std, module
+}
+
+// qualify prefixes the model names in a type expression with the models
+// package, e.g. "[]Customer" -> "[]models.Customer"
+func (ms *modelSet) qualify(t, pkg string) string {
+	for _, prefix := range []string{"[]", "*", "map[string]"} {
+		if strings.HasPrefix(t, prefix) {
+			return prefix + ms.qualify(strings.TrimPrefix(t, prefix), pkg)
+		}
+	}
+	if _, ok := ms.models[t]; ok {
+		return pkg + "." + t
+	}
+	return t
+}
@@ -0,0 +1,144 @@
+package codegen
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+	"github.com/devdraft/devdraft-sdk-go/validate"
+)
+
+// addValidations fills in the checks of every struct model and Params
+// struct. It runs once all models exist, since checks on a field depend on
+// whether its type is a struct or an enum.
+func (ms *modelSet) addValidations(services []*Service) {
+	for _, m := range ms.models {
+		if m == nil || m.Kind != "struct" {
+			continue
+		}
+		for _, f := range m.Fields {
+			m.Validations = append(m.Validations, ms.checks(strconv.Quote(f.JSONName), "m."+f.Name, f.Type, f.schema, f.Required)...)
+		}
+	}
+	for _, svc := range services {
+		for _, method := range svc.Methods {
+			if method.Params == nil {
+				continue
+			}
+			for _, f := range method.Params.Fields {
+				method.Params.Validations = append(method.Params.Validations, ms.checks(strconv.Quote(f.Key), "p."+f.Name, f.Type, f.schema, f.Required)...)
+			}
+		}
+	}
+}
+
+// checks returns the statements validating expr, a value of Go type t
+// described by s. field is the Go expression naming it in errors.
+func (ms *modelSet) checks(field, expr, t string, s *openapi.Schema, required bool) []string {
+	if s == nil {
+		return nil
+	}
+	var out []string
+	requiredString := false
+	if required && !s.ReadOnly && !s.Nullable {
+		switch {
+		case t == "string":
+			out = append(out, "errs.Required("+field+", "+expr+` == "")`)
+			requiredString = true
+		case strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map["):
+			out = append(out, "errs.Required("+field+", "+expr+" == nil)")
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(t, "types.Nullable["):
+		if value := ms.valueChecks(field, "v", valueType(t), s); len(value) > 0 {
+			out = append(out, "if v, ok := "+expr+".Get(); ok {\n"+strings.Join(value, "\n")+"\n}")
+		}
+	case strings.HasPrefix(t, "*"):
+		if value := ms.valueChecks(field, "*"+expr, valueType(t), s); len(value) > 0 {
+			out = append(out, "if "+expr+" != nil {\n"+strings.Join(value, "\n")+"\n}")
+		}
+	case requiredString:
+		// An empty required string is only reported as missing
+		if value := ms.valueChecks(field, expr, t, s); len(value) > 0 {
+			out = append(out, "if "+expr+` != "" {`+"\n"+strings.Join(value, "\n")+"\n}")
+		}
+	default:
+		out = append(out, ms.valueChecks(field, expr, t, s)...)
+	}
+	return out
+}
+
+// valueChecks returns the statements validating a present value
+func (ms *modelSet) valueChecks(field, v, t string, s *openapi.Schema) []string {
+	// Named types carry their own checks
+	if m := ms.models[strings.TrimPrefix(t, ms.pkg+".")]; m != nil {
+		// Methods on value receivers are called through pointers directly
+		recv := strings.TrimPrefix(v, "*")
+		switch m.Kind {
+		case "struct":
+			return []string{"errs.Nested(" + field + ", " + recv + ".Validate())"}
+		case "enum":
+			return []string{"errs.Enum(" + field + ", string(" + v + "), " + recv + ".IsValid())"}
+		}
+		return nil
+	}
+	if s.Ref != "" {
+		return nil
+	}
+
+	var out []string
+	switch {
+	case t == "string":
+		if s.Format != "" && validate.KnownFormat(s.Format) {
+			out = append(out, "errs.Format("+field+", "+v+", "+strconv.Quote(s.Format)+")")
+		}
+		if s.MinLength != nil && *s.MinLength > 0 {
+			out = append(out, "errs.MinLength("+field+", "+v+", "+strconv.Itoa(*s.MinLength)+")")
+		}
+		if s.MaxLength != nil {
+			out = append(out, "errs.MaxLength("+field+", "+v+", "+strconv.Itoa(*s.MaxLength)+")")
+		}
+		if s.Pattern != "" {
+			// Patterns RE2 cannot compile are left to the server
+			if _, err := regexp.Compile(s.Pattern); err == nil {
+				out = append(out, "errs.Pattern("+field+", "+v+", "+strconv.Quote(s.Pattern)+")")
+			}
+		}
+	case isInteger(t) || t == "float32" || t == "float64":
+		if s.Minimum != nil {
+			out = append(out, "errs.Minimum("+field+", float64("+v+"), "+formatNumber(*s.Minimum)+", "+strconv.FormatBool(s.ExclusiveMinimum)+")")
+		}
+		if s.Maximum != nil {
+			out = append(out, "errs.Maximum("+field+", float64("+v+"), "+formatNumber(*s.Maximum)+", "+strconv.FormatBool(s.ExclusiveMaximum)+")")
+		}
+	case t == "types.Decimal" || t == "types.DecimalString":
+		if s.Minimum != nil {
+			out = append(out, "errs.Minimum("+field+", "+strings.TrimPrefix(v, "*")+".Float64(), "+formatNumber(*s.Minimum)+", "+strconv.FormatBool(s.ExclusiveMinimum)+")")
+		}
+		if s.Maximum != nil {
+			out = append(out, "errs.Maximum("+field+", "+strings.TrimPrefix(v, "*")+".Float64(), "+formatNumber(*s.Maximum)+", "+strconv.FormatBool(s.ExclusiveMaximum)+")")
+		}
+	case strings.HasPrefix(t, "[]") && t != "[]byte":
+		if s.MinItems != nil && *s.MinItems > 0 {
+			out = append(out, "errs.MinItems("+field+", len("+v+"), "+strconv.Itoa(*s.MinItems)+")")
+		}
+		if s.MaxItems != nil {
+			out = append(out, "errs.MaxItems("+field+", len("+v+"), "+strconv.Itoa(*s.MaxItems)+")")
+		}
+		item := strings.TrimPrefix(t, "[]")
+		// Lists of lists are not checked element by element
+		if s.Items != nil && !strings.HasPrefix(item, "[]") {
+			if checks := ms.valueChecks("validate.Index("+field+", i)", "item", item, s.Items); len(checks) > 0 {
+				out = append(out, "for i, item := range "+v+" {\n"+strings.Join(checks, "\n")+"\n}")
+			}
+		}
+	}
+	return out
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,56 @@
+package codegen
+
+import (
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+)
+
+// Webhook is an event the API sends to webhook endpoints
+type Webhook struct {
+	// Type is the event type, e.g. "customer.created"
+	Type string
+	// Name is the Go name of the event, e.g. "CustomerCreated"
+	Name string
+	Doc  string
+	// Payload is the qualified model type of the event body
+	Payload string
+}
+
+// buildWebhooks returns the spec's webhook events in document order,
+// adding their payload types to ms
+func buildWebhooks(doc *openapi.Document, ms *modelSet, modelsPkg string) ([]*Webhook, error) {
+	var hooks []*Webhook
+	for _, eventType := range doc.WebhookOrder {
+		item := doc.Webhooks[eventType]
+		if item == nil || item.Post == nil {
+			continue
+		}
+		op := item.Post
+		rb, err := doc.RequestBody(op.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		if rb == nil {
+			continue
+		}
+		schema, ok := openapi.JSONSchema(rb.Content)
+		if !ok {
+			continue
+		}
+
+		name := GoName(eventType)
+		t, err := ms.typeOf(schema, name+"Event")
+		if err != nil {
+			return nil, err
+		}
+		if m := ms.models[t]; m != nil && schema.Ref == "" && schema.Description == "" {
+			m.Doc = "// " + t + " is the payload of " + eventType + " webhooks\n"
+		}
+		hooks = append(hooks, &Webhook{
+			Type:    eventType,
+			Name:    name,
+			Doc:     comment("\t", name, "handles "+eventType+" events"),
+			Payload: ms.qualify(t, modelsPkg),
+		})
+	}
+	return hooks, nil
+}
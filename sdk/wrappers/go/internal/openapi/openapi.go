@@ -0,0 +1,420 @@
+// Package openapi reads the subset of OpenAPI 3.0 documents used by the
+// SDK's contract testing and code generation
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+	// ConnectPackage is the protobuf package of the API's Connect services,
+	// e.g. "acme.v1", from the x-connect-package extension
+	ConnectPackage string `json:"x-connect-package,omitempty"`
+	// PathOrder lists the path templates in document order
+	PathOrder []string `json:"-"`
+	// Webhooks are the events the API sends, keyed by event type, read from
+	// the 3.1 webhooks object or the x-webhooks extension used with 3.0
+	Webhooks map[string]*PathItem `json:"-"`
+	// WebhookOrder lists the webhook event types in document order
+	WebhookOrder []string `json:"-"`
+}
+
+// UnmarshalJSON decodes the document, recording the order of its paths
+func (d *Document) UnmarshalJSON(data []byte) error {
+	type plain Document
+	if err := json.Unmarshal(data, (*plain)(d)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if paths, ok := raw["paths"]; ok {
+		order, err := objectKeys(paths)
+		if err != nil {
+			return err
+		}
+		d.PathOrder = order
+	}
+	for _, key := range []string{"webhooks", "x-webhooks"} {
+		hooks, ok := raw[key]
+		if !ok {
+			continue
+		}
+		var items map[string]*PathItem
+		if err := json.Unmarshal(hooks, &items); err != nil {
+			return fmt.Errorf("openapi: %s: %w", key, err)
+		}
+		order, err := objectKeys(hooks)
+		if err != nil {
+			return err
+		}
+		if d.Webhooks == nil {
+			d.Webhooks = make(map[string]*PathItem)
+		}
+		for _, name := range order {
+			if _, dup := d.Webhooks[name]; !dup {
+				d.WebhookOrder = append(d.WebhookOrder, name)
+			}
+			d.Webhooks[name] = items[name]
+		}
+	}
+	return nil
+}
+
+// Tag groups operations
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// TagDescription returns the description of the named tag
+func (d *Document) TagDescription(name string) string {
+	for _, t := range d.Tags {
+		if t.Name == name {
+			return t.Description
+		}
+	}
+	return ""
+}
+
+// Info is the document's metadata
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is an API base URL
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components holds the document's reusable objects
+type Components struct {
+	Schemas       map[string]*Schema      `json:"schemas,omitempty"`
+	Parameters    map[string]*Parameter   `json:"parameters,omitempty"`
+	RequestBodies map[string]*RequestBody `json:"requestBodies,omitempty"`
+	Responses     map[string]*Response    `json:"responses,omitempty"`
+}
+
+// PathItem holds the operations on one path
+type PathItem struct {
+	Parameters []*Parameter `json:"parameters,omitempty"`
+	Get        *Operation   `json:"get,omitempty"`
+	Put        *Operation   `json:"put,omitempty"`
+	Post       *Operation   `json:"post,omitempty"`
+	Delete     *Operation   `json:"delete,omitempty"`
+	Patch      *Operation   `json:"patch,omitempty"`
+	Head       *Operation   `json:"head,omitempty"`
+	Options    *Operation   `json:"options,omitempty"`
+}
+
+// Methods lists HTTP methods in the order operations are generated
+var Methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// Operations returns the path's operations keyed by upper-case HTTP method
+func (p *PathItem) Operations() map[string]*Operation {
+	ops := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		"GET": p.Get, "PUT": p.Put, "POST": p.Post, "DELETE": p.Delete,
+		"PATCH": p.Patch, "HEAD": p.Head, "OPTIONS": p.Options,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// Operation is a single API operation
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	// ConnectProcedure is the Connect procedure serving the operation, e.g.
+	// "/acme.v1.CustomersService/GetCustomer", from the x-connect-procedure
+	// extension
+	ConnectProcedure string `json:"x-connect-procedure,omitempty"`
+}
+
+// Parameter is a path, query, header or cookie parameter
+type Parameter struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	In          string  `json:"in,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Deprecated  bool    `json:"deprecated,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody describes an operation's request body
+type RequestBody struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Response describes one response of an operation
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+	// ErrorCodes lists the error codes the response can carry, read from
+	// the x-error-codes extension
+	ErrorCodes []ErrorCode `json:"x-error-codes,omitempty"`
+}
+
+// ErrorCode is a documented API error code
+type ErrorCode struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+}
+
+// MediaType is the schema of a body in one content type
+type MediaType struct {
+	Schema  *Schema     `json:"schema,omitempty"`
+	Example interface{} `json:"example,omitempty"`
+}
+
+// Load reads a JSON or YAML OpenAPI document from path
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes a JSON or YAML OpenAPI document
+func Parse(data []byte) (*Document, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] != '{' {
+		converted, err := yamlToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+		}
+		data = converted
+	}
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", doc.OpenAPI)
+	}
+	return &doc, nil
+}
+
+// BasePath returns the path of the first server URL, e.g. "/v1", without a
+// trailing slash
+func (d *Document) BasePath() string {
+	if len(d.Servers) == 0 {
+		return ""
+	}
+	u, err := url.Parse(d.Servers[0].URL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// OrderedPaths returns the document's path templates in document order
+func (d *Document) OrderedPaths() []string {
+	if len(d.PathOrder) == len(d.Paths) {
+		return d.PathOrder
+	}
+	return d.SortedPaths()
+}
+
+// SortedPaths returns the document's path templates in order
+func (d *Document) SortedPaths() []string {
+	paths := make([]string, 0, len(d.Paths))
+	for p := range d.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// refName returns the component name of a local reference of the given
+// kind, e.g. "Customer" for "#/components/schemas/Customer"
+func refName(ref, kind string) (string, error) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", fmt.Errorf("unsupported reference %q", ref)
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
+
+// RefName returns the component name a schema reference points to
+func RefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// Schema resolves a schema reference chain
+func (d *Document) Schema(s *Schema) (*Schema, error) {
+	for i := 0; s != nil && s.Ref != ""; i++ {
+		if i > 32 {
+			return nil, fmt.Errorf("reference cycle at %q", s.Ref)
+		}
+		name, err := refName(s.Ref, "schemas")
+		if err != nil {
+			return nil, err
+		}
+		next, ok := d.Components.Schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown schema %q", s.Ref)
+		}
+		s = next
+	}
+	return s, nil
+}
+
+// Parameter resolves a parameter reference
+func (d *Document) Parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	name, err := refName(p.Ref, "parameters")
+	if err != nil {
+		return nil, err
+	}
+	if resolved, ok := d.Components.Parameters[name]; ok {
+		return resolved, nil
+	}
+	return nil, fmt.Errorf("unknown parameter %q", p.Ref)
+}
+
+// RequestBody resolves a request body reference
+func (d *Document) RequestBody(b *RequestBody) (*RequestBody, error) {
+	if b == nil || b.Ref == "" {
+		return b, nil
+	}
+	name, err := refName(b.Ref, "requestBodies")
+	if err != nil {
+		return nil, err
+	}
+	if resolved, ok := d.Components.RequestBodies[name]; ok {
+		return resolved, nil
+	}
+	return nil, fmt.Errorf("unknown request body %q", b.Ref)
+}
+
+// Response resolves a response reference
+func (d *Document) Response(r *Response) (*Response, error) {
+	if r == nil || r.Ref == "" {
+		return r, nil
+	}
+	name, err := refName(r.Ref, "responses")
+	if err != nil {
+		return nil, err
+	}
+	if resolved, ok := d.Components.Responses[name]; ok {
+		return resolved, nil
+	}
+	return nil, fmt.Errorf("unknown response %q", r.Ref)
+}
+
+// OperationParameters returns the resolved parameters of op, including
+// those declared on its path item
+func (d *Document) OperationParameters(item *PathItem, op *Operation) ([]*Parameter, error) {
+	var params []*Parameter
+	seen := make(map[string]bool)
+	for _, list := range [][]*Parameter{op.Parameters, item.Parameters} {
+		for _, p := range list {
+			resolved, err := d.Parameter(p)
+			if err != nil {
+				return nil, err
+			}
+			key := resolved.In + ":" + resolved.Name
+			if !seen[key] {
+				seen[key] = true
+				params = append(params, resolved)
+			}
+		}
+	}
+	return params, nil
+}
+
+// Match finds the path template matching a request path, with the values
+// of its path parameters. Literal segments take precedence over parameters.
+func (d *Document) Match(path string) (template string, item *PathItem, params map[string]string, ok bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	bestLiterals := -1
+	for _, tmpl := range d.SortedPaths() {
+		parts := strings.Split(strings.Trim(tmpl, "/"), "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+		values := make(map[string]string)
+		literals := 0
+		matched := true
+		for i, part := range parts {
+			if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+				v, err := url.PathUnescape(segments[i])
+				if err != nil {
+					v = segments[i]
+				}
+				values[part[1:len(part)-1]] = v
+				continue
+			}
+			if part != segments[i] {
+				matched = false
+				break
+			}
+			literals++
+		}
+		if matched && literals > bestLiterals {
+			template, item, params, ok = tmpl, d.Paths[tmpl], values, true
+			bestLiterals = literals
+		}
+	}
+	return template, item, params, ok
+}
+
+// ResponseFor returns the response declared for status, falling back to
+// the "2XX"-style range and "default"
+func (d *Document) ResponseFor(op *Operation, status int) (*Response, error) {
+	for _, key := range []string{fmt.Sprint(status), fmt.Sprintf("%dXX", status/100), "default"} {
+		if r, ok := op.Responses[key]; ok {
+			return d.Response(r)
+		}
+	}
+	return nil, nil
+}
+
+// JSONSchema returns the schema of the JSON content in content, if any
+func JSONSchema(content map[string]*MediaType) (*Schema, bool) {
+	for _, ct := range []string{"application/json", "application/problem+json"} {
+		if mt, ok := content[ct]; ok && mt.Schema != nil {
+			return mt.Schema, true
+		}
+	}
+	for ct, mt := range content {
+		if strings.HasSuffix(ct, "+json") && mt.Schema != nil {
+			return mt.Schema, true
+		}
+	}
+	return nil, false
+}
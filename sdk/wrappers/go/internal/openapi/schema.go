@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Schema is an OpenAPI 3.0 schema object
+type Schema struct {
+	Ref         string `json:"$ref,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Nullable    bool   `json:"nullable,omitempty"`
+	ReadOnly    bool   `json:"readOnly,omitempty"`
+	WriteOnly   bool   `json:"writeOnly,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+
+	Required   []string           `json:"required,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	// PropertyOrder lists the property names in document order
+	PropertyOrder []string `json:"-"`
+	// AdditionalProperties is nil when unspecified; see
+	// AllowsAdditionalProperties
+	AdditionalProperties *Schema `json:"-"`
+	// NoAdditionalProperties is set by "additionalProperties": false
+	NoAdditionalProperties bool    `json:"-"`
+	Items                  *Schema `json:"items,omitempty"`
+
+	Enum    []interface{} `json:"enum,omitempty"`
+	Default interface{}   `json:"default,omitempty"`
+	Example interface{}   `json:"example,omitempty"`
+
+	AllOf         []*Schema      `json:"allOf,omitempty"`
+	OneOf         []*Schema      `json:"oneOf,omitempty"`
+	AnyOf         []*Schema      `json:"anyOf,omitempty"`
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+	MinItems         *int     `json:"minItems,omitempty"`
+	MaxItems         *int     `json:"maxItems,omitempty"`
+	UniqueItems      bool     `json:"uniqueItems,omitempty"`
+
+	// Extensions holds the schema's x- members
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// Discriminator selects a oneOf/anyOf member by a property value
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// UnmarshalJSON decodes the schema, including additionalProperties given as
+// a boolean or a schema, and x- extensions
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if props, ok := raw["properties"]; ok {
+		order, err := objectKeys(props)
+		if err != nil {
+			return err
+		}
+		s.PropertyOrder = order
+	}
+	if ap, ok := raw["additionalProperties"]; ok {
+		var allowed bool
+		if err := json.Unmarshal(ap, &allowed); err == nil {
+			s.NoAdditionalProperties = !allowed
+		} else {
+			s.AdditionalProperties = new(Schema)
+			if err := json.Unmarshal(ap, s.AdditionalProperties); err != nil {
+				return err
+			}
+		}
+	}
+	for key, value := range raw {
+		if strings.HasPrefix(key, "x-") {
+			if s.Extensions == nil {
+				s.Extensions = make(map[string]interface{})
+			}
+			var v interface{}
+			if err := json.Unmarshal(value, &v); err != nil {
+				return err
+			}
+			s.Extensions[key] = v
+		}
+	}
+	return nil
+}
+
+// objectKeys returns the member names of a JSON object in document order
+func objectKeys(data json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, tok.(string))
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// IsRequired reports whether the object schema requires property
+func (s *Schema) IsRequired(property string) bool {
+	for _, r := range s.Required {
+		if r == property {
+			return true
+		}
+	}
+	return false
+}
+
+// Extension returns the string value of an x- extension
+func (s *Schema) Extension(name string) string {
+	v, _ := s.Extensions[name].(string)
+	return v
+}
@@ -0,0 +1,334 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Violation is one way a value fails to match a schema
+type Violation struct {
+	// Path locates the value, e.g. "body.items[0].email"
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var (
+	patternsMu sync.Mutex
+	patterns   = map[string]*regexp.Regexp{}
+)
+
+func compilePattern(p string) (*regexp.Regexp, error) {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+	if re, ok := patterns[p]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return nil, err
+	}
+	patterns[p] = re
+	return re, nil
+}
+
+// DecodeJSON decodes data for validation, keeping numbers exact
+func DecodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Validate checks a decoded JSON value against schema. Values decoded with
+// json.Number and float64 are both accepted for numbers.
+func (d *Document) Validate(schema *Schema, value interface{}, path string) []Violation {
+	var out []Violation
+	d.validate(schema, value, path, &out)
+	return out
+}
+
+func (d *Document) validate(schema *Schema, value interface{}, path string, out *[]Violation) {
+	report := func(format string, args ...interface{}) {
+		*out = append(*out, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	s, err := d.Schema(schema)
+	if err != nil {
+		report("%v", err)
+		return
+	}
+	if s == nil {
+		return
+	}
+
+	if value == nil {
+		if !s.Nullable && s.Type != "" {
+			report("null is not allowed")
+		}
+		return
+	}
+
+	for _, sub := range s.AllOf {
+		d.validate(sub, value, path, out)
+	}
+	if len(s.OneOf) > 0 {
+		if n := d.countMatches(s.OneOf, value, path); n != 1 {
+			report("matches %d oneOf schemas, want exactly 1", n)
+		}
+	}
+	if len(s.AnyOf) > 0 {
+		if d.countMatches(s.AnyOf, value, path) == 0 {
+			report("matches none of the anyOf schemas")
+		}
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		report("%v is not one of %v", value, s.Enum)
+	}
+
+	switch s.Type {
+	case "":
+		if len(s.Properties) > 0 {
+			if obj, ok := value.(map[string]interface{}); ok {
+				d.validateObject(s, obj, path, out)
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			report("expected object, got %s", jsonType(value))
+			return
+		}
+		d.validateObject(s, obj, path, out)
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			report("expected array, got %s", jsonType(value))
+			return
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			report("has %d items, minimum is %d", len(arr), *s.MinItems)
+		}
+		if s.MaxItems != nil && len(arr) > *s.MaxItems {
+			report("has %d items, maximum is %d", len(arr), *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				d.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i), out)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			report("expected string, got %s", jsonType(value))
+			return
+		}
+		validateString(s, str, report)
+	case "integer", "number":
+		n, ok := toFloat(value)
+		if !ok {
+			report("expected %s, got %s", s.Type, jsonType(value))
+			return
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			report("expected integer, got %v", value)
+			return
+		}
+		validateNumber(s, n, report)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report("expected boolean, got %s", jsonType(value))
+		}
+	}
+}
+
+func (d *Document) countMatches(schemas []*Schema, value interface{}, path string) int {
+	n := 0
+	for _, sub := range schemas {
+		if len(d.Validate(sub, value, path)) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (d *Document) validateObject(s *Schema, obj map[string]interface{}, path string, out *[]Violation) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			*out = append(*out, Violation{Path: path, Message: fmt.Sprintf("missing required property %q", name)})
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		child := joinPath(path, k)
+		if prop, ok := s.Properties[k]; ok {
+			d.validate(prop, obj[k], child, out)
+			continue
+		}
+		switch {
+		case s.AdditionalProperties != nil:
+			d.validate(s.AdditionalProperties, obj[k], child, out)
+		case s.NoAdditionalProperties:
+			*out = append(*out, Violation{Path: child, Message: "unknown property"})
+		}
+	}
+}
+
+func validateString(s *Schema, str string, report func(string, ...interface{})) {
+	length := len([]rune(str))
+	if s.MinLength != nil && length < *s.MinLength {
+		report("length %d is below minimum %d", length, *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		report("length %d exceeds maximum %d", length, *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		if re, err := compilePattern(s.Pattern); err != nil {
+			report("invalid pattern %q: %v", s.Pattern, err)
+		} else if !re.MatchString(str) {
+			report("%q does not match pattern %q", str, s.Pattern)
+		}
+	}
+	if err := checkFormat(s.Format, str); err != nil {
+		report("%q is not a valid %s", str, s.Format)
+	}
+}
+
+// checkFormat validates the string formats the SDK relies on; unknown
+// formats are accepted
+func checkFormat(format, str string) error {
+	switch format {
+	case "uuid":
+		if !uuidPattern.MatchString(str) {
+			return fmt.Errorf("invalid uuid")
+		}
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, str)
+		return err
+	case "date":
+		_, err := time.Parse("2006-01-02", str)
+		return err
+	case "email":
+		addr, err := mail.ParseAddress(str)
+		if err != nil || addr.Address != str {
+			return fmt.Errorf("invalid email")
+		}
+	}
+	return nil
+}
+
+func validateNumber(s *Schema, n float64, report func(string, ...interface{})) {
+	if s.Minimum != nil {
+		if n < *s.Minimum || (s.ExclusiveMinimum && n == *s.Minimum) {
+			report("%v is below minimum %v", n, *s.Minimum)
+		}
+	}
+	if s.Maximum != nil {
+		if n > *s.Maximum || (s.ExclusiveMaximum && n == *s.Maximum) {
+			report("%v exceeds maximum %v", n, *s.Maximum)
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf != 0 {
+		if q := n / *s.MultipleOf; q != math.Trunc(q) {
+			report("%v is not a multiple of %v", n, *s.MultipleOf)
+		}
+	}
+}
+
+// ValidateParam checks a raw path, query or header value against the
+// parameter's schema
+func (d *Document) ValidateParam(p *Parameter, raw string) []Violation {
+	path := p.In + "." + p.Name
+	s, err := d.Schema(p.Schema)
+	if err != nil {
+		return []Violation{{Path: path, Message: err.Error()}}
+	}
+	if s == nil {
+		return nil
+	}
+	var value interface{} = raw
+	switch s.Type {
+	case "integer", "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return []Violation{{Path: path, Message: fmt.Sprintf("%q is not a %s", raw, s.Type)}}
+		}
+		value = n
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []Violation{{Path: path, Message: fmt.Sprintf("%q is not a boolean", raw)}}
+		}
+		value = b
+	}
+	return d.Validate(s, value, path)
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number, float64, int, int64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
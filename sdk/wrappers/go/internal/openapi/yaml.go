@@ -0,0 +1,630 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlToJSON converts a YAML document to JSON, keeping mapping keys in
+// document order. It handles the block styles and single-line flow
+// collections OpenAPI documents are written in; anchors, aliases, tags,
+// flow collections spanning lines and multi-document streams are rejected.
+func yamlToJSON(data []byte) ([]byte, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	p := &yamlParser{lines: strings.Split(text, "\n")}
+	p.skipDirectives()
+	v, err := p.block(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.next(); p.pos < len(p.lines) {
+		if isDocumentMarker(p.text()) {
+			return nil, p.errorf("multi-document streams are not supported")
+		}
+		return nil, p.errorf("unexpected content")
+	}
+	var buf bytes.Buffer
+	if err := encodeYAMLValue(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlMap is a mapping with its keys in document order
+type yamlMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *yamlMap) set(key string, v interface{}) error {
+	if _, dup := m.values[key]; dup {
+		return fmt.Errorf("duplicate key %q", key)
+	}
+	m.keys = append(m.keys, key)
+	m.values[key] = v
+	return nil
+}
+
+type yamlParser struct {
+	lines []string
+	pos   int
+}
+
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+// errorAt reports an error on the line with index line
+func (p *yamlParser) errorAt(line int, format string, args ...interface{}) error {
+	return fmt.Errorf("yaml: line %d: %s", line+1, fmt.Sprintf(format, args...))
+}
+
+// skipDirectives skips %-directives and a leading --- marker
+func (p *yamlParser) skipDirectives() {
+	for p.next(); p.pos < len(p.lines); p.next() {
+		line := strings.TrimSpace(p.lines[p.pos])
+		if !strings.HasPrefix(line, "%") && line != "---" {
+			return
+		}
+		p.pos++
+	}
+}
+
+// next advances past blank and comment-only lines
+func (p *yamlParser) next() {
+	for p.pos < len(p.lines) {
+		line := strings.TrimSpace(p.lines[p.pos])
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return
+		}
+		p.pos++
+	}
+}
+
+// indent returns the indentation of the current line
+func (p *yamlParser) indent() int {
+	line := p.lines[p.pos]
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// tabIndented reports whether the current line's indentation holds a tab
+func (p *yamlParser) tabIndented() bool {
+	line := p.lines[p.pos]
+	return strings.ContainsRune(line[:len(line)-len(strings.TrimLeft(line, " \t"))], '\t')
+}
+
+func (p *yamlParser) text() string {
+	return strings.TrimSpace(p.lines[p.pos])
+}
+
+func isDocumentMarker(text string) bool {
+	return text == "---" || text == "..." || strings.HasPrefix(text, "--- ")
+}
+
+func isSeqEntry(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// block parses the node starting at the current line, indented by at least
+// min
+func (p *yamlParser) block(min int) (interface{}, error) {
+	p.next()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	n := p.indent()
+	if n < min {
+		return nil, nil
+	}
+	if p.tabIndented() {
+		return nil, p.errorf("tabs are not allowed in indentation")
+	}
+	text := p.text()
+	if isSeqEntry(text) {
+		return p.sequence(n)
+	}
+	if _, _, ok := splitMappingEntry(text); ok {
+		return p.mapping(n)
+	}
+	// A lone scalar, possibly a flow collection
+	p.pos++
+	return p.value(n, text, false)
+}
+
+func (p *yamlParser) mapping(n int) (interface{}, error) {
+	m := &yamlMap{values: make(map[string]interface{})}
+	for p.next(); p.pos < len(p.lines); p.next() {
+		if p.tabIndented() {
+			return nil, p.errorf("tabs are not allowed in indentation")
+		}
+		indent := p.indent()
+		if indent < n {
+			break
+		}
+		if indent > n {
+			return nil, p.errorf("bad indentation")
+		}
+		text := p.text()
+		if isSeqEntry(text) {
+			break
+		}
+		key, rest, ok := splitMappingEntry(text)
+		if !ok {
+			if isDocumentMarker(text) {
+				return nil, p.errorf("multi-document streams are not supported")
+			}
+			if text[0] == '&' || text[0] == '*' {
+				return nil, p.errorf("anchors and aliases are not supported")
+			}
+			return nil, p.errorf("expected a mapping entry")
+		}
+		if key == "<<" {
+			return nil, p.errorf("merge keys are not supported")
+		}
+		line := p.pos
+		p.pos++
+		v, err := p.value(n, rest, true)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.set(key, v); err != nil {
+			return nil, p.errorAt(line, "%v", err)
+		}
+	}
+	return m, nil
+}
+
+func (p *yamlParser) sequence(n int) (interface{}, error) {
+	list := []interface{}{}
+	for p.next(); p.pos < len(p.lines); p.next() {
+		if p.tabIndented() {
+			return nil, p.errorf("tabs are not allowed in indentation")
+		}
+		if p.indent() != n || !isSeqEntry(p.text()) {
+			if p.indent() > n {
+				return nil, p.errorf("bad indentation")
+			}
+			break
+		}
+		item := strings.TrimPrefix(p.text(), "-")
+		content := strings.TrimLeft(item, " ")
+		if content == "" || strings.HasPrefix(content, "#") {
+			p.pos++
+			v, err := p.block(n + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			continue
+		}
+		_, _, isEntry := splitMappingEntry(content)
+		if isEntry || isSeqEntry(content) {
+			// A compact nested node: reparse the entry's content as if it
+			// started its own line at the same column
+			col := n + 1 + len(item) - len(content)
+			p.lines[p.pos] = strings.Repeat(" ", col) + content
+			v, err := p.block(col)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			continue
+		}
+		p.pos++
+		v, err := p.value(n, content, false)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+// value parses the value following a mapping key or sequence dash on a line
+// indented by n. rest is the remainder of that line, which is the one before
+// the current one.
+func (p *yamlParser) value(n int, rest string, inMapping bool) (interface{}, error) {
+	line := p.pos - 1
+	rest = stripYAMLComment(rest)
+	switch {
+	case rest == "":
+		p.next()
+		if p.pos >= len(p.lines) {
+			return nil, nil
+		}
+		// Sequences may sit at the same indentation as their key
+		if inMapping && p.indent() == n && isSeqEntry(p.text()) {
+			return p.sequence(n)
+		}
+		return p.block(n + 1)
+	case strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+		return p.blockScalar(n, rest)
+	case strings.HasPrefix(rest, "!"):
+		return nil, p.errorAt(line, "tags are not supported")
+	case strings.HasPrefix(rest, "&") || strings.HasPrefix(rest, "*"):
+		return nil, p.errorAt(line, "anchors and aliases are not supported")
+	}
+	v, err := parseYAMLScalar(rest)
+	if err != nil {
+		return nil, p.errorAt(line, "%v", err)
+	}
+	if s, ok := v.(string); ok && isPlainScalar(rest) {
+		// Plain scalars may continue on more indented lines
+		for p.next(); p.pos < len(p.lines) && p.indent() > n; p.next() {
+			s += " " + stripYAMLComment(p.text())
+			p.pos++
+		}
+		return s, nil
+	}
+	return v, nil
+}
+
+// blockScalar parses a literal (|) or folded (>) scalar whose header is on
+// the line before the current one
+func (p *yamlParser) blockScalar(n int, header string) (interface{}, error) {
+	folded := header[0] == '>'
+	chomp := byte(0)
+	explicit := 0
+	for _, c := range header[1:] {
+		switch {
+		case c == '-' || c == '+':
+			chomp = byte(c)
+		case c >= '1' && c <= '9':
+			explicit = int(c - '0')
+		default:
+			return nil, p.errorAt(p.pos-1, "bad block scalar header %q", header)
+		}
+	}
+
+	contentIndent := -1
+	if explicit > 0 {
+		contentIndent = n + explicit
+	}
+	var lines []string
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if contentIndent < 0 {
+			if indent <= n {
+				break
+			}
+			contentIndent = indent
+		}
+		if indent < contentIndent {
+			break
+		}
+		lines = append(lines, line[contentIndent:])
+	}
+
+	// Separate the trailing blank lines, which only chomping decides about
+	end := len(lines)
+	for end > 0 && lines[end-1] == "" {
+		end--
+	}
+	trailing := len(lines) - end
+	lines = lines[:end]
+
+	s := strings.Join(lines, "\n")
+	if folded {
+		var b strings.Builder
+		for i, line := range lines {
+			if i > 0 {
+				prev := lines[i-1]
+				switch {
+				case line == "":
+					// Each blank line folds to a newline
+					b.WriteByte('\n')
+				case prev == "":
+				case strings.HasPrefix(line, " ") || strings.HasPrefix(prev, " "):
+					// More indented lines keep their line breaks
+					b.WriteByte('\n')
+				default:
+					b.WriteByte(' ')
+				}
+			}
+			b.WriteString(line)
+		}
+		s = b.String()
+	}
+	switch chomp {
+	case '-':
+	case '+':
+		if len(lines) > 0 {
+			s += "\n"
+		}
+		s += strings.Repeat("\n", trailing)
+	default:
+		if len(lines) > 0 {
+			s += "\n"
+		}
+	}
+	return s, nil
+}
+
+// splitMappingEntry splits "key: value" into its key and the rest of the
+// line
+func splitMappingEntry(text string) (key, rest string, ok bool) {
+	if text == "" {
+		return "", "", false
+	}
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", false
+		}
+		after := text[end+1:]
+		if after != ":" && !strings.HasPrefix(after, ": ") {
+			return "", "", false
+		}
+		k, err := parseYAMLScalar(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		return fmt.Sprint(k), strings.TrimSpace(after[1:]), true
+	}
+	if strings.ContainsRune("[{#&*!|>%@`", rune(text[0])) {
+		return "", "", false
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '#' && i > 0 && text[i-1] == ' ' {
+			return "", "", false
+		}
+		if text[i] == ':' && (i == len(text)-1 || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// closingQuote returns the index of the quote closing the string text
+// starts with, or -1
+func closingQuote(text string) int {
+	q := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case q == '"' && text[i] == '\\':
+			i++
+		case text[i] == q:
+			if q == '\'' && i+1 < len(text) && text[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// stripYAMLComment removes a trailing comment from a value
+func stripYAMLComment(s string) string {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"' || s[i] == '\'':
+			if i == 0 || s[i-1] == ' ' || s[i-1] == '[' || s[i-1] == '{' || s[i-1] == ',' {
+				if end := closingQuote(s[i:]); end >= 0 {
+					i += end
+				}
+			}
+		case s[i] == '#' && (i == 0 || s[i-1] == ' '):
+			return strings.TrimSpace(s[:i])
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+func isPlainScalar(s string) bool {
+	return s != "" && !strings.ContainsRune("\"'[{", rune(s[0]))
+}
+
+var (
+	yamlInt   = regexp.MustCompile(`^[-+]?(0|[1-9][0-9]*)$`)
+	yamlFloat = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// parseYAMLScalar parses a quoted, plain or flow scalar
+func parseYAMLScalar(s string) (interface{}, error) {
+	f := &yamlFlow{s: s}
+	v, err := f.value()
+	if err != nil {
+		return nil, err
+	}
+	if f.skipSpace(); f.pos < len(f.s) {
+		if isPlainScalar(s) {
+			return resolvePlain(s), nil
+		}
+		return nil, fmt.Errorf("unexpected %q after value", f.s[f.pos:])
+	}
+	return v, nil
+}
+
+// resolvePlain gives a plain scalar its JSON type
+func resolvePlain(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if yamlInt.MatchString(s) {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	}
+	if yamlFloat.MatchString(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// yamlFlow parses a single-line flow value: a scalar, [sequence] or
+// {mapping}
+type yamlFlow struct {
+	s   string
+	pos int
+}
+
+func (f *yamlFlow) skipSpace() {
+	for f.pos < len(f.s) && f.s[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *yamlFlow) value() (interface{}, error) {
+	f.skipSpace()
+	if f.pos >= len(f.s) {
+		return nil, nil
+	}
+	switch f.s[f.pos] {
+	case '[':
+		return f.sequence()
+	case '{':
+		return f.mapping()
+	case '"', '\'':
+		return f.quoted()
+	}
+	start := f.pos
+	for f.pos < len(f.s) && !strings.ContainsRune(",]}", rune(f.s[f.pos])) {
+		if f.s[f.pos] == ':' && f.pos+1 < len(f.s) && f.s[f.pos+1] == ' ' {
+			break
+		}
+		f.pos++
+	}
+	return resolvePlain(strings.TrimSpace(f.s[start:f.pos])), nil
+}
+
+func (f *yamlFlow) quoted() (interface{}, error) {
+	end := closingQuote(f.s[f.pos:])
+	if end < 0 {
+		return nil, fmt.Errorf("unterminated string %s", f.s[f.pos:])
+	}
+	raw := f.s[f.pos : f.pos+end+1]
+	f.pos += end + 1
+	if raw[0] == '\'' {
+		return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+	}
+	s, err := strconv.Unquote(raw)
+	if err != nil {
+		return nil, fmt.Errorf("bad string %s", raw)
+	}
+	return s, nil
+}
+
+func (f *yamlFlow) sequence() (interface{}, error) {
+	f.pos++
+	list := []interface{}{}
+	for {
+		f.skipSpace()
+		if f.pos >= len(f.s) {
+			return nil, fmt.Errorf("flow sequence not closed on its line; flow collections spanning lines are not supported")
+		}
+		if f.s[f.pos] == ']' {
+			f.pos++
+			return list, nil
+		}
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+		if err := f.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (f *yamlFlow) mapping() (interface{}, error) {
+	f.pos++
+	m := &yamlMap{values: make(map[string]interface{})}
+	for {
+		f.skipSpace()
+		if f.pos >= len(f.s) {
+			return nil, fmt.Errorf("flow mapping not closed on its line; flow collections spanning lines are not supported")
+		}
+		if f.s[f.pos] == '}' {
+			f.pos++
+			return m, nil
+		}
+		k, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		f.skipSpace()
+		if f.pos >= len(f.s) || f.s[f.pos] != ':' {
+			return nil, fmt.Errorf("expected ':' in flow mapping")
+		}
+		f.pos++
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		if err := m.set(fmt.Sprint(k), v); err != nil {
+			return nil, err
+		}
+		if err := f.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes the comma between flow entries, leaving the closing
+// bracket for the caller
+func (f *yamlFlow) separator(closing byte) error {
+	f.skipSpace()
+	if f.pos < len(f.s) && f.s[f.pos] == ',' {
+		f.pos++
+		return nil
+	}
+	if f.pos < len(f.s) && f.s[f.pos] == closing {
+		return nil
+	}
+	return fmt.Errorf("expected ',' or '%c'", closing)
+}
+
+func encodeYAMLValue(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case *yamlMap:
+		buf.WriteByte('{')
+		for i, k := range v.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(k)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := encodeYAMLValue(buf, v.values[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeYAMLValue(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
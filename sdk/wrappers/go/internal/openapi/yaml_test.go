@@ -0,0 +1,89 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestYAMLToJSONGolden converts the shipped spec and compares it with
+// testdata/openapi.json, an independent conversion of the same document
+func TestYAMLToJSONGolden(t *testing.T) {
+	data, err := os.ReadFile("../../../../openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	converted, err := yamlToJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, err := os.ReadFile("testdata/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want interface{}
+	if err := json.Unmarshal(converted, &got); err != nil {
+		t.Fatalf("converted document is not JSON: %v", err)
+	}
+	if err := json.Unmarshal(golden, &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("converted openapi.yaml differs from testdata/openapi.json")
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name, yaml, want string
+	}{
+		{"key order", "b: 1\na: 2\nc: 3\n", `{"b":1,"a":2,"c":3}`},
+		{"scalars", "a: ~\nb: true\nc: 1.5\nd: 007x\ne: '1'\n", `{"a":null,"b":true,"c":1.5,"d":"007x","e":"1"}`},
+		{"sequence at key indentation", "a:\n- 1\n- x\n", `{"a":[1,"x"]}`},
+		{"compact mapping in sequence", "- a: 1\n  b: 2\n", `[{"a":1,"b":2}]`},
+		{"flow collections", "a: [1, {b: c}]\nd: {}\n", `{"a":[1,{"b":"c"}],"d":{}}`},
+		{"literal block", "a: |\n  x\n  y\nb: 1\n", `{"a":"x\ny\n","b":1}`},
+		{"folded block", "a: >-\n  x\n  y\n", `{"a":"x y"}`},
+		{"comments", "# top\na: 1 # trailing\n", `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yamlToJSON([]byte(tt.yaml))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLToJSONUnsupported(t *testing.T) {
+	tests := []struct {
+		name, yaml, err string
+	}{
+		{"anchor", "a: &x 1\n", "yaml: line 1: anchors and aliases are not supported"},
+		{"alias", "a: 1\nb: *x\n", "yaml: line 2: anchors and aliases are not supported"},
+		{"anchored mapping", "a: &x\n  b: 1\n", "yaml: line 1: anchors and aliases are not supported"},
+		{"alias in sequence", "a:\n  - *x\n", "yaml: line 2: anchors and aliases are not supported"},
+		{"merge key", "a:\n  <<: 1\n", "yaml: line 2: merge keys are not supported"},
+		{"multi-line flow mapping", "a: {b: 1,\n  c: 2}\n", "yaml: line 1: flow mapping not closed on its line"},
+		{"multi-line flow sequence", "a: [1,\n  2]\n", "yaml: line 1: flow sequence not closed on its line"},
+		{"block scalar header", "a: |x\n  y\n", `yaml: line 1: bad block scalar header "|x"`},
+		{"tag", "a: !!str 1\n", "yaml: line 1: tags are not supported"},
+		{"multiple documents", "a: 1\n---\nb: 2\n", "yaml: line 2: multi-document streams are not supported"},
+		{"tab indentation", "a:\n\tb: 1\n", "yaml: line 2: tabs are not allowed in indentation"},
+		{"duplicate key", "a: 1\na: 2\n", `yaml: line 2: duplicate key "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := yamlToJSON([]byte(tt.yaml))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package yourapi
+
+import (
+	"context"
+	"net/http/httptrace"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DebugSnapshot is a point-in-time view of the client's configuration and
+// runtime state, safe to include in support bundles
+type DebugSnapshot struct {
+	Time       time.Time      `json:"time"`
+	SDKVersion string         `json:"sdkVersion"`
+	Config     ConfigSnapshot `json:"config"`
+	InFlight   int64          `json:"inFlight"`
+	// Queued is the number of requests waiting for a MaxConcurrentRequests
+	// slot
+	Queued      int64           `json:"queued"`
+	Connections ConnectionStats `json:"connections"`
+	RateLimit   RateLimitState  `json:"rateLimit"`
+	// Budget is RemainingBudget, and EndpointBudgets the budget of each
+	// EndpointRateLimits entry
+	Budget          Budget          `json:"budget"`
+	EndpointBudgets []LimitedBudget `json:"endpointBudgets,omitempty"`
+	Endpoints       []EndpointStats `json:"endpoints"`
+}
+
+// LimitedBudget is the Budget of the endpoints matched by one
+// EndpointRateLimits entry
+type LimitedBudget struct {
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path"`
+	Budget
+}
+
+// ConfigSnapshot is the client configuration with secrets masked
+type ConfigSnapshot struct {
+	BaseURL          string            `json:"baseUrl"`
+	Auth             string            `json:"auth"`
+	Credential       string            `json:"credential,omitempty"`
+	Timeout          time.Duration     `json:"timeout"`
+	MaxRetries       int               `json:"maxRetries"`
+	UserAgent        string            `json:"userAgent"`
+	Locale           string            `json:"locale,omitempty"`
+	CustomHeaders    map[string]string `json:"customHeaders,omitempty"`
+	MaxErrorBodySize int64             `json:"maxErrorBodySize"`
+	Tracing          bool              `json:"tracing"`
+	Metrics          bool              `json:"metrics"`
+}
+
+// ConnectionStats counts how requests obtained connections, showing whether
+// keep-alive pooling is effective
+type ConnectionStats struct {
+	// New is the number of freshly dialed connections
+	New int64 `json:"new"`
+	// Reused is the number of requests served by a pooled connection
+	Reused int64 `json:"reused"`
+	// WasIdle is the number of reused connections taken from the idle pool
+	WasIdle int64 `json:"wasIdle"`
+}
+
+// connCounters accumulates ConnectionStats
+type connCounters struct {
+	newConns atomic.Int64
+	reused   atomic.Int64
+	wasIdle  atomic.Int64
+}
+
+// withConnTracking records how the request obtains its connection
+func (cc *connCounters) withConnTracking(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if !info.Reused {
+				cc.newConns.Add(1)
+				return
+			}
+			cc.reused.Add(1)
+			if info.WasIdle {
+				cc.wasIdle.Add(1)
+			}
+		},
+	})
+}
+
+// DebugSnapshot returns the client's current configuration (with
+// credentials masked) and runtime state
+func (c *Client) DebugSnapshot() DebugSnapshot {
+	cfg := ConfigSnapshot{
+		BaseURL:          c.options.BaseURL,
+		Auth:             "none",
+		Timeout:          c.httpClient.Timeout,
+		MaxRetries:       c.maxRetries,
+		UserAgent:        c.userAgent,
+		Locale:           c.locale,
+		MaxErrorBodySize: c.maxErrorBodySize,
+		Tracing:          c.tracer != nil,
+		Metrics:          c.metrics != nil,
+	}
+	switch {
+	case c.bearerToken != "":
+		cfg.Auth = "bearer"
+		cfg.Credential = maskSecret(c.bearerToken)
+	case c.apiKey != "":
+		cfg.Auth = "api_key"
+		cfg.Credential = maskSecret(c.apiKey)
+	}
+	if len(c.customHeaders) > 0 {
+		cfg.CustomHeaders = make(map[string]string, len(c.customHeaders))
+		for k, v := range c.customHeaders {
+			if c.redactor.headers[canonicalHeader(k)] {
+				v = Redacted
+			}
+			cfg.CustomHeaders[k] = v
+		}
+	}
+
+	return DebugSnapshot{
+		Time:       c.clock.Now(),
+		SDKVersion: Version,
+		Config:     cfg,
+		InFlight:   c.inFlight.Load(),
+		Queued:     c.queued(),
+		Connections: ConnectionStats{
+			New:     c.conns.newConns.Load(),
+			Reused:  c.conns.reused.Load(),
+			WasIdle: c.conns.wasIdle.Load(),
+		},
+		RateLimit:       c.RateLimitState(),
+		Budget:          c.RemainingBudget(),
+		EndpointBudgets: c.endpointBudgets(),
+		Endpoints:       c.Stats(),
+	}
+}
+
+// endpointBudgets reports the budget of every EndpointRateLimits entry
+func (c *Client) endpointBudgets() []LimitedBudget {
+	var budgets []LimitedBudget
+	for _, e := range c.options.EndpointRateLimits {
+		method := strings.ToUpper(e.Method)
+		template := normalizeTemplate(strings.TrimSuffix(e.Path, "*"))
+		budgets = append(budgets, LimitedBudget{
+			Method: method,
+			Path:   e.Path,
+			Budget: c.budget(method, template),
+		})
+	}
+	return budgets
+}
+
+// queued returns the number of requests waiting on the concurrency gate
+func (c *Client) queued() int64 {
+	if c.gate == nil {
+		return 0
+	}
+	return c.gate.queued.Load()
+}
+
+// maskSecret keeps the last four characters of long secrets
+func maskSecret(s string) string {
+	if len(s) <= 8 {
+		return Redacted
+	}
+	return "****" + s[len(s)-4:]
+}
@@ -0,0 +1,150 @@
+package yourapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Page is one page of a paginated list, as returned to an Iterator
+type Page[T any] struct {
+	Items []T
+	// Next identifies the following page: a cursor, or a page number for
+	// page-numbered lists. Empty means there is none.
+	Next string
+	// HasMore reports whether the list continues after this page
+	HasMore bool
+}
+
+// PageFetcher fetches the page identified by next, "" being the first
+type PageFetcher[T any] func(ctx context.Context, next string) (Page[T], error)
+
+// Iterator walks the items of a paginated list, fetching pages as it goes.
+// The generated ...Iter service methods return one per list endpoint:
+//
+//	it := client.Customers.ListIter(ctx, nil)
+//	for it.Next() {
+//		customer := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx    context.Context
+	client *Client
+	ro     requestOptions
+	fetch  PageFetcher[T]
+
+	items   []T
+	index   int
+	item    T
+	next    string
+	started bool
+	done    bool
+	err     error
+	paused  time.Duration
+	// seen guards against servers that hand back a cursor already visited,
+	// which would otherwise loop forever
+	seen map[string]bool
+}
+
+// NewIterator returns an Iterator over the pages returned by fetch. Requests
+// made by fetch should use ctx; c and opts enable WithPauseOnRateLimit.
+func NewIterator[T any](ctx context.Context, c *Client, fetch PageFetcher[T], opts ...RequestOption) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:    ctx,
+		client: c,
+		ro:     newRequestOptions(opts),
+		fetch:  fetch,
+		seen:   map[string]bool{"": true},
+	}
+}
+
+// Next advances to the next item, fetching the next page when the current
+// one is exhausted. It returns false when the list ends or a request fails;
+// check Err to tell which.
+func (it *Iterator[T]) Next() bool {
+	for it.index >= len(it.items) {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.started && it.next == "" {
+			it.done = true
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		it.fetchPage()
+	}
+	it.item = it.items[it.index]
+	it.index++
+	return true
+}
+
+func (it *Iterator[T]) fetchPage() {
+	page, err := it.fetch(it.ctx, it.next)
+	if err != nil {
+		if it.client == nil {
+			it.err = err
+			return
+		}
+		pause, ok := it.client.rateLimitPause(it.ro, err, it.paused)
+		if !ok {
+			it.err = err
+			return
+		}
+		it.client.logger.Info("rate limited, pausing pagination", "pauseMs", pause.Milliseconds())
+		if err := it.client.sleep(it.ctx, pause); err != nil {
+			it.err = err
+			return
+		}
+		it.paused += pause
+		return
+	}
+
+	it.started = true
+	it.items, it.index = page.Items, 0
+	it.next = page.Next
+	if !page.HasMore {
+		it.next = ""
+	}
+	if it.next != "" {
+		if it.seen[it.next] {
+			it.err = fmt.Errorf("pagination cursor %q was already returned", it.next)
+			it.next = ""
+			return
+		}
+		it.seen[it.next] = true
+	}
+}
+
+// Item returns the current item
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All drains the iterator and returns the remaining items
+func (it *Iterator[T]) All() ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// deref returns the value p points to, or the zero value for nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
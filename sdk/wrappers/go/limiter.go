@@ -0,0 +1,316 @@
+package yourapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter decides when requests may be sent. Implementations backed by
+// shared storage let a fleet of workers stay within one API quota.
+type RateLimiter interface {
+	// Allow takes a permit for the request if one is available and returns
+	// 0. Otherwise it returns how long to wait before asking again, without
+	// taking a permit.
+	Allow(ctx context.Context, method, pathTemplate string) (wait time.Duration, err error)
+}
+
+// RateLimitMode selects how a RateLimit spends its quota
+type RateLimitMode int
+
+const (
+	// RateLimitBursty is a token bucket: up to Burst requests may go out
+	// back to back, then requests are paced at RPS
+	RateLimitBursty RateLimitMode = iota
+	// RateLimitSmooth paces requests strictly, one every 1/RPS seconds,
+	// ignoring Burst, for gateways that penalize bursts even within quota
+	RateLimitSmooth
+)
+
+// RateLimit configures the client-side token bucket applied before every
+// request attempt, including retries
+type RateLimit struct {
+	// RPS is the sustained number of requests per second; 0 disables limiting
+	RPS float64
+	// Burst is the number of requests that may be sent back to back before
+	// pacing applies (default: 1)
+	Burst int
+	// Mode selects bursty or smooth pacing (default: RateLimitBursty)
+	Mode RateLimitMode
+}
+
+// burst returns the effective bucket size
+func (r RateLimit) burst() int {
+	if r.Mode == RateLimitSmooth || r.Burst < 1 {
+		return 1
+	}
+	return r.Burst
+}
+
+// EndpointRateLimit applies its own RateLimit to requests matching Method and
+// Path. All endpoints matched by one entry share a single bucket, so a
+// wildcard entry limits an operation group as a whole.
+type EndpointRateLimit struct {
+	// Method restricts the entry to one HTTP method (optional)
+	Method string
+	// Path is a path template such as "/customers/{id}/orders"; any {param}
+	// segment matches an identifier, and a trailing "*" matches any suffix
+	// (e.g. "/search*")
+	Path string
+	RateLimit
+}
+
+// limitRules maps requests to the RateLimit that applies to them and a key
+// identifying the bucket they share
+type limitRules struct {
+	endpoints []endpointRule
+	global    RateLimit
+}
+
+// endpointRule is a compiled EndpointRateLimit
+type endpointRule struct {
+	key    string
+	method string
+	path   string
+	prefix bool
+	limit  RateLimit
+}
+
+func newLimitRules(global RateLimit, endpoints []EndpointRateLimit) limitRules {
+	rules := limitRules{global: global}
+	for _, e := range endpoints {
+		path := e.Path
+		prefix := strings.HasSuffix(path, "*")
+		if prefix {
+			path = strings.TrimSuffix(path, "*")
+		}
+		rule := endpointRule{
+			method: strings.ToUpper(e.Method),
+			path:   normalizeTemplate(path),
+			prefix: prefix,
+			limit:  e.RateLimit,
+		}
+		rule.key = strings.TrimSpace(rule.method + " " + e.Path)
+		rules.endpoints = append(rules.endpoints, rule)
+	}
+	return rules
+}
+
+// empty reports whether no request is ever limited
+func (r limitRules) empty() bool {
+	return r.global.RPS <= 0 && len(r.endpoints) == 0
+}
+
+// match returns the limit of the first endpoint entry matching the request,
+// falling back to the client-wide limit under the key "*"
+func (r limitRules) match(method, template string) (key string, limit RateLimit) {
+	for _, e := range r.endpoints {
+		if e.method != "" && e.method != method {
+			continue
+		}
+		if template == e.path || (e.prefix && strings.HasPrefix(template, e.path)) {
+			return e.key, e.limit
+		}
+	}
+	return "*", r.global
+}
+
+// normalizeTemplate rewrites every {param} segment to {id} so user-written
+// templates compare equal to pathTemplate output
+func normalizeTemplate(path string) string {
+	segments := strings.Split(pathTemplate(path), "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// MemoryRateLimiter is a RateLimiter holding token buckets in process. It is
+// what the client uses for ClientOptions.RateLimit and EndpointRateLimits.
+type MemoryRateLimiter struct {
+	rules limitRules
+	clock Clock
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// NewMemoryRateLimiter creates an in-process limiter applying limit to all
+// requests, except those matching one of endpoints
+func NewMemoryRateLimiter(limit RateLimit, endpoints ...EndpointRateLimit) *MemoryRateLimiter {
+	return newMemoryRateLimiter(newLimitRules(limit, endpoints), realClock{})
+}
+
+func newMemoryRateLimiter(rules limitRules, clock Clock) *MemoryRateLimiter {
+	return &MemoryRateLimiter{rules: rules, clock: clock, buckets: make(map[string]*tokenBucket)}
+}
+
+// Allow implements RateLimiter
+func (l *MemoryRateLimiter) Allow(ctx context.Context, method, pathTemplate string) (time.Duration, error) {
+	key, limit := l.rules.match(method, pathTemplate)
+	if limit.RPS <= 0 {
+		return 0, nil
+	}
+	now := l.clock.Now()
+
+	l.mu.Lock()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(limit, now)
+		l.buckets[key] = bucket
+	}
+	l.mu.Unlock()
+
+	return bucket.take(now), nil
+}
+
+// Available implements BudgetReporter
+func (l *MemoryRateLimiter) Available(method, pathTemplate string) int {
+	key, limit := l.rules.match(method, pathTemplate)
+	if limit.RPS <= 0 {
+		return -1
+	}
+
+	l.mu.Lock()
+	bucket, ok := l.buckets[key]
+	l.mu.Unlock()
+	if !ok {
+		return int(newTokenBucket(limit, l.clock.Now()).burst)
+	}
+	return bucket.available(l.clock.Now())
+}
+
+// tokenBucket is a token bucket limiter driven by the client's Clock
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket for cfg
+func newTokenBucket(cfg RateLimit, now time.Time) *tokenBucket {
+	burst := float64(cfg.burst())
+	return &tokenBucket{rate: cfg.RPS, burst: burst, tokens: burst, last: now}
+}
+
+// take removes a token and returns 0 if one is available, or otherwise how
+// long until the next token is due
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// available returns the number of whole tokens in the bucket at now
+func (b *tokenBucket) available(now time.Time) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tokens := b.tokens
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		tokens += elapsed.Seconds() * b.rate
+		if tokens > b.burst {
+			tokens = b.burst
+		}
+	}
+	return int(tokens)
+}
+
+// ThrottledLocallyError is returned instead of waiting when a request made
+// with WithFailFast would be delayed by the client's own limits
+type ThrottledLocallyError struct {
+	// Limiter names the limit that was exhausted: "rate_limit",
+	// "adaptive" or "concurrency"
+	Limiter string
+	// Wait is how long the request would have waited, 0 if unknown
+	Wait time.Duration
+}
+
+func (e *ThrottledLocallyError) Error() string {
+	if e.Wait > 0 {
+		return fmt.Sprintf("throttled locally by %s limiter (would wait %s)", e.Limiter, e.Wait)
+	}
+	return fmt.Sprintf("throttled locally by %s limiter", e.Limiter)
+}
+
+// throttle blocks until the client-side limits admit another attempt of the
+// call or ctx is done. Calls made with WithFailFast get a
+// *ThrottledLocallyError instead of waiting.
+func (c *Client) throttle(ctx context.Context, ci *callInfo) error {
+	if c.rateLimiter != nil {
+		if err := c.waitForPermit(ctx, ci); err != nil {
+			return err
+		}
+	}
+	if c.pacer != nil {
+		wait, ok := c.pacer.reserve(c.rateLimit.get(), c.clock.Now(), !ci.failFast)
+		if !ok {
+			return &ThrottledLocallyError{Limiter: "adaptive", Wait: wait}
+		}
+		if wait > 0 {
+			return c.sleep(ctx, wait)
+		}
+	}
+	return nil
+}
+
+// waitForPermit polls the rate limiter until it grants the call a permit.
+// While higher-priority calls are waiting for permits, the call leaves the
+// next permit to them.
+func (c *Client) waitForPermit(ctx context.Context, ci *callInfo) error {
+	waiting := &c.rateWaiters[ci.priority.level()]
+	registered := false
+	defer func() {
+		if registered {
+			waiting.Add(-1)
+		}
+	}()
+
+	var wait time.Duration
+	for {
+		if !c.rateWaiters.ahead(ci.priority) {
+			var err error
+			wait, err = c.rateLimiter.Allow(ctx, ci.method, ci.template)
+			if err != nil {
+				return fmt.Errorf("rate limiter: %w", err)
+			}
+			if wait <= 0 {
+				return nil
+			}
+		} else if wait <= 0 {
+			wait = minPermitPoll
+		}
+		if ci.failFast {
+			return &ThrottledLocallyError{Limiter: "rate_limit", Wait: wait}
+		}
+		if !registered {
+			waiting.Add(1)
+			registered = true
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// minPermitPoll is how long a call yielding to higher-priority calls waits
+// before checking the rate limiter again
+const minPermitPoll = 10 * time.Millisecond
@@ -0,0 +1,141 @@
+package yourapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// templateRecorder is a RateLimiter recording the templates it is asked
+// about
+type templateRecorder struct {
+	mu        sync.Mutex
+	templates []string
+}
+
+func (r *templateRecorder) Allow(_ context.Context, _, pathTemplate string) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates = append(r.templates, pathTemplate)
+	return 0, nil
+}
+
+func TestLimitRulesMatch(t *testing.T) {
+	rules := newLimitRules(RateLimit{RPS: 100}, []EndpointRateLimit{
+		{Method: "POST", Path: "/customers/{customerId}/exports", RateLimit: RateLimit{RPS: 1}},
+		{Path: "/customers/{id}", RateLimit: RateLimit{RPS: 2}},
+		{Path: "/search*", RateLimit: RateLimit{RPS: 3}},
+	})
+	tests := []struct {
+		method, template string
+		wantKey          string
+		wantRPS          float64
+	}{
+		{"POST", "/customers/{id}/exports", "POST /customers/{customerId}/exports", 1},
+		{"GET", "/customers/{id}/exports", "*", 100},
+		{"GET", "/customers/{id}", "/customers/{id}", 2},
+		{"DELETE", "/customers/{id}", "/customers/{id}", 2},
+		{"GET", "/search/customers", "/search*", 3},
+		{"GET", "/customers", "*", 100},
+	}
+	for _, tt := range tests {
+		key, limit := rules.match(tt.method, tt.template)
+		if key != tt.wantKey || limit.RPS != tt.wantRPS {
+			t.Errorf("match(%s %s) = %q, %v; want %q, %v", tt.method, tt.template, key, limit.RPS, tt.wantKey, tt.wantRPS)
+		}
+	}
+}
+
+func TestPathParamsTemplateMatchesEndpointRules(t *testing.T) {
+	limiter := &templateRecorder{}
+	client, err := NewClient(ClientOptions{
+		BaseURL:     "http://limits.test",
+		HTTPClient:  &http.Client{Transport: fuzzTransport{status: 200, body: []byte(`{}`)}},
+		RateLimiter: limiter,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := newLimitRules(RateLimit{}, []EndpointRateLimit{
+		{Method: "GET", Path: "/customers/{customerId}/invoices/{invoiceId}", RateLimit: RateLimit{RPS: 1}},
+	})
+	calls := []struct {
+		path string
+		opts []RequestOption
+	}{
+		{"/customers/{customerId}/invoices/{invoiceId}", []RequestOption{WithPathParams(PathParams{"customerId": "cus_abc", "invoiceId": "inv_def"})}},
+		{"/customers/cus_123/invoices/inv_456", nil},
+	}
+	for _, call := range calls {
+		if err := client.Get(context.Background(), call.path, nil, call.opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, template := range limiter.templates {
+		if template != "/customers/{id}/invoices/{id}" {
+			t.Errorf("call %d: template %q", i, template)
+		}
+		if key, _ := rules.match("GET", template); key == "*" {
+			t.Errorf("call %d: template %q matches no endpoint rule", i, template)
+		}
+	}
+}
+
+func TestDebugSnapshotBudgets(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		BaseURL:    "http://limits.test",
+		HTTPClient: &http.Client{Transport: fuzzTransport{status: 200, body: []byte(`{}`)}},
+		RateLimit:  RateLimit{RPS: 1, Burst: 5},
+		EndpointRateLimits: []EndpointRateLimit{
+			{Method: "post", Path: "/customers/{customerId}/exports", RateLimit: RateLimit{RPS: 1, Burst: 2}},
+			{Path: "/search*", RateLimit: RateLimit{RPS: 1, Burst: 3}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := client.Post(ctx, "/customers/cus_123/exports", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get(ctx, "/customers", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	snap := client.DebugSnapshot()
+	if snap.Budget.Local != 4 {
+		t.Errorf("client-wide budget %d, want 4", snap.Budget.Local)
+	}
+	want := []struct {
+		method, path string
+		local        int
+	}{
+		{"POST", "/customers/{customerId}/exports", 1},
+		{"", "/search*", 3},
+	}
+	if len(snap.EndpointBudgets) != len(want) {
+		t.Fatalf("got %d endpoint budgets, want %d", len(snap.EndpointBudgets), len(want))
+	}
+	for i, w := range want {
+		got := snap.EndpointBudgets[i]
+		if got.Method != w.method || got.Path != w.path || got.Local != w.local {
+			t.Errorf("endpoint budget %d = %s %s %d, want %s %s %d", i, got.Method, got.Path, got.Local, w.method, w.path, w.local)
+		}
+	}
+}
+
+func TestRateLimitStateBeforeResponses(t *testing.T) {
+	client, err := NewClient(ClientOptions{BaseURL: "http://limits.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := client.RateLimitState()
+	if state.Limit != -1 || state.Remaining != -1 || !state.UpdatedAt.IsZero() {
+		t.Errorf("RateLimitState() = %+v before any response, want -1 limit and remaining", state)
+	}
+	if b := client.RemainingBudget(); b.Remaining != -1 {
+		t.Errorf("RemainingBudget().Remaining = %d, want -1", b.Remaining)
+	}
+}
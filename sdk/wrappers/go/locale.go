@@ -0,0 +1,46 @@
+package yourapi
+
+import "strings"
+
+// localizedMessage returns the error message best matching locale. The API
+// either sends a ready-made localizedMessage, or a map of language tags to
+// translations under message or messages.
+func localizedMessage(body map[string]interface{}, locale string) string {
+	if msg, ok := body["localizedMessage"].(string); ok && msg != "" {
+		return msg
+	}
+	if locale == "" {
+		return ""
+	}
+
+	for _, key := range []string{"message", "messages"} {
+		translations, ok := body[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if msg := matchLocale(translations, locale); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// matchLocale looks up a translation by exact tag, then by base language
+// (so "fr-CA" falls back to "fr")
+func matchLocale(translations map[string]interface{}, locale string) string {
+	base, _, _ := strings.Cut(locale, "-")
+	var baseMatch string
+	for tag, v := range translations {
+		msg, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(tag, locale) {
+			return msg
+		}
+		if strings.EqualFold(tag, base) {
+			baseMatch = msg
+		}
+	}
+	return baseMatch
+}
@@ -0,0 +1,149 @@
+package yourapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+)
+
+// Logger receives the client's log events. *slog.Logger satisfies it, as do
+// thin adapters over zap, zerolog or logrus.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// NewSlogLogger returns a Logger writing through handler, tagging every
+// record with sdk=yourapi
+func NewSlogLogger(handler slog.Handler) Logger {
+	return slog.New(handler).With("sdk", "yourapi")
+}
+
+// DebugFormat selects how debug output is rendered
+type DebugFormat string
+
+const (
+	// DebugFormatText renders debug events as key=value text lines
+	DebugFormatText DebugFormat = "text"
+	// DebugFormatJSON renders each debug event as a JSON object per line
+	DebugFormatJSON DebugFormat = "json"
+)
+
+// Log event names, recorded under the "event" key of every log record so
+// aggregation systems can index SDK activity
+const (
+	// EventRequestStart is logged before each attempt, with method, url,
+	// attempt and maxAttempts
+	EventRequestStart = "request.start"
+	// EventResponse is logged for each response, with method, url and status
+	EventResponse = "response"
+	// EventRetry is logged when a retry is scheduled, with method, url,
+	// attempt, backoffMs and either status or error
+	EventRetry = "retry"
+	// EventRequestBody is logged with DebugBodies, with method, url and body
+	EventRequestBody = "request.body"
+	// EventResponseBody is logged with DebugBodies, with method, url, status
+	// and body
+	EventResponseBody = "response.body"
+	// EventSlowRequest is logged for requests over SlowRequestThreshold,
+	// with method, path, status, durationMs, thresholdMs, attemptsMs and
+	// backoffMs
+	EventSlowRequest = "request.slow"
+	// EventInsecureTLS is logged when a client is created with
+	// TLS.InsecureSkipVerify, with baseUrl
+	EventInsecureTLS = "tls.insecure"
+)
+
+// defaultLogger returns the logger used when ClientOptions.Logger is not
+// set: debug-level output on w (stdout if nil) in Debug mode, otherwise
+// nothing
+func defaultLogger(debug bool, w io.Writer, format DebugFormat) Logger {
+	if !debug {
+		return noopLogger{}
+	}
+	if w == nil {
+		w = os.Stdout
+	}
+
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	if format == DebugFormatJSON {
+		return NewSlogLogger(slog.NewJSONHandler(w, opts))
+	}
+	return NewSlogLogger(slog.NewTextHandler(w, opts))
+}
+
+// promotedLogger logs debug events at info level, for requests made
+// WithDebug against a logger that filters out debug
+type promotedLogger struct {
+	Logger
+}
+
+func (l promotedLogger) Debug(msg string, args ...interface{}) {
+	l.Logger.Info(msg, args...)
+}
+
+// callLogger returns the logger for the request tracked in ctx and whether
+// bodies should be logged
+func (c *Client) callLogger(ctx context.Context) (Logger, bool) {
+	return c.loggerFor(callFromContext(ctx))
+}
+
+// loggerFor returns the logger for a tracked request and whether bodies
+// should be logged
+func (c *Client) loggerFor(ci *callInfo) (Logger, bool) {
+	if ci != nil && ci.debug {
+		return c.debugLogger, true
+	}
+	return c.logger, c.debugBodies
+}
+
+// noopLogger discards all log events
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, ...interface{}) {}
+func (noopLogger) Info(string, ...interface{})  {}
+func (noopLogger) Warn(string, ...interface{})  {}
+func (noopLogger) Error(string, ...interface{}) {}
+
+// loggableBody redacts and caps a complete body for logging
+func (c *Client) loggableBody(body []byte) string {
+	redacted := c.redactor.Body(body)
+	if len(redacted) > c.maxLoggedBodySize {
+		return fmt.Sprintf("%s...(truncated, %d bytes total)", redacted[:c.maxLoggedBodySize], len(redacted))
+	}
+	return string(redacted)
+}
+
+// logResponseBody logs the start of a response body without consuming it.
+// A body longer than the cap cannot be redacted reliably, so when body
+// fields are redacted only its size is logged.
+func (c *Client) logResponseBody(logger Logger, method, url string, resp *http.Response) {
+	prefix, err := io.ReadAll(io.LimitReader(resp.Body, int64(c.maxLoggedBodySize)+1))
+	resp.Body = readCloser{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+	if err != nil {
+		return
+	}
+
+	var logged string
+	switch {
+	case len(prefix) <= c.maxLoggedBodySize:
+		logged = c.loggableBody(prefix)
+	case len(c.redactor.fields) > 0:
+		logged = fmt.Sprintf("(more than %d bytes, omitted)", c.maxLoggedBodySize)
+	default:
+		logged = fmt.Sprintf("%s...(truncated)", prefix[:c.maxLoggedBodySize])
+	}
+	logger.Debug("response body", "event", EventResponseBody, "method", method, "url", url, "status", resp.StatusCode, "body", logged)
+}
+
+// readCloser pairs a reader with the Close of the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
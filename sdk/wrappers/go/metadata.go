@@ -0,0 +1,27 @@
+package yourapi
+
+import (
+	"net/http"
+	"time"
+)
+
+// ResponseMetadata describes how a request went, filled in by WithMetadata
+// whether the request succeeded or failed
+type ResponseMetadata struct {
+	// Status is the final HTTP status, 0 if no response was received
+	Status int
+	// Header holds the final response's headers, nil if none was received
+	Header http.Header
+	// RequestID is the server's X-Request-Id
+	RequestID string
+	// APIVersion is the API version the server reports having served, from
+	// its APIVersionHeader
+	APIVersion string
+	// IdempotencyKey is the Idempotency-Key sent, whether provided with
+	// WithIdempotencyKey or generated by the client
+	IdempotencyKey string
+	// Attempts is the number of attempts made, including retries
+	Attempts int
+	// Duration covers all attempts, including backoff waits
+	Duration time.Duration
+}
@@ -0,0 +1,111 @@
+package yourapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// MetricsSink receives request-level measurements. PrometheusMetrics is a
+// ready-made implementation; other metrics systems can implement it directly.
+type MetricsSink interface {
+	// RequestStarted is called when a logical request begins
+	RequestStarted(method, pathTemplate string)
+	// RequestFinished is called once the request completes, after retries
+	RequestFinished(m RequestMetrics)
+}
+
+// RequestMetrics describes a completed logical request
+type RequestMetrics struct {
+	Method       string
+	PathTemplate string
+	// Status is the final HTTP status, or 0 when no response was received
+	Status int
+	// Code is the error code as in ErrorMetricKey, empty on success
+	Code string
+	// Duration covers all attempts, including backoff waits
+	Duration time.Duration
+	// Retries is the number of attempts beyond the first
+	Retries int
+	// Timings covers the last attempt when ClientOptions.ConnectionTimings
+	// is enabled
+	Timings *ConnTimings
+}
+
+// ErrorMetricKey identifies a class of failed requests for metrics
+type ErrorMetricKey struct {
+	// Method is the HTTP method
+	Method string
+	// PathTemplate is the request path with identifiers replaced by {id}
+	PathTemplate string
+	// Status is the HTTP status, or 0 when no response was received
+	Status int
+	// Code is the API error code, the network error kind (e.g.
+	// "connect_timeout"), "canceled" / "deadline_exceeded" for context
+	// errors, or "throttled_locally" for WithFailFast rejections
+	Code string
+}
+
+// ErrorCounter is incremented once for every terminal error
+type ErrorCounter func(key ErrorMetricKey)
+
+// errorMetricKey classifies a terminal error for the ErrorCounter; template
+// is the call's path template, as reported to metrics and traces
+func errorMetricKey(method, template string, err error) ErrorMetricKey {
+	key := ErrorMetricKey{Method: method, PathTemplate: template}
+
+	if apiErr, ok := AsAPIError(err); ok {
+		key.Status = apiErr.Status
+		key.Code = apiErr.Code
+		return key
+	}
+
+	var redirectErr *RedirectError
+	if errors.As(err, &redirectErr) {
+		key.Status = redirectErr.Status
+		return key
+	}
+
+	var throttledErr *ThrottledLocallyError
+	if errors.As(err, &throttledErr) {
+		key.Code = "throttled_locally"
+		return key
+	}
+
+	switch {
+	case errors.Is(err, ErrNotModified):
+		key.Status = 304
+	case errors.Is(err, context.Canceled):
+		key.Code = "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		key.Code = "deadline_exceeded"
+	default:
+		if kind, ok := NetworkErrorKindOf(err); ok {
+			key.Code = strings.ReplaceAll(kind.String(), " ", "_")
+		}
+	}
+	return key
+}
+
+// recordMetrics reports a finished call to the metrics sink
+func (c *Client) recordMetrics(ci *callInfo, err error) {
+	m := RequestMetrics{
+		Method:       ci.method,
+		PathTemplate: ci.template,
+		Status:       ci.status,
+		Duration:     c.clock.Now().Sub(ci.start),
+		Timings:      ci.connTimings(),
+	}
+	if ci.attempts > 1 {
+		m.Retries = ci.attempts - 1
+	}
+	if err != nil {
+		key := errorMetricKey(ci.method, ci.template, err)
+		m.Code = key.Code
+		if key.Status != 0 {
+			m.Status = key.Status
+		}
+	}
+	c.metrics.RequestFinished(m)
+}
@@ -0,0 +1,220 @@
+// Code generated by saligen-go. DO NOT EDIT.
+
+// Package models holds the API's data types, generated from the
+// OpenAPI spec
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/devdraft/devdraft-sdk-go/types"
+	"github.com/devdraft/devdraft-sdk-go/validate"
+)
+
+// Customer is the Customer schema
+type Customer struct {
+	// ID Unique customer identifier
+	ID string `json:"id"`
+	// Email Customer email address
+	Email string `json:"email"`
+	// Name Customer name
+	Name string `json:"name"`
+	// Phone Customer phone number
+	Phone types.Nullable[string] `json:"phone,omitempty"`
+	// CreatedAt Customer creation timestamp
+	CreatedAt time.Time `json:"createdAt"`
+	// UpdatedAt Customer last update timestamp
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Validate checks m against the constraints in the API spec
+func (m Customer) Validate() error {
+	var errs validate.Errors
+	errs.Required("id", m.ID == "")
+	if m.ID != "" {
+		errs.Format("id", m.ID, "uuid")
+	}
+	errs.Required("email", m.Email == "")
+	if m.Email != "" {
+		errs.Format("email", m.Email, "email")
+	}
+	errs.Required("name", m.Name == "")
+	return errs.Err()
+}
+
+// MarshalJSON leaves unset Nullable fields out of the encoding
+func (m Customer) MarshalJSON() ([]byte, error) {
+	type plain Customer
+	out := struct {
+		plain
+		Phone *types.Nullable[string] `json:"phone,omitempty"`
+	}{plain: plain(m)}
+	if m.Phone.IsSet() {
+		out.Phone = &m.Phone
+	}
+	return json.Marshal(out)
+}
+
+// CustomerCreateRequest is the CustomerCreateRequest schema
+type CustomerCreateRequest struct {
+	Email string                 `json:"email"`
+	Name  string                 `json:"name"`
+	Phone types.Nullable[string] `json:"phone,omitempty"`
+}
+
+// Validate checks m against the constraints in the API spec
+func (m CustomerCreateRequest) Validate() error {
+	var errs validate.Errors
+	errs.Required("email", m.Email == "")
+	if m.Email != "" {
+		errs.Format("email", m.Email, "email")
+	}
+	errs.Required("name", m.Name == "")
+	if m.Name != "" {
+		errs.MinLength("name", m.Name, 1)
+		errs.MaxLength("name", m.Name, 255)
+	}
+	return errs.Err()
+}
+
+// MarshalJSON leaves unset Nullable fields out of the encoding
+func (m CustomerCreateRequest) MarshalJSON() ([]byte, error) {
+	type plain CustomerCreateRequest
+	out := struct {
+		plain
+		Phone *types.Nullable[string] `json:"phone,omitempty"`
+	}{plain: plain(m)}
+	if m.Phone.IsSet() {
+		out.Phone = &m.Phone
+	}
+	return json.Marshal(out)
+}
+
+// CustomerCreatedEvent is the payload of customer.created webhooks
+type CustomerCreatedEvent struct {
+	// ID Unique event identifier
+	ID string `json:"id"`
+	// Type Event type
+	Type string `json:"type"`
+	// CreatedAt When the event occurred
+	CreatedAt time.Time `json:"createdAt"`
+	Data      Customer  `json:"data"`
+}
+
+// Validate checks m against the constraints in the API spec
+func (m CustomerCreatedEvent) Validate() error {
+	var errs validate.Errors
+	errs.Required("id", m.ID == "")
+	errs.Required("type", m.Type == "")
+	errs.Nested("data", m.Data.Validate())
+	return errs.Err()
+}
+
+// CustomerDeletedEvent is the payload of customer.deleted webhooks
+type CustomerDeletedEvent struct {
+	// ID Unique event identifier
+	ID string `json:"id"`
+	// Type Event type
+	Type string `json:"type"`
+	// CreatedAt When the event occurred
+	CreatedAt time.Time                `json:"createdAt"`
+	Data      CustomerDeletedEventData `json:"data"`
+}
+
+// Validate checks m against the constraints in the API spec
+func (m CustomerDeletedEvent) Validate() error {
+	var errs validate.Errors
+	errs.Required("id", m.ID == "")
+	errs.Required("type", m.Type == "")
+	errs.Nested("data", m.Data.Validate())
+	return errs.Err()
+}
+
+// CustomerDeletedEventData is generated from an inline schema
+type CustomerDeletedEventData struct {
+	// ID ID of the deleted customer
+	ID string `json:"id"`
+}
+
+// Validate checks m against the constraints in the API spec
+func (m CustomerDeletedEventData) Validate() error {
+	var errs validate.Errors
+	errs.Required("id", m.ID == "")
+	if m.ID != "" {
+		errs.Format("id", m.ID, "uuid")
+	}
+	return errs.Err()
+}
+
+// CustomerListResponse is the CustomerListResponse schema
+type CustomerListResponse struct {
+	Items []Customer `json:"items"`
+	// NextCursor Cursor for next page, null if no more pages
+	NextCursor types.Nullable[string] `json:"nextCursor,omitempty"`
+	// HasMore Whether more results are available
+	HasMore bool `json:"hasMore"`
+}
+
+// Validate checks m against the constraints in the API spec
+func (m CustomerListResponse) Validate() error {
+	var errs validate.Errors
+	errs.Required("items", m.Items == nil)
+	for i, item := range m.Items {
+		errs.Nested(validate.Index("items", i), item.Validate())
+	}
+	return errs.Err()
+}
+
+// MarshalJSON leaves unset Nullable fields out of the encoding
+func (m CustomerListResponse) MarshalJSON() ([]byte, error) {
+	type plain CustomerListResponse
+	out := struct {
+		plain
+		NextCursor *types.Nullable[string] `json:"nextCursor,omitempty"`
+	}{plain: plain(m)}
+	if m.NextCursor.IsSet() {
+		out.NextCursor = &m.NextCursor
+	}
+	return json.Marshal(out)
+}
+
+// CustomerUpdatedEvent is the payload of customer.updated webhooks
+type CustomerUpdatedEvent struct {
+	// ID Unique event identifier
+	ID string `json:"id"`
+	// Type Event type
+	Type string `json:"type"`
+	// CreatedAt When the event occurred
+	CreatedAt time.Time `json:"createdAt"`
+	Data      Customer  `json:"data"`
+}
+
+// Validate checks m against the constraints in the API spec
+func (m CustomerUpdatedEvent) Validate() error {
+	var errs validate.Errors
+	errs.Required("id", m.ID == "")
+	errs.Required("type", m.Type == "")
+	errs.Nested("data", m.Data.Validate())
+	return errs.Err()
+}
+
+// Error is the Error schema
+type Error struct {
+	// Code Error code for programmatic handling
+	Code string `json:"code"`
+	// Message Human-readable error message
+	Message string `json:"message"`
+	// RequestID Request ID for tracking
+	RequestID *string `json:"requestId,omitempty"`
+	// Timestamp Error timestamp
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// Validate checks m against the constraints in the API spec
+func (m Error) Validate() error {
+	var errs validate.Errors
+	errs.Required("code", m.Code == "")
+	errs.Required("message", m.Message == "")
+	return errs.Err()
+}
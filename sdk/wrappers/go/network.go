@@ -0,0 +1,134 @@
+package yourapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// NetworkErrorKind categorizes transport failures
+type NetworkErrorKind int
+
+const (
+	// NetworkErrorUnknown is a transport failure that could not be classified
+	NetworkErrorUnknown NetworkErrorKind = iota
+	// DNSError means the API host name could not be resolved
+	DNSError
+	// ConnectTimeout means no connection could be established in time
+	ConnectTimeout
+	// ConnectionRefused means the API host actively refused the connection
+	ConnectionRefused
+	// TLSError means the TLS handshake or certificate verification failed
+	TLSError
+	// ConnectionReset means an established connection was dropped
+	ConnectionReset
+	// DeadlineExceeded means the request timeout or the caller's context
+	// deadline expired
+	DeadlineExceeded
+)
+
+func (k NetworkErrorKind) String() string {
+	switch k {
+	case DNSError:
+		return "dns error"
+	case ConnectTimeout:
+		return "connect timeout"
+	case ConnectionRefused:
+		return "connection refused"
+	case TLSError:
+		return "tls error"
+	case ConnectionReset:
+		return "connection reset"
+	case DeadlineExceeded:
+		return "deadline exceeded"
+	default:
+		return "network error"
+	}
+}
+
+// NetworkError wraps a transport failure with its category
+type NetworkError struct {
+	Kind NetworkErrorKind
+	Err  error
+}
+
+func (e *NetworkError) Error() string {
+	return e.Kind.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying transport error
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// NetworkErrorKindOf returns the category of the NetworkError in err's chain
+func NetworkErrorKindOf(err error) (NetworkErrorKind, bool) {
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return netErr.Kind, true
+	}
+	return NetworkErrorUnknown, false
+}
+
+// classifyNetworkError wraps an error returned by http.Client.Do
+func classifyNetworkError(err error) *NetworkError {
+	return &NetworkError{Kind: networkErrorKind(err), Err: err}
+}
+
+func networkErrorKind(err error) NetworkErrorKind {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return DeadlineExceeded
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return DNSError
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		if opErr.Timeout() {
+			return ConnectTimeout
+		}
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return ConnectionRefused
+		}
+	}
+
+	if isTLSError(err) {
+		return TLSError
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ConnectionReset
+	}
+
+	var timeoutErr interface{ Timeout() bool }
+	if errors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+		return DeadlineExceeded
+	}
+
+	return NetworkErrorUnknown
+}
+
+func isTLSError(err error) bool {
+	var (
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	return errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
@@ -0,0 +1,188 @@
+package yourapi
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Option configures a client built with New. Any ClientOptions field can
+// be set with a function literal:
+//
+//	client, err := yourapi.New(baseURL, func(o *yourapi.ClientOptions) {
+//		o.Debug = true
+//	})
+type Option func(*ClientOptions)
+
+// NoTimeout as ClientOptions.Timeout or in WithAttemptTimeout leaves
+// attempts without a timeout, bounded only by their context
+const NoTimeout time.Duration = -1
+
+// New creates a client for the API at baseURL configured by opts. It is
+// NewClient with functional options: options added in later versions do
+// not break callers, and a zero passed to an option means zero, not the
+// default.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	o := ClientOptions{BaseURL: baseURL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return NewClient(o)
+}
+
+// Clone returns a client configured like c with opts applied on top, e.g.
+// another tenant's credentials, extra headers or a longer timeout. The
+// clone shares c's connection pool and client-side rate limiting, so it is
+// cheap to create and counts against the same quota; rate limiting and
+// transport options such as ProxyURL in opts are ignored unless they set
+// HTTPClient. Its logs, events and statistics are its own.
+func (c *Client) Clone(opts ...Option) (*Client, error) {
+	o := c.options
+	if o.CustomHeaders != nil {
+		headers := make(map[string]string, len(o.CustomHeaders))
+		for k, v := range o.CustomHeaders {
+			headers[k] = v
+		}
+		o.CustomHeaders = headers
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newClient(o, c)
+}
+
+// WithAPIKey authenticates with an API key, replacing any bearer token
+func WithAPIKey(key string) Option {
+	return func(o *ClientOptions) {
+		o.APIKey, o.BearerToken = key, ""
+	}
+}
+
+// WithBearerToken authenticates with a bearer token, replacing any API key
+func WithBearerToken(token string) Option {
+	return func(o *ClientOptions) {
+		o.APIKey, o.BearerToken = "", token
+	}
+}
+
+// WithDefaultTimeout sets ClientOptions.Timeout, the limit on each attempt
+// of every request. WithAttemptTimeout replaces it for one call, while
+// WithTimeout only adds a bound on the whole call, retries included. Zero
+// disables it.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(o *ClientOptions) {
+		if d == 0 {
+			d = NoTimeout
+		}
+		o.Timeout = d
+	}
+}
+
+// WithMaxRetries sets how many times a failed request is retried. Zero
+// disables retries.
+func WithMaxRetries(n int) Option {
+	return func(o *ClientOptions) {
+		if n == 0 {
+			n = -1
+		}
+		o.MaxRetries = n
+	}
+}
+
+// WithRetryBackoff tunes the wait between retries
+func WithRetryBackoff(b RetryBackoff) Option {
+	return func(o *ClientOptions) {
+		o.RetryBackoff = b
+	}
+}
+
+// WithUserAgent replaces the SDK's User-Agent
+func WithUserAgent(ua string) Option {
+	return func(o *ClientOptions) {
+		o.UserAgent = ua
+	}
+}
+
+// WithDefaultHeader adds a header to every request, which WithHeader
+// overrides per call
+func WithDefaultHeader(key, value string) Option {
+	return func(o *ClientOptions) {
+		headers := make(map[string]string, len(o.CustomHeaders)+1)
+		for k, v := range o.CustomHeaders {
+			headers[k] = v
+		}
+		headers[key] = value
+		o.CustomHeaders = headers
+	}
+}
+
+// WithHTTPClient sends requests through hc
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *ClientOptions) {
+		o.HTTPClient = hc
+	}
+}
+
+// WithProxy sends requests through the proxy at proxyURL, except to the
+// hosts matching noProxy, see ClientOptions.NoProxy
+func WithProxy(proxyURL string, noProxy ...string) Option {
+	return func(o *ClientOptions) {
+		o.ProxyURL, o.NoProxy = proxyURL, noProxy
+	}
+}
+
+// WithTLS sets how the API's certificate is verified, see TLSOptions
+func WithTLS(t TLSOptions) Option {
+	return func(o *ClientOptions) {
+		o.TLS = t
+	}
+}
+
+// WithDialContext opens the connections of requests with dial, see
+// ClientOptions.DialContext
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(o *ClientOptions) {
+		o.DialContext = dial
+	}
+}
+
+// WithResolver looks up hosts with r, see ClientOptions.Resolver
+func WithResolver(r *net.Resolver) Option {
+	return func(o *ClientOptions) {
+		o.Resolver = r
+	}
+}
+
+// WithHost dials ip for host, overriding DNS, see ClientOptions.Hosts
+func WithHost(host, ip string) Option {
+	return func(o *ClientOptions) {
+		hosts := make(map[string]string, len(o.Hosts)+1)
+		for k, v := range o.Hosts {
+			hosts[k] = v
+		}
+		hosts[host] = ip
+		o.Hosts = hosts
+	}
+}
+
+// WithLogger sends the client's logs to l
+func WithLogger(l Logger) Option {
+	return func(o *ClientOptions) {
+		o.Logger = l
+	}
+}
+
+// WithTracerProvider records a span per request
+func WithTracerProvider(tp TracerProvider) Option {
+	return func(o *ClientOptions) {
+		o.TracerProvider = tp
+	}
+}
+
+// WithMetrics reports request metrics to sink
+func WithMetrics(sink MetricsSink) Option {
+	return func(o *ClientOptions) {
+		o.Metrics = sink
+	}
+}
@@ -0,0 +1,56 @@
+package yourapi_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+	"github.com/devdraft/devdraft-sdk-go/yourapitest"
+)
+
+func TestPauseOnRateLimitUsesClientClock(t *testing.T) {
+	// Years before the real time, so a pause measured with time.Now would
+	// find the reset long past
+	clock := yourapitest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"reset", http.Header{"X-Ratelimit-Reset": {strconv.FormatInt(clock.Now().Add(30*time.Second).Unix(), 10)}}, 30 * time.Second},
+		{"retry after", http.Header{"Retry-After": {"12"}}, 12 * time.Second},
+		{"no hint", nil, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := yourapitest.NewFakeClock(clock.Now())
+			stub := yourapitest.NewStubTransport().On(http.MethodGet, "/customers",
+				yourapitest.StubResponse{Status: http.StatusTooManyRequests, Header: tt.header, Body: `{"code":"rate_limited"}`},
+				yourapitest.StubResponse{JSON: map[string]interface{}{"items": []interface{}{"a"}}},
+			)
+			opts := stub.ClientOptions()
+			opts.Clock = clock
+			opts.MaxRetries = -1
+			client, err := yourapi.NewClient(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			errc := make(chan error, 1)
+			go func() {
+				errc <- client.PaginateCursor(context.Background(), "/customers", func(interface{}) error { return nil }, yourapi.WithPauseOnRateLimit(0))
+			}()
+			clock.BlockUntil(1)
+			clock.AdvanceToNext()
+			if err := <-errc; err != nil {
+				t.Fatal(err)
+			}
+			if waits := clock.Waits(); len(waits) != 1 || waits[0] != tt.want {
+				t.Errorf("paused %v, want [%v]", waits, tt.want)
+			}
+		})
+	}
+}
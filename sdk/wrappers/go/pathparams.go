@@ -0,0 +1,78 @@
+package yourapi
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// PathParams holds the values substituted into a path template's {name}
+// segments. Values are formatted with fmt.Sprint.
+type PathParams map[string]interface{}
+
+// ExpandPath substitutes params into template, escaping each value so it
+// stays a single path segment: "/customers/{id}" with id "a/b c" becomes
+// "/customers/a%2Fb%20c". It fails if a placeholder has no value or an
+// empty one, if a value is "." or "..", or if a param is not used.
+func ExpandPath(template string, params PathParams) (string, error) {
+	var b strings.Builder
+	used := make(map[string]bool, len(params))
+	rest := template
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("path template %q has an unterminated parameter", template)
+		}
+		name := rest[open+1 : open+end]
+		v, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("path parameter %q is missing", name)
+		}
+		value := fmt.Sprint(v)
+		switch value {
+		case "":
+			return "", fmt.Errorf("path parameter %q is empty", name)
+		case ".", "..":
+			return "", fmt.Errorf("path parameter %q cannot be %q", name, value)
+		}
+		used[name] = true
+		b.WriteString(rest[:open])
+		b.WriteString(url.PathEscape(value))
+		rest = rest[open+end+1:]
+	}
+	b.WriteString(rest)
+
+	if len(used) < len(params) {
+		var unused []string
+		for name := range params {
+			if !used[name] {
+				unused = append(unused, name)
+			}
+		}
+		sort.Strings(unused)
+		return "", fmt.Errorf("path template %q does not use %s", template, strings.Join(unused, ", "))
+	}
+	return b.String(), nil
+}
+
+// WithPathParams expands the request path as a template with params, see
+// ExpandPath. Metrics, traces and stats report the unexpanded template, so
+// it also keeps their cardinality low.
+func WithPathParams(params PathParams) RequestOption {
+	return func(ro *requestOptions) {
+		ro.pathParams = params
+	}
+}
+
+// pathOptions resolves opts for a generated method, expanding its path
+// template with params
+func pathOptions(opts []RequestOption, params PathParams) requestOptions {
+	ro := newRequestOptions(opts)
+	ro.pathParams = params
+	return ro
+}
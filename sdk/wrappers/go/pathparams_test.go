@@ -0,0 +1,102 @@
+package yourapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	tests := []struct {
+		template string
+		params   PathParams
+		want     string
+		wantErr  bool
+	}{
+		{"/customers/{id}", PathParams{"id": "cus_123"}, "/customers/cus_123", false},
+		{"/customers/{id}", PathParams{"id": "a/b c?"}, "/customers/a%2Fb%20c%3F", false},
+		{"/customers/{id}/invoices/{n}", PathParams{"id": "c", "n": 42}, "/customers/c/invoices/42", false},
+		{"/a/{x}/b/{x}", PathParams{"x": "1"}, "/a/1/b/1", false},
+		{"/customers", nil, "/customers", false},
+		{"/customers/{id}", PathParams{}, "", true},
+		{"/customers/{id}", PathParams{"id": ""}, "", true},
+		{"/customers/{id}", PathParams{"id": ".."}, "", true},
+		{"/customers/{id}", PathParams{"id": "a", "extra": "b"}, "", true},
+		{"/customers/{id", PathParams{"id": "a"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := ExpandPath(tt.template, tt.params)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ExpandPath(%q, %v) = %q, %v; want %q, error %v", tt.template, tt.params, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// pathRecorder answers every request with an empty object, recording the
+// escaped paths requested
+type pathRecorder struct {
+	fuzzTransport
+	paths []string
+}
+
+func (r *pathRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.paths = append(r.paths, req.URL.EscapedPath())
+	return r.fuzzTransport.RoundTrip(req)
+}
+
+func TestGeneratedMethodsReportTemplates(t *testing.T) {
+	limiter := &templateRecorder{}
+	rt := &pathRecorder{fuzzTransport: fuzzTransport{status: 200, body: []byte(`{}`)}}
+	client, err := NewClient(ClientOptions{
+		BaseURL:     "http://services.test",
+		HTTPClient:  &http.Client{Transport: rt},
+		RateLimiter: limiter,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, id := range []string{"cus_abc", "cus_def", "a/b"} {
+		if _, err := client.Customers.Get(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.Customers.Delete(ctx, "cus_xyz"); err != nil {
+		t.Fatal(err)
+	}
+	wantPaths := []string{"/customers/cus_abc", "/customers/cus_def", "/customers/a%2Fb", "/customers/cus_xyz"}
+	for i, want := range wantPaths {
+		if rt.paths[i] != want {
+			t.Errorf("request %d: path %q, want %q", i, rt.paths[i], want)
+		}
+		if limiter.templates[i] != "/customers/{id}" {
+			t.Errorf("request %d: template %q, want /customers/{id}", i, limiter.templates[i])
+		}
+	}
+	if _, err := client.Customers.Get(ctx, ""); err == nil {
+		t.Error("Get with an empty ID succeeded")
+	}
+}
+
+func TestErrorCounterUsesPathTemplate(t *testing.T) {
+	var keys []ErrorMetricKey
+	client, err := NewClient(ClientOptions{
+		BaseURL:      "http://errors.test",
+		HTTPClient:   &http.Client{Transport: fuzzTransport{status: 404, body: []byte(`{"code":"not_found"}`)}},
+		ErrorCounter: func(key ErrorMetricKey) { keys = append(keys, key) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	client.Customers.Get(ctx, "acme")
+	client.Get(ctx, "/customers/{id}", nil, WithPathParams(PathParams{"id": "globex"}))
+	if len(keys) != 2 {
+		t.Fatalf("got %d error keys, want 2", len(keys))
+	}
+	for i, key := range keys {
+		if key.PathTemplate != "/customers/{id}" || key.Status != 404 {
+			t.Errorf("call %d: key %+v", i, key)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package yourapi
+
+import (
+	"strings"
+	"unicode"
+)
+
+// pathTemplate normalizes a request path into a low-cardinality template by
+// dropping the query string and replacing identifier segments with {id}, so
+// "/customers/8f14e45f-ceea-467f-a0c6-9a1e1d1b3b1c?expand=address" becomes
+// "/customers/{id}"
+func pathTemplate(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if isIdentifierSegment(seg) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isIdentifierSegment reports whether a path segment looks like a resource
+// identifier (numeric, UUID, or an opaque token containing digits) rather
+// than a collection or action name
+func isIdentifierSegment(seg string) bool {
+	if seg == "" || (strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")) {
+		return false
+	}
+
+	var digits int
+	for _, r := range seg {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsLetter(r), r == '-', r == '_', r == '.':
+		default:
+			// Escaped or unusual characters only appear in identifiers
+			return true
+		}
+	}
+	return digits > 0
+}
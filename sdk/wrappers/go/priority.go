@@ -0,0 +1,56 @@
+package yourapi
+
+import "sync/atomic"
+
+// Priority orders requests waiting on the client-side rate limiter or
+// concurrency limit; higher priorities are admitted first
+type Priority int
+
+const (
+	// PriorityBackground is for bulk and batch traffic that can wait
+	PriorityBackground Priority = -1
+	// PriorityNormal is the default priority
+	PriorityNormal Priority = 0
+	// PriorityHigh is for latency-sensitive, user-facing calls
+	PriorityHigh Priority = 1
+)
+
+// numPriorities is the number of distinct priority levels
+const numPriorities = 3
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackground:
+		return "background"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+// level maps p to a queue index, 0 being the highest priority
+func (p Priority) level() int {
+	switch {
+	case p >= PriorityHigh:
+		return 0
+	case p <= PriorityBackground:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// priorityWaiters counts requests waiting on the rate limiter per priority,
+// so lower-priority requests can yield to higher-priority ones
+type priorityWaiters [numPriorities]atomic.Int64
+
+// ahead reports whether requests of a higher priority than p are waiting
+func (w *priorityWaiters) ahead(p Priority) bool {
+	for level := 0; level < p.level(); level++ {
+		if w[level].Load() > 0 {
+			return true
+		}
+	}
+	return false
+}
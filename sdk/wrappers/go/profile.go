@@ -0,0 +1,284 @@
+package yourapi
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Environment variables selecting the config file profile
+const (
+	// EnvProfile names the profile LoadEnv applies (default: "default")
+	EnvProfile = "YOURAPI_PROFILE"
+	// EnvConfigFile replaces the config file path
+	EnvConfigFile = "YOURAPI_CONFIG_FILE"
+)
+
+// DefaultProfile is the profile applied when none is selected, if the
+// config file defines it
+const DefaultProfile = "default"
+
+// ConfigFilePaths returns the config file locations tried in order:
+// $YOURAPI_CONFIG_FILE when set, else ~/.yourapi/config.yaml, config.yml
+// and config.toml
+func ConfigFilePaths() []string {
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		return []string{path}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	dir := filepath.Join(home, ".yourapi")
+	return []string{
+		filepath.Join(dir, "config.yaml"),
+		filepath.Join(dir, "config.yml"),
+		filepath.Join(dir, "config.toml"),
+	}
+}
+
+// LoadProfile fills the fields of o that are still unset from the named
+// profile of the first config file found, so that several accounts or
+// environments can be kept side by side:
+//
+//	# ~/.yourapi/config.yaml
+//	prod:
+//	  base_url: https://api.yourorg.com/v1
+//	  api_key: sk_live_123
+//	staging:
+//	  base_url: https://staging-api.yourorg.com/v1
+//	  api_key: sk_test_456
+//	  timeout: 60s
+//
+// or the same as TOML, with a [prod] table per profile. A profile holds
+// base_url or environment (a known environment's name), api_key,
+// bearer_token, api_version, timeout, max_retries, user_agent and debug. An
+// empty name selects DefaultProfile, which may be missing; a named profile
+// that cannot be found is an error.
+func (o *ClientOptions) LoadProfile(name string) error {
+	explicit := name != ""
+	if !explicit {
+		name = DefaultProfile
+	}
+	path, data, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		if explicit {
+			return fmt.Errorf("profile %q: no config file found in %s", name, strings.Join(ConfigFilePaths(), ", "))
+		}
+		return nil
+	}
+	profiles, err := parseConfig(path, data)
+	if err != nil {
+		return err
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		if explicit {
+			return fmt.Errorf("%s: no profile %q", path, name)
+		}
+		return nil
+	}
+	if err := o.applyProfile(profile); err != nil {
+		return fmt.Errorf("%s: profile %q: %w", path, name, err)
+	}
+	return nil
+}
+
+// WithProfile selects the config file profile applied by NewClient, see
+// ClientOptions.LoadProfile
+func WithProfile(name string) Option {
+	return func(o *ClientOptions) {
+		o.Profile = name
+	}
+}
+
+// readConfigFile returns the path and contents of the first config file
+// found, or an empty path when there is none
+func readConfigFile() (string, []byte, error) {
+	for _, path := range ConfigFilePaths() {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		return path, data, nil
+	}
+	return "", nil, nil
+}
+
+// applyProfile fills the unset fields of o from a profile's settings
+func (o *ClientOptions) applyProfile(profile map[string]string) error {
+	// Credentials set in code replace the profile's, whichever kind they are
+	hasAuth := o.APIKey != "" || o.BearerToken != ""
+	keys := make([]string, 0, len(profile))
+	for key := range profile {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		v := profile[key]
+		var err error
+		switch key {
+		case "base_url":
+			if o.BaseURL == "" && o.Environment == "" {
+				o.BaseURL = v
+			}
+		case "environment":
+			if o.Environment == "" {
+				env, ok := LookupEnvironment(v)
+				if !ok {
+					return fmt.Errorf("environment: unknown environment %q", v)
+				}
+				o.Environment = env
+			}
+		case "api_key":
+			if !hasAuth {
+				o.APIKey = v
+			}
+		case "bearer_token":
+			if !hasAuth {
+				o.BearerToken = v
+			}
+		case "api_version":
+			if o.APIVersion == "" {
+				o.APIVersion = v
+			}
+		case "timeout":
+			if o.Timeout == 0 {
+				o.Timeout, err = parseEnvDuration(v)
+			}
+		case "max_retries":
+			if o.MaxRetries == 0 {
+				if o.MaxRetries, err = strconv.Atoi(v); err == nil && o.MaxRetries == 0 {
+					o.MaxRetries = -1
+				}
+			}
+		case "user_agent":
+			if o.UserAgent == "" {
+				o.UserAgent = v
+			}
+		case "debug":
+			if !o.Debug {
+				o.Debug, err = strconv.ParseBool(v)
+			}
+		default:
+			return fmt.Errorf("unknown setting %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: invalid value %q", key, v)
+		}
+	}
+	return nil
+}
+
+// parseConfig parses a config file into its profiles' settings, as TOML
+// for a .toml path and YAML otherwise. Both are read in the two-level
+// shape profiles need: a mapping or table per profile holding scalars.
+func parseConfig(path string, data []byte) (map[string]map[string]string, error) {
+	toml := strings.EqualFold(filepath.Ext(path), ".toml")
+	profiles := make(map[string]map[string]string)
+	var current map[string]string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		text := stripComment(sc.Text())
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		fail := func(format string, args ...interface{}) error {
+			return fmt.Errorf("%s:%d: %s", path, line, fmt.Sprintf(format, args...))
+		}
+
+		var key, value string
+		var ok bool
+		if toml {
+			if strings.HasPrefix(trimmed, "[") {
+				if !strings.HasSuffix(trimmed, "]") {
+					return nil, fail("unterminated table header")
+				}
+				name, err := configScalar(strings.TrimSpace(trimmed[1 : len(trimmed)-1]))
+				if err != nil || name == "" {
+					return nil, fail("invalid profile name")
+				}
+				current = make(map[string]string)
+				profiles[name] = current
+				continue
+			}
+			key, value, ok = strings.Cut(trimmed, "=")
+		} else {
+			if text[0] != ' ' && text[0] != '\t' {
+				name, rest, found := strings.Cut(trimmed, ":")
+				if !found || strings.TrimSpace(rest) != "" {
+					return nil, fail("want a profile name followed by a colon")
+				}
+				name, err := configScalar(strings.TrimSpace(name))
+				if err != nil || name == "" {
+					return nil, fail("invalid profile name")
+				}
+				current = make(map[string]string)
+				profiles[name] = current
+				continue
+			}
+			key, value, ok = strings.Cut(trimmed, ":")
+		}
+		if !ok {
+			return nil, fail("want key and value")
+		}
+		if current == nil {
+			return nil, fail("setting outside a profile")
+		}
+		v, err := configScalar(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fail("%v", err)
+		}
+		current[strings.TrimSpace(key)] = v
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// configScalar unquotes a double- or single-quoted value
+func configScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		return strconv.Unquote(s)
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("unterminated string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	return s, nil
+}
+
+// stripComment removes a # comment outside quotes from line
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
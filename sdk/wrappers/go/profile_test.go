@@ -0,0 +1,99 @@
+package yourapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		data    string
+		want    map[string]map[string]string
+		wantErr string
+	}{
+		{
+			name: "yaml",
+			path: "config.yaml",
+			data: "# profiles\nprod:\n  base_url: https://api.test/v1\n  api_key: \"sk_live # not a comment\"\n\nstaging:\n  timeout: 60s # comment\n  user_agent: 'it''s me'\n",
+			want: map[string]map[string]string{
+				"prod":    {"base_url": "https://api.test/v1", "api_key": "sk_live # not a comment"},
+				"staging": {"timeout": "60s", "user_agent": "it's me"},
+			},
+		},
+		{
+			name: "yaml document marker",
+			path: "config.yml",
+			data: "---\ndefault:\n\tdebug: true\n",
+			want: map[string]map[string]string{"default": {"debug": "true"}},
+		},
+		{
+			name: "toml",
+			path: "config.TOML",
+			data: "[sandbox]\nbase_url = \"https://sandbox.test\"\ndebug = true\n[\"my profile\"]\nmax_retries = 5\n",
+			want: map[string]map[string]string{
+				"sandbox":    {"base_url": "https://sandbox.test", "debug": "true"},
+				"my profile": {"max_retries": "5"},
+			},
+		},
+		{name: "yaml setting outside a profile", path: "config.yaml", data: "  api_key: x\n", wantErr: "config.yaml:1: setting outside a profile"},
+		{name: "yaml nested value", path: "config.yaml", data: "prod: x\n", wantErr: "config.yaml:1: want a profile name"},
+		{name: "yaml missing value", path: "config.yaml", data: "prod:\n  api_key\n", wantErr: "config.yaml:2: want key and value"},
+		{name: "unterminated string", path: "config.yaml", data: "prod:\n  api_key: 'x\n", wantErr: "config.yaml:2: unterminated string"},
+		{name: "toml unterminated table", path: "config.toml", data: "[prod\n", wantErr: "config.toml:1: unterminated table header"},
+		{name: "toml empty profile name", path: "config.toml", data: "[]\n", wantErr: "config.toml:1: invalid profile name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConfig(tt.path, []byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ClientOptions
+		profile map[string]string
+		check   func(ClientOptions) bool
+		wantErr bool
+	}{
+		{"fills unset", ClientOptions{}, map[string]string{"api_key": "k", "max_retries": "5"},
+			func(o ClientOptions) bool { return o.APIKey == "k" && o.MaxRetries == 5 }, false},
+		{"keeps set", ClientOptions{APIKey: "code"}, map[string]string{"api_key": "k"},
+			func(o ClientOptions) bool { return o.APIKey == "code" }, false},
+		{"api key skipped with a bearer token", ClientOptions{BearerToken: "tok"}, map[string]string{"api_key": "k"},
+			func(o ClientOptions) bool { return o.APIKey == "" }, false},
+		{"environment by name", ClientOptions{}, map[string]string{"environment": "sandbox"},
+			func(o ClientOptions) bool { return o.Environment == EnvironmentSandbox }, false},
+		{"unknown environment", ClientOptions{}, map[string]string{"environment": "moon"}, nil, true},
+		{"bad timeout", ClientOptions{}, map[string]string{"timeout": "soon"}, nil, true},
+		{"unknown setting", ClientOptions{}, map[string]string{"colour": "blue"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := tt.opts
+			err := o.applyProfile(tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.check != nil && !tt.check(o) {
+				t.Errorf("options not applied as expected: %+v", o)
+			}
+		})
+	}
+}
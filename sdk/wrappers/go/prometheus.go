@@ -0,0 +1,181 @@
+package yourapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are the request duration histogram buckets, in
+// seconds
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// PrometheusMetrics is a MetricsSink that exposes request totals, durations,
+// retries, in-flight requests and, with ClientOptions.ConnectionTimings,
+// per-phase connection timings in the Prometheus text exposition format.
+// It is an http.Handler; mount it on the embedding service's metrics mux:
+//
+//	metrics := yourapi.NewPrometheusMetrics("yourapi")
+//	mux.Handle("/metrics/yourapi", metrics)
+//
+// It depends only on the standard library, so services already using
+// client_golang can scrape the handler or implement MetricsSink against their
+// own registry instead.
+type PrometheusMetrics struct {
+	namespace string
+	buckets   []float64
+
+	mu        sync.Mutex
+	requests  map[string]float64
+	retries   map[string]float64
+	inFlight  map[string]float64
+	durations map[string]*histogram
+	phases    map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics creates a sink whose metric names are prefixed with
+// namespace (e.g. "yourapi_requests_total")
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	return &PrometheusMetrics{
+		namespace: namespace,
+		buckets:   DefaultDurationBuckets,
+		requests:  make(map[string]float64),
+		retries:   make(map[string]float64),
+		inFlight:  make(map[string]float64),
+		durations: make(map[string]*histogram),
+		phases:    make(map[string]*histogram),
+	}
+}
+
+// RequestStarted implements MetricsSink
+func (p *PrometheusMetrics) RequestStarted(method, pathTemplate string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight[labels("method", method, "path", pathTemplate)]++
+}
+
+// RequestFinished implements MetricsSink
+func (p *PrometheusMetrics) RequestFinished(m RequestMetrics) {
+	endpoint := labels("method", m.Method, "path", m.PathTemplate)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inFlight[endpoint]--
+	p.requests[labels("method", m.Method, "path", m.PathTemplate, "status", strconv.Itoa(m.Status), "code", m.Code)]++
+	if m.Retries > 0 {
+		p.retries[endpoint] += float64(m.Retries)
+	}
+
+	p.observe(p.durations, endpoint, m.Duration.Seconds())
+	if t := m.Timings; t != nil {
+		if !t.Reused {
+			p.observe(p.phases, labels("method", m.Method, "path", m.PathTemplate, "phase", "dns"), t.DNS.Seconds())
+			p.observe(p.phases, labels("method", m.Method, "path", m.PathTemplate, "phase", "connect"), t.Connect.Seconds())
+			if t.TLS > 0 {
+				p.observe(p.phases, labels("method", m.Method, "path", m.PathTemplate, "phase", "tls"), t.TLS.Seconds())
+			}
+		}
+		p.observe(p.phases, labels("method", m.Method, "path", m.PathTemplate, "phase", "server"), t.Server.Seconds())
+		p.observe(p.phases, labels("method", m.Method, "path", m.PathTemplate, "phase", "ttfb"), t.TTFB.Seconds())
+	}
+}
+
+// observe adds a sample to the histogram for series, creating it if needed
+func (p *PrometheusMetrics) observe(hists map[string]*histogram, series string, seconds float64) {
+	h, ok := hists[series]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		hists[series] = h
+	}
+	for i, upper := range p.buckets {
+		if seconds <= upper {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (p *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format
+func (p *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b strings.Builder
+	p.writeSamples(&b, "requests_total", "counter", "Completed API requests.", p.requests)
+	p.writeSamples(&b, "retries_total", "counter", "API request retry attempts.", p.retries)
+	p.writeSamples(&b, "requests_in_flight", "gauge", "API requests currently in flight.", p.inFlight)
+
+	p.writeHistograms(&b, "request_duration_seconds", "API request duration including retries.", p.durations)
+	if len(p.phases) > 0 {
+		p.writeHistograms(&b, "request_phase_seconds", "API request connection phase timings of the last attempt.", p.phases)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (p *PrometheusMetrics) writeHistograms(b *strings.Builder, metric, help string, hists map[string]*histogram) {
+	name := p.name(metric)
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, series := range sortedKeys(hists) {
+		h := hists[series]
+		for i, upper := range p.buckets {
+			le := strconv.FormatFloat(upper, 'g', -1, 64)
+			fmt.Fprintf(b, "%s_bucket{%s,le=%q} %d\n", name, series, le, h.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, series, h.count)
+		fmt.Fprintf(b, "%s_sum{%s} %g\n", name, series, h.sum)
+		fmt.Fprintf(b, "%s_count{%s} %d\n", name, series, h.count)
+	}
+}
+
+func (p *PrometheusMetrics) writeSamples(b *strings.Builder, metric, kind, help string, samples map[string]float64) {
+	name := p.name(metric)
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	for _, l := range sortedKeys(samples) {
+		fmt.Fprintf(b, "%s{%s} %g\n", name, l, samples[l])
+	}
+}
+
+func (p *PrometheusMetrics) name(metric string) string {
+	if p.namespace == "" {
+		return metric
+	}
+	return p.namespace + "_" + metric
+}
+
+// labels renders label pairs as `k1="v1",k2="v2"`
+func labels(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+"="+strconv.Quote(pairs[i+1]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,98 @@
+package yourapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Propagator injects trace context from ctx into outgoing request headers.
+// OpenTelemetry's propagation.TextMapPropagator satisfies it through
+// propagation.HeaderCarrier.
+type Propagator interface {
+	Inject(ctx context.Context, header http.Header)
+}
+
+// TraceContext holds W3C trace context headers
+type TraceContext struct {
+	// TraceParent is the traceparent header, e.g.
+	// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	TraceParent string
+	// TraceState is the optional tracestate header
+	TraceState string
+}
+
+type traceContextKey struct{}
+
+// WithTraceContext returns a context whose requests carry tc's headers
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceContextFromRequest extracts the trace context of an incoming request,
+// so a service can forward it on its own calls to the API
+func TraceContextFromRequest(r *http.Request) TraceContext {
+	return TraceContext{
+		TraceParent: r.Header.Get("traceparent"),
+		TraceState:  r.Header.Get("tracestate"),
+	}
+}
+
+// traceContextPropagator propagates the TraceContext stored in the request
+// context as traceparent/tracestate, and optionally as a B3 single header
+type traceContextPropagator struct {
+	b3 bool
+}
+
+func (p traceContextPropagator) Inject(ctx context.Context, header http.Header) {
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	if !ok || !validTraceParent(tc.TraceParent) {
+		return
+	}
+
+	header.Set("traceparent", tc.TraceParent)
+	if tc.TraceState != "" {
+		header.Set("tracestate", tc.TraceState)
+	}
+	if p.b3 {
+		header.Set("b3", b3FromTraceParent(tc.TraceParent))
+	}
+}
+
+// validTraceParent checks the version-00 layout
+// {version}-{trace-id}-{parent-id}-{flags}
+func validTraceParent(tp string) bool {
+	parts := strings.Split(tp, "-")
+	if len(parts) != 4 {
+		return false
+	}
+	for i, n := range []int{2, 32, 16, 2} {
+		if len(parts[i]) != n || !isLowerHex(parts[i]) {
+			return false
+		}
+	}
+	return parts[1] != strings.Repeat("0", 32) && parts[2] != strings.Repeat("0", 16)
+}
+
+// b3FromTraceParent converts a traceparent into a B3 single header
+// {trace-id}-{span-id}-{sampled}
+func b3FromTraceParent(tp string) string {
+	parts := strings.Split(tp, "-")
+	sampled := "0"
+	// The sampled flag is bit 0 of the hex flags byte, validated by the
+	// caller
+	if flags, err := strconv.ParseUint(parts[3], 16, 8); err == nil && flags&1 == 1 {
+		sampled = "1"
+	}
+	return parts[1] + "-" + parts[2] + "-" + sampled
+}
+
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+package yourapi
+
+import "testing"
+
+func TestB3FromTraceParent(t *testing.T) {
+	const trace, span = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	tests := []struct {
+		flags   string
+		sampled string
+	}{
+		{"00", "0"},
+		{"01", "1"},
+		{"02", "0"},
+		{"03", "1"},
+		{"0a", "0"},
+		{"0b", "1"},
+		{"f0", "0"},
+		{"ff", "1"},
+	}
+	for _, tt := range tests {
+		tp := "00-" + trace + "-" + span + "-" + tt.flags
+		want := trace + "-" + span + "-" + tt.sampled
+		if got := b3FromTraceParent(tp); got != want {
+			t.Errorf("flags %s: got %s, want %s", tt.flags, got, want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package yourapi
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// EncodeQuery encodes the exported fields of a struct, or a pointer to one,
+// as query parameters. A field's `url` tag sets its name and options:
+//
+//	Status  string    `url:"status,omitempty"`  // skipped when empty
+//	Tags    []string  `url:"tag"`               // tag=a&tag=b
+//	IDs     []string  `url:"ids,comma"`         // ids=a,b
+//	Since   time.Time `url:"since,omitempty"`   // RFC 3339
+//	Before  time.Time `url:"before,unix"`       // seconds since the epoch
+//	Private string    `url:"-"`                 // never sent
+//
+// Untagged fields use the field name. Nil pointers and empty slices are
+// skipped, embedded structs are flattened, and types implementing
+// encoding.TextMarshaler encode as their text. A nil v encodes as no
+// parameters.
+func EncodeQuery(v interface{}) (url.Values, error) {
+	values := make(url.Values)
+	if v == nil {
+		return values, nil
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return values, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query parameters must be a struct, got %s", rv.Type())
+	}
+	if err := encodeStruct(values, rv); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// WithQueryParams adds the query parameters encoded from v by EncodeQuery
+// to this request. An encoding error fails the request.
+func WithQueryParams(v interface{}) RequestOption {
+	return func(ro *requestOptions) {
+		values, err := EncodeQuery(v)
+		if err != nil {
+			ro.err = err
+			return
+		}
+		if ro.query == nil {
+			ro.query = make(url.Values)
+		}
+		for k, vs := range values {
+			ro.query[k] = append(ro.query[k], vs...)
+		}
+	}
+}
+
+// queryTag is a parsed `url` struct tag
+type queryTag struct {
+	name      string
+	omitEmpty bool
+	comma     bool
+	unix      bool
+}
+
+func parseQueryTag(field reflect.StructField) (queryTag, bool) {
+	tag, ok := field.Tag.Lookup("url")
+	if tag == "-" {
+		return queryTag{}, false
+	}
+	parts := strings.Split(tag, ",")
+	qt := queryTag{name: parts[0]}
+	if !ok || qt.name == "" {
+		qt.name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty":
+			qt.omitEmpty = true
+		case "comma":
+			qt.comma = true
+		case "unix":
+			qt.unix = true
+		}
+	}
+	return qt, true
+}
+
+func encodeStruct(values url.Values, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+		if field.Anonymous && field.Tag.Get("url") == "" {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+				if err := encodeStruct(values, fv); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		tag, ok := parseQueryTag(field)
+		if !ok {
+			continue
+		}
+		if err := encodeField(values, tag, fv); err != nil {
+			return fmt.Errorf("query parameter %s: %w", tag.name, err)
+		}
+	}
+	return nil
+}
+
+func encodeField(values url.Values, tag queryTag, fv reflect.Value) error {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	if tag.omitEmpty && fv.IsZero() {
+		return nil
+	}
+
+	if (fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8) || fv.Kind() == reflect.Array {
+		items := make([]string, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			item := fv.Index(i)
+			if item.Kind() == reflect.Ptr && item.IsNil() {
+				continue
+			}
+			s, err := formatQueryValue(reflect.Indirect(item), tag)
+			if err != nil {
+				return err
+			}
+			items = append(items, s)
+		}
+		// An empty list has no values to send, with or without omitempty
+		if len(items) == 0 {
+			return nil
+		}
+		if tag.comma {
+			values.Add(tag.name, strings.Join(items, ","))
+			return nil
+		}
+		for _, s := range items {
+			values.Add(tag.name, s)
+		}
+		return nil
+	}
+
+	s, err := formatQueryValue(fv, tag)
+	if err != nil {
+		return err
+	}
+	values.Add(tag.name, s)
+	return nil
+}
+
+// formatQueryValue renders a single value as a query parameter value
+func formatQueryValue(v reflect.Value, tag queryTag) (string, error) {
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if tag.unix {
+			return strconv.FormatInt(t.Unix(), 10), nil
+		}
+		return t.Format(time.RFC3339), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() && reflect.PtrTo(v.Type()).Implements(textMarshalerType) {
+		text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	case reflect.Slice:
+		// []byte
+		return string(v.Bytes()), nil
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
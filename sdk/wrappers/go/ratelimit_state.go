@@ -0,0 +1,75 @@
+package yourapi
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitState is the latest rate limit reported by the server through
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset
+type RateLimitState struct {
+	// Limit is the request limit per window, -1 if not reported
+	Limit int `json:"limit"`
+	// Remaining is the number of requests left in the window, -1 if not
+	// reported
+	Remaining int `json:"remaining"`
+	// Reset is when the window resets, zero if not reported
+	Reset time.Time `json:"reset"`
+	// UpdatedAt is when the headers were last seen; zero means no response
+	// has carried rate limit headers yet
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// rateLimitTracker holds the latest RateLimitState
+type rateLimitTracker struct {
+	mu    sync.RWMutex
+	state RateLimitState
+}
+
+// observe records the rate limit headers of a response, if present
+func (t *rateLimitTracker) observe(h http.Header, now time.Time) {
+	limit, hasLimit := headerInt(h, "X-RateLimit-Limit")
+	remaining, hasRemaining := headerInt(h, "X-RateLimit-Remaining")
+	reset, hasReset := parseRateLimitReset(h.Get("X-RateLimit-Reset"))
+	if !hasLimit && !hasRemaining && !hasReset {
+		return
+	}
+
+	state := RateLimitState{Limit: -1, Remaining: -1, UpdatedAt: now}
+	if hasLimit {
+		state.Limit = limit
+	}
+	if hasRemaining {
+		state.Remaining = remaining
+	}
+	if hasReset {
+		state.Reset = reset
+	}
+
+	t.mu.Lock()
+	t.state = state
+	t.mu.Unlock()
+}
+
+func (t *rateLimitTracker) get() RateLimitState {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.state
+}
+
+func headerInt(h http.Header, name string) (int, bool) {
+	v := h.Get(name)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil
+}
+
+// RateLimitState returns the most recent rate limit headers seen on any
+// response, so applications can throttle before hitting 429s
+func (c *Client) RateLimitState() RateLimitState {
+	return c.rateLimit.get()
+}
@@ -0,0 +1,91 @@
+package yourapi
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RecordedRequest is one request as it was sent on the wire
+type RecordedRequest struct {
+	Time   time.Time
+	Method string
+	URL    string
+	// Header is a copy of the request headers, including credentials
+	Header http.Header
+	// Body is the encoded request body, nil when there was none
+	Body []byte
+	// Attempt is 1 for the first send and counts up across retries
+	Attempt int
+}
+
+// IdempotencyKey returns the request's Idempotency-Key header
+func (r RecordedRequest) IdempotencyKey() string {
+	return r.Header.Get(IdempotencyKeyHeader)
+}
+
+// RequestRecorder keeps a log of every request a client sends, including
+// each retry, for assertions in tests. Set it as ClientOptions.Recorder.
+// It is safe for concurrent use.
+type RequestRecorder struct {
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+// NewRequestRecorder returns an empty recorder
+func NewRequestRecorder() *RequestRecorder {
+	return &RequestRecorder{}
+}
+
+func (r *RequestRecorder) record(req *http.Request, body []byte, attempt int, now time.Time) {
+	rec := RecordedRequest{
+		Time:    now,
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Header:  req.Header.Clone(),
+		Attempt: attempt,
+	}
+	if body != nil {
+		rec.Body = append([]byte(nil), body...)
+	}
+	r.mu.Lock()
+	r.requests = append(r.requests, rec)
+	r.mu.Unlock()
+}
+
+// Requests returns the recorded requests in the order they were sent
+func (r *RequestRecorder) Requests() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedRequest(nil), r.requests...)
+}
+
+// Filter returns the recorded requests for which match returns true
+func (r *RequestRecorder) Filter(match func(RecordedRequest) bool) []RecordedRequest {
+	var out []RecordedRequest
+	for _, req := range r.Requests() {
+		if match(req) {
+			out = append(out, req)
+		}
+	}
+	return out
+}
+
+// ByIdempotencyKey returns the recorded requests sent with key
+func (r *RequestRecorder) ByIdempotencyKey(key string) []RecordedRequest {
+	return r.Filter(func(req RecordedRequest) bool { return req.IdempotencyKey() == key })
+}
+
+// Len returns the number of recorded requests
+func (r *RequestRecorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// Reset clears the log
+func (r *RequestRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
@@ -0,0 +1,99 @@
+package yourapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Redacted replaces sensitive values in log output
+const Redacted = "[REDACTED]"
+
+// defaultRedactHeaders are always redacted from log output
+var defaultRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"X-API-Key",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// redactor masks sensitive headers and JSON body fields before they are
+// logged
+type redactor struct {
+	headers map[string]bool
+	fields  map[string]bool
+}
+
+// newRedactor builds a redactor for the built-in auth headers plus the
+// configured headers and body fields. Matching is case-insensitive.
+func newRedactor(headers, fields []string) *redactor {
+	r := &redactor{
+		headers: make(map[string]bool),
+		fields:  make(map[string]bool),
+	}
+	for _, h := range defaultRedactHeaders {
+		r.headers[canonicalHeader(h)] = true
+	}
+	for _, h := range headers {
+		r.headers[canonicalHeader(h)] = true
+	}
+	for _, f := range fields {
+		r.fields[strings.ToLower(f)] = true
+	}
+	return r
+}
+
+// Header returns a copy of h with sensitive values masked
+func (r *redactor) Header(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		if r.headers[canonicalHeader(k)] {
+			out[k] = []string{Redacted}
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// canonicalHeader normalizes a header name for lookups
+func canonicalHeader(name string) string {
+	return http.CanonicalHeaderKey(name)
+}
+
+// Body returns a copy of a JSON body with sensitive fields masked at any
+// depth. Bodies that are not JSON are returned unchanged.
+func (r *redactor) Body(body []byte) []byte {
+	if len(r.fields) == 0 || len(body) == 0 {
+		return body
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	redacted, err := json.Marshal(r.value(v))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func (r *redactor) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if r.fields[strings.ToLower(k)] {
+				v[k] = Redacted
+			} else {
+				v[k] = r.value(child)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = r.value(child)
+		}
+	}
+	return v
+}
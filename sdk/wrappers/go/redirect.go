@@ -0,0 +1,108 @@
+package yourapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultMaxRedirects is the default maximum number of redirects followed
+const DefaultMaxRedirects = 10
+
+// ErrNotModified is returned for 304 responses to conditional requests; the
+// result is left untouched so cached data can be reused
+var ErrNotModified = errors.New("not modified")
+
+// RedirectPolicy controls how the client handles 3xx responses
+type RedirectPolicy struct {
+	// Forbid disables following redirects; 3xx responses are returned as a
+	// *RedirectError
+	Forbid bool
+	// MaxRedirects is the maximum number of hops followed (default: 10)
+	MaxRedirects int
+	// KeepAuthOnCrossOrigin keeps authentication headers when a redirect
+	// points at a different origin (default: stripped)
+	KeepAuthOnCrossOrigin bool
+}
+
+// RedirectError is returned for 3xx responses that were not followed, either
+// because redirects are forbidden or the hop limit was reached
+type RedirectError struct {
+	Status   int
+	Location string
+}
+
+func (e *RedirectError) Error() string {
+	if e.Location == "" {
+		return fmt.Sprintf("[%d] redirect not followed", e.Status)
+	}
+	return fmt.Sprintf("[%d] redirect to %s not followed", e.Status, e.Location)
+}
+
+// authHeaders are removed from requests redirected to another origin
+var authHeaders = []string{"Authorization", "X-API-Key"}
+
+// checkRedirect returns an http.Client CheckRedirect function implementing
+// the policy. Redirects that are not followed hand the 3xx response back to
+// the caller instead of failing the round trip, so they are never retried.
+func (p RedirectPolicy) checkRedirect() func(req *http.Request, via []*http.Request) error {
+	maxRedirects := p.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+
+	return func(req *http.Request, via []*http.Request) error {
+		if p.Forbid || len(via) > maxRedirects {
+			return http.ErrUseLastResponse
+		}
+
+		if sameOrigin(req, via[0]) {
+			return nil
+		}
+		for _, h := range authHeaders {
+			// net/http drops Authorization itself on most cross-origin hops,
+			// so kept headers are restored from the original request
+			if v := via[0].Header.Values(h); len(v) > 0 && p.KeepAuthOnCrossOrigin {
+				req.Header[http.CanonicalHeaderKey(h)] = v
+			} else {
+				req.Header.Del(h)
+			}
+		}
+		return nil
+	}
+}
+
+// sameOrigin reports whether two requests share scheme, host and port, an
+// omitted port standing for the scheme's default
+func sameOrigin(a, b *http.Request) bool {
+	return strings.EqualFold(a.URL.Scheme, b.URL.Scheme) &&
+		strings.EqualFold(a.URL.Hostname(), b.URL.Hostname()) &&
+		originPort(a.URL) == originPort(b.URL)
+}
+
+// originPort returns the port of u, or its scheme's default port
+func originPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		return "443"
+	case "http":
+		return "80"
+	}
+	return ""
+}
+
+// redirectError builds the error returned for an unfollowed 3xx response
+func redirectError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotModified {
+		return ErrNotModified
+	}
+	return &RedirectError{
+		Status:   resp.StatusCode,
+		Location: resp.Header.Get("Location"),
+	}
+}
@@ -0,0 +1,98 @@
+package yourapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://api.test/a", "https://api.test/b", true},
+		{"https://api.test/a", "https://api.test:443/b", true},
+		{"http://api.test/a", "http://api.test:80/b", true},
+		{"https://API.test/a", "HTTPS://api.test/b", true},
+		{"https://api.test/a", "http://api.test/b", false},
+		{"https://api.test/a", "https://api.test:8443/b", false},
+		{"http://api.test/a", "http://api.test:443/b", false},
+		{"https://api.test/a", "https://eu.api.test/b", false},
+	}
+	for _, tt := range tests {
+		a := &http.Request{URL: mustParseURL(t, tt.a)}
+		b := &http.Request{URL: mustParseURL(t, tt.b)}
+		if got := sameOrigin(a, b); got != tt.want {
+			t.Errorf("sameOrigin(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestRedirectAuthHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		keep     bool
+		sameHost bool
+		wantAuth bool
+		bearer   string
+		apiKey   string
+	}{
+		{name: "cross-origin bearer stripped", bearer: "tok"},
+		{name: "cross-origin API key stripped", apiKey: "key"},
+		{name: "cross-origin bearer kept", keep: true, wantAuth: true, bearer: "tok"},
+		{name: "cross-origin API key kept", keep: true, wantAuth: true, apiKey: "key"},
+		{name: "same origin", sameHost: true, wantAuth: true, bearer: "tok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+				w.Write([]byte(`{}`))
+			}))
+			defer target.Close()
+			origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/moved" {
+					got = r.Header.Clone()
+					w.Write([]byte(`{}`))
+					return
+				}
+				location := target.URL + "/moved"
+				if tt.sameHost {
+					location = "/moved"
+				}
+				http.Redirect(w, r, location, http.StatusFound)
+			}))
+			defer origin.Close()
+
+			client, err := NewClient(ClientOptions{
+				BaseURL:     origin.URL,
+				BearerToken: tt.bearer,
+				APIKey:      tt.apiKey,
+				Redirects:   RedirectPolicy{KeepAuthOnCrossOrigin: tt.keep},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := client.Get(context.Background(), "/start", nil); err != nil {
+				t.Fatal(err)
+			}
+			hasAuth := got.Get("Authorization") != "" || got.Get("X-API-Key") != ""
+			if hasAuth != tt.wantAuth {
+				t.Errorf("auth headers after redirect: %v, want %v (%v)", hasAuth, tt.wantAuth, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package yourapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RedisEvaler runs a Lua script on Redis. It is the only Redis operation
+// RedisRateLimiter needs, so the SDK stays free of a Redis client
+// dependency. With github.com/redis/go-redis:
+//
+//	evaler := yourapi.RedisEvalFunc(func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//		return rdb.Eval(ctx, script, keys, args...).Result()
+//	})
+type RedisEvaler interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// RedisEvalFunc adapts a function to RedisEvaler
+type RedisEvalFunc func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+
+// Eval implements RedisEvaler
+func (f RedisEvalFunc) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return f(ctx, script, keys, args...)
+}
+
+// gcraScript implements the generic cell rate algorithm, equivalent to a
+// token bucket, against Redis server time. It returns 0 when the request is
+// allowed or the wait in microseconds.
+const gcraScript = `
+local emission = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tat = tonumber(redis.call('GET', KEYS[1])) or now
+if tat < now then tat = now end
+local next_tat = tat + emission
+local allow_at = next_tat - emission * burst
+if allow_at > now then return allow_at - now end
+redis.call('SET', KEYS[1], string.format('%.0f', next_tat), 'PX', math.ceil((next_tat - now) / 1000) + 1)
+return 0
+`
+
+// RedisRateLimiter is a RateLimiter whose buckets live in Redis, so every
+// process using the same Prefix shares one quota. It uses Redis server time,
+// making it insensitive to clock skew between workers.
+type RedisRateLimiter struct {
+	redis  RedisEvaler
+	prefix string
+	rules  limitRules
+}
+
+// NewRedisRateLimiter creates a limiter storing its state under keys
+// starting with prefix, applying limit to all requests except those
+// matching one of endpoints
+func NewRedisRateLimiter(redis RedisEvaler, prefix string, limit RateLimit, endpoints ...EndpointRateLimit) *RedisRateLimiter {
+	return &RedisRateLimiter{redis: redis, prefix: prefix, rules: newLimitRules(limit, endpoints)}
+}
+
+// Allow implements RateLimiter
+func (l *RedisRateLimiter) Allow(ctx context.Context, method, pathTemplate string) (time.Duration, error) {
+	key, limit := l.rules.match(method, pathTemplate)
+	if limit.RPS <= 0 {
+		return 0, nil
+	}
+	emission := int64(1e6 / limit.RPS)
+
+	res, err := l.redis.Eval(ctx, gcraScript, []string{l.prefix + key}, emission, limit.burst())
+	if err != nil {
+		return 0, err
+	}
+	wait, ok := res.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected redis reply %T", res)
+	}
+	return time.Duration(wait) * time.Microsecond, nil
+}
@@ -0,0 +1,237 @@
+package yourapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultRateLimitPause is how long WithPauseOnRateLimit pauses when a 429
+// carries no Retry-After or X-RateLimit-Reset hint
+const defaultRateLimitPause = 5 * time.Second
+
+// RequestOption customizes a single request
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the per-request settings applied by RequestOptions
+type requestOptions struct {
+	debug            bool
+	failFast         bool
+	pauseOnRateLimit bool
+	maxPause         time.Duration
+	priority         Priority
+	idempotencyKey   string
+	metadata         *ResponseMetadata
+	headers          map[string]string
+	query            url.Values
+	timeout          time.Duration
+	attemptTimeout   time.Duration
+	retry            *RetryPolicy
+	pathParams       PathParams
+	baseURL          string
+	// rawBaseURL sends baseURL without the client's version and scope
+	// prefixes, as Connect procedures are
+	rawBaseURL bool
+	// err is set by an option that could not be applied, failing the request
+	err error
+}
+
+func newRequestOptions(opts []RequestOption) requestOptions {
+	var ro requestOptions
+	for _, opt := range opts {
+		opt(&ro)
+	}
+	return ro
+}
+
+// WithDebug enables verbose logging, including bodies, for this request
+// only. Without a custom Logger the output goes to DebugWriter; with one,
+// the request's debug events are logged at info level so they pass the
+// level filters typically used in production.
+func WithDebug() RequestOption {
+	return func(ro *requestOptions) {
+		ro.debug = true
+	}
+}
+
+// WithFailFast makes the request return a *ThrottledLocallyError instead of
+// waiting when the client's rate limiter, adaptive throttling or concurrency
+// limit would delay it
+func WithFailFast() RequestOption {
+	return func(ro *requestOptions) {
+		ro.failFast = true
+	}
+}
+
+// WithPauseOnRateLimit makes PaginateCursor and GetAllCursor pause when a
+// page fails with ErrRateLimited after retries, waiting for the server's
+// Retry-After or reset hint and then resuming from the same cursor instead
+// of failing the walk. maxPause bounds the total time spent paused; 0 means
+// only ctx limits it. Other requests ignore this option.
+func WithPauseOnRateLimit(maxPause time.Duration) RequestOption {
+	return func(ro *requestOptions) {
+		ro.pauseOnRateLimit = true
+		ro.maxPause = maxPause
+	}
+}
+
+// WithPriority sets the request's priority. When the rate limiter or
+// MaxConcurrentRequests makes requests wait, higher-priority requests are
+// admitted first.
+func WithPriority(p Priority) RequestOption {
+	return func(ro *requestOptions) {
+		ro.priority = p
+	}
+}
+
+// WithIdempotencyKey sends key as the request's Idempotency-Key, reused
+// across its retries. The API honors it on POST, PUT, PATCH and DELETE.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(ro *requestOptions) {
+		ro.idempotencyKey = key
+	}
+}
+
+// WithMetadata fills md with the request's outcome, including the
+// idempotency key used, once the call returns, so callers can persist it for
+// reconciliation and support investigations
+func WithMetadata(md *ResponseMetadata) RequestOption {
+	return func(ro *requestOptions) {
+		ro.metadata = md
+	}
+}
+
+// WithHeader sets a header on this request, overriding the client's
+// default and custom headers of the same name
+func WithHeader(key, value string) RequestOption {
+	return func(ro *requestOptions) {
+		if ro.headers == nil {
+			ro.headers = make(map[string]string)
+		}
+		ro.headers[http.CanonicalHeaderKey(key)] = value
+	}
+}
+
+// WithQuery adds a query parameter to this request. Calling it again with
+// the same key adds another value.
+func WithQuery(key, value string) RequestOption {
+	return func(ro *requestOptions) {
+		if ro.query == nil {
+			ro.query = make(url.Values)
+		}
+		ro.query.Add(key, value)
+	}
+}
+
+// WithTimeout bounds this request, including its retries and backoff, to d.
+// It applies on top of ctx's deadline and the client's Timeout, which still
+// limits each attempt; raise that with WithAttemptTimeout.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(ro *requestOptions) {
+		ro.timeout = d
+	}
+}
+
+// WithAttemptTimeout replaces the client's Timeout for each attempt of this
+// request, so a slow call such as report generation can outlast it while
+// other calls keep failing fast. NoTimeout lifts the limit.
+func WithAttemptTimeout(d time.Duration) RequestOption {
+	return func(ro *requestOptions) {
+		ro.attemptTimeout = d
+	}
+}
+
+// WithBaseURL sends this request to baseURL instead of the client's
+// BaseURL, e.g. to reach a regional endpoint or a dedicated host for bulk
+// operations. The APIVersionInPath and ScopePath prefixes still apply.
+func WithBaseURL(baseURL string) RequestOption {
+	return func(ro *requestOptions) {
+		normalized, err := normalizeBaseURL(baseURL)
+		if err != nil {
+			ro.err = err
+			return
+		}
+		ro.baseURL = normalized
+	}
+}
+
+// WithRetryPolicy overrides the client's retry settings for this request
+func WithRetryPolicy(p RetryPolicy) RequestOption {
+	return func(ro *requestOptions) {
+		ro.retry = &p
+	}
+}
+
+// ResolvedRequest is what a request's options change in the request
+// sent, for API implementations other than Client such as fakes
+type ResolvedRequest struct {
+	// Path is the path with the WithPathParams parameters expanded and the
+	// WithQuery and WithQueryParams parameters appended
+	Path string
+	// Headers holds the WithHeader headers, and the WithIdempotencyKey key
+	// under IdempotencyKeyHeader
+	Headers map[string]string
+	// IdempotencyKey is the WithIdempotencyKey key, "" without one
+	IdempotencyKey string
+	// Timeout is the WithTimeout bound, 0 without one
+	Timeout time.Duration
+}
+
+// ResolveRequest applies opts to a request for path as Client does. It
+// fails when an option could not be applied or the path parameters do not
+// fit path.
+func ResolveRequest(path string, opts ...RequestOption) (ResolvedRequest, error) {
+	ro := newRequestOptions(opts)
+	if ro.err != nil {
+		return ResolvedRequest{}, ro.err
+	}
+	if ro.pathParams != nil {
+		expanded, err := ExpandPath(path, ro.pathParams)
+		if err != nil {
+			return ResolvedRequest{}, err
+		}
+		path = expanded
+	}
+	headers := ro.withHeaders(nil)
+	if ro.idempotencyKey != "" {
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[IdempotencyKeyHeader] = ro.idempotencyKey
+	}
+	return ResolvedRequest{
+		Path:           ro.withQuery(path),
+		Headers:        headers,
+		IdempotencyKey: ro.idempotencyKey,
+		Timeout:        ro.timeout,
+	}, nil
+}
+
+// withQuery appends the WithQuery parameters to path
+func (ro requestOptions) withQuery(path string) string {
+	if len(ro.query) == 0 {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + ro.query.Encode()
+}
+
+// withHeaders returns headers with the WithHeader values added, leaving the
+// caller's map untouched
+func (ro requestOptions) withHeaders(headers map[string]string) map[string]string {
+	if len(ro.headers) == 0 {
+		return headers
+	}
+	merged := make(map[string]string, len(headers)+len(ro.headers))
+	for k, v := range headers {
+		merged[k] = v
+	}
+	for k, v := range ro.headers {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,108 @@
+package yourapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+	"github.com/devdraft/devdraft-sdk-go/yourapitest"
+)
+
+func TestRetrySchedule(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	unavailable := func(header http.Header) yourapitest.StubResponse {
+		return yourapitest.StubResponse{Status: http.StatusServiceUnavailable, Header: header, Body: `{"code":"unavailable"}`}
+	}
+	retryAfter := func(v string) http.Header { return http.Header{"Retry-After": {v}} }
+
+	tests := []struct {
+		name       string
+		backoff    yourapi.RetryBackoff
+		maxRetries int
+		responses  []yourapitest.StubResponse
+		want       []time.Duration
+	}{
+		{
+			name:       "default exponential",
+			maxRetries: 3,
+			responses:  []yourapitest.StubResponse{unavailable(nil), unavailable(nil), unavailable(nil)},
+			want:       []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:       "capped exponential",
+			backoff:    yourapi.RetryBackoff{Base: 500 * time.Millisecond, Max: time.Second},
+			maxRetries: 4,
+			responses:  []yourapitest.StubResponse{unavailable(nil), unavailable(nil), unavailable(nil), unavailable(nil)},
+			want:       []time.Duration{500 * time.Millisecond, time.Second, time.Second, time.Second},
+		},
+		{
+			name:       "Retry-After seconds",
+			maxRetries: 3,
+			responses:  []yourapitest.StubResponse{unavailable(retryAfter("7")), unavailable(nil)},
+			want:       []time.Duration{7 * time.Second, 2 * time.Second},
+		},
+		{
+			name:       "Retry-After date",
+			maxRetries: 3,
+			responses:  []yourapitest.StubResponse{unavailable(retryAfter(start.Add(90 * time.Second).Format(http.TimeFormat)))},
+			want:       []time.Duration{90 * time.Second},
+		},
+		{
+			name:       "Retry-After date in the past",
+			maxRetries: 3,
+			responses:  []yourapitest.StubResponse{unavailable(retryAfter(start.Add(-time.Minute).Format(http.TimeFormat)))},
+			want:       []time.Duration{time.Second},
+		},
+		{
+			name:       "Retry-After capped",
+			backoff:    yourapi.RetryBackoff{MaxRetryAfter: 10 * time.Second},
+			maxRetries: 3,
+			responses:  []yourapitest.StubResponse{unavailable(retryAfter("60"))},
+			want:       []time.Duration{10 * time.Second},
+		},
+		{
+			name:       "429 Retry-After",
+			maxRetries: 3,
+			responses: []yourapitest.StubResponse{
+				{Status: http.StatusTooManyRequests, Header: retryAfter("3"), Body: `{"code":"rate_limited"}`},
+			},
+			want: []time.Duration{3 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := yourapitest.NewFakeClock(start)
+			responses := append(tt.responses, yourapitest.StubResponse{JSON: map[string]string{"id": "cus_1"}})
+			stub := yourapitest.NewStubTransport().On(http.MethodGet, "/customers/cus_1", responses...)
+			opts := stub.ClientOptions()
+			opts.Clock = clock
+			opts.MaxRetries = tt.maxRetries
+			opts.RetryBackoff = tt.backoff
+			client, err := yourapi.NewClient(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			errc := make(chan error, 1)
+			go func() { errc <- client.Get(context.Background(), "/customers/cus_1", nil) }()
+			for range tt.want {
+				clock.BlockUntil(1)
+				clock.AdvanceToNext()
+			}
+			if err := <-errc; err != nil {
+				t.Fatal(err)
+			}
+			waits := clock.Waits()
+			if len(waits) != len(tt.want) {
+				t.Fatalf("waited %v, want %v", waits, tt.want)
+			}
+			for i := range waits {
+				if waits[i] != tt.want[i] {
+					t.Errorf("wait %d: %v, want %v", i, waits[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package yourapi
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Default names of the headers scoping requests to a tenant or organization
+const (
+	DefaultTenantHeader       = "X-Tenant-ID"
+	DefaultOrganizationHeader = "X-Organization-ID"
+)
+
+// Scoping selects how ClientOptions.Tenant and Organization reach the API
+type Scoping int
+
+const (
+	// ScopeHeader sends them in the TenantHeader and OrganizationHeader
+	// headers
+	ScopeHeader Scoping = iota
+	// ScopePath prefixes request paths with /organizations/{organization}
+	// and /tenants/{tenant}, in that order
+	ScopePath
+)
+
+// WithTenant scopes every request to the tenant id, see
+// ClientOptions.Tenant
+func WithTenant(id string) Option {
+	return func(o *ClientOptions) {
+		o.Tenant = id
+	}
+}
+
+// WithOrganization scopes every request to the organization id, see
+// ClientOptions.Organization
+func WithOrganization(id string) Option {
+	return func(o *ClientOptions) {
+		o.Organization = id
+	}
+}
+
+// scope applies the Tenant and Organization of o to baseURL and the custom
+// headers, returning those every request uses
+func (o *ClientOptions) scope(baseURL string) (string, map[string]string, error) {
+	if o.Tenant == "" && o.Organization == "" {
+		return baseURL, o.CustomHeaders, nil
+	}
+	switch o.Scoping {
+	case ScopeHeader:
+		headers := make(map[string]string, len(o.CustomHeaders)+2)
+		for k, v := range o.CustomHeaders {
+			headers[k] = v
+		}
+		if o.Organization != "" {
+			headers[o.OrganizationHeader] = o.Organization
+		}
+		if o.Tenant != "" {
+			headers[o.TenantHeader] = o.Tenant
+		}
+		return baseURL, headers, nil
+	case ScopePath:
+		if o.Organization != "" {
+			baseURL += "/organizations/" + url.PathEscape(o.Organization)
+		}
+		if o.Tenant != "" {
+			baseURL += "/tenants/" + url.PathEscape(o.Tenant)
+		}
+		return baseURL, o.CustomHeaders, nil
+	}
+	return "", nil, fmt.Errorf("unknown Scoping %d", o.Scoping)
+}
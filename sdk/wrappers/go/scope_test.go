@@ -0,0 +1,114 @@
+package yourapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// urlRecorder answers every request with an empty object, recording the
+// URLs requested
+type urlRecorder struct {
+	fuzzTransport
+	urls []string
+}
+
+func (r *urlRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.urls = append(r.urls, req.URL.String())
+	return r.fuzzTransport.RoundTrip(req)
+}
+
+func TestWithBaseURLKeepsPrefixes(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ClientOptions
+		ro   []RequestOption
+		want string
+	}{
+		{
+			name: "client base URL",
+			opts: ClientOptions{Organization: "org_42", Tenant: "acme", Scoping: ScopePath},
+			want: "https://api.test/v1/organizations/org_42/tenants/acme/customers",
+		},
+		{
+			name: "scoped",
+			opts: ClientOptions{Organization: "org_42", Tenant: "acme", Scoping: ScopePath},
+			ro:   []RequestOption{WithBaseURL("https://eu.test/v1")},
+			want: "https://eu.test/v1/organizations/org_42/tenants/acme/customers",
+		},
+		{
+			name: "versioned and scoped",
+			opts: ClientOptions{APIVersion: "2024-06-01", APIVersionInPath: true, Tenant: "acme", Scoping: ScopePath},
+			ro:   []RequestOption{WithBaseURL("https://eu.test")},
+			want: "https://eu.test/2024-06-01/tenants/acme/customers",
+		},
+		{
+			name: "header scoping",
+			opts: ClientOptions{Tenant: "acme"},
+			ro:   []RequestOption{WithBaseURL("https://eu.test/v1")},
+			want: "https://eu.test/v1/customers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &urlRecorder{fuzzTransport: fuzzTransport{status: 200, body: []byte(`{}`)}}
+			opts := tt.opts
+			opts.BaseURL = "https://api.test/v1"
+			opts.HTTPClient = &http.Client{Transport: rt}
+			client, err := NewClient(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := client.Get(context.Background(), "/customers", nil, tt.ro...); err != nil {
+				t.Fatal(err)
+			}
+			if rt.urls[0] != tt.want {
+				t.Errorf("requested %s, want %s", rt.urls[0], tt.want)
+			}
+		})
+	}
+}
+
+// headerRecorder answers every request with an empty object, recording the
+// headers sent
+type headerRecorder struct {
+	fuzzTransport
+	headers []http.Header
+}
+
+func (r *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.headers = append(r.headers, req.Header.Clone())
+	return r.fuzzTransport.RoundTrip(req)
+}
+
+func TestWithHeaderOverridesClientHeaders(t *testing.T) {
+	rt := &headerRecorder{fuzzTransport: fuzzTransport{status: 200, body: []byte(`{}`)}}
+	client, err := NewClient(ClientOptions{
+		BaseURL:       "https://api.test/v1",
+		APIKey:        "key_client",
+		Tenant:        "acme",
+		CustomHeaders: map[string]string{"X-TRACE-SOURCE": "client"},
+		HTTPClient:    &http.Client{Transport: rt},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Map iteration made the winner random when spellings differed, so
+	// repeat the request
+	for i := 0; i < 20; i++ {
+		err := client.Get(context.Background(), "/customers", nil,
+			WithHeader("x-tenant-id", "globex"),
+			WithHeader("X-API-KEY", "key_request"),
+			WithHeader("x-trace-source", "request"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, h := range rt.headers {
+		for _, name := range []string{"X-Tenant-ID", "X-API-Key", "X-Trace-Source"} {
+			if values := h.Values(name); len(values) != 1 || values[0] == "acme" || values[0] == "key_client" || values[0] == "client" {
+				t.Fatalf("%s sent as %q, want the request's value", name, values)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Code generated by saligen-go. DO NOT EDIT.
+
+package yourapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/devdraft/devdraft-sdk-go/models"
+	"github.com/devdraft/devdraft-sdk-go/validate"
+)
+
+// Services holds the API's resource services, one per OpenAPI tag
+type Services struct {
+	Customers *CustomersService
+}
+
+func newServices(c *Client) Services {
+	return Services{
+		Customers: &CustomersService{client: c},
+	}
+}
+
+// CustomersService provides the customers operations
+//
+// Customer management operations
+type CustomersService struct {
+	client *Client
+}
+
+// CustomersListParams holds the parameters of CustomersService.List
+type CustomersListParams struct {
+	// Limit Maximum number of items to return
+	Limit *int64 `url:"limit,omitempty"`
+	// Cursor Cursor for pagination
+	Cursor *string `url:"cursor,omitempty"`
+	// Email Filter by email address
+	Email *string `url:"email,omitempty"`
+}
+
+// Validate checks p against the constraints in the API spec
+func (p CustomersListParams) Validate() error {
+	var errs validate.Errors
+	if p.Limit != nil {
+		errs.Minimum("limit", float64(*p.Limit), 1, false)
+		errs.Maximum("limit", float64(*p.Limit), 100, false)
+	}
+	if p.Email != nil {
+		errs.Format("email", *p.Email, "email")
+	}
+	return errs.Err()
+}
+
+// List calls GET /customers
+//
+// Retrieve a paginated list of customers
+func (s *CustomersService) List(ctx context.Context, params *CustomersListParams, opts ...RequestOption) (*models.CustomerListResponse, error) {
+	path := "/customers"
+	if err := s.client.validateRequest(params); err != nil {
+		return nil, err
+	}
+	q, err := EncodeQuery(params)
+	if err != nil {
+		return nil, err
+	}
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	var result models.CustomerListResponse
+	if err := s.client.send(ctx, http.MethodGet, path, nil, nil, &result, newRequestOptions(opts)); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListIter iterates over every item List returns, fetching
+// pages as needed. params is not modified.
+func (s *CustomersService) ListIter(ctx context.Context, params *CustomersListParams, opts ...RequestOption) *Iterator[models.Customer] {
+	var p CustomersListParams
+	if params != nil {
+		p = *params
+	}
+	return NewIterator(ctx, s.client, func(ctx context.Context, next string) (Page[models.Customer], error) {
+		if next != "" {
+			p.Cursor = &next
+		}
+		resp, err := s.List(ctx, &p, opts...)
+		if err != nil {
+			return Page[models.Customer]{}, err
+		}
+		return Page[models.Customer]{Items: resp.Items, Next: resp.NextCursor.Or(""), HasMore: resp.HasMore}, nil
+	}, opts...)
+}
+
+// Create calls POST /customers
+//
+// Create a new customer
+func (s *CustomersService) Create(ctx context.Context, body models.CustomerCreateRequest, opts ...RequestOption) (*models.Customer, error) {
+	path := "/customers"
+	var result models.Customer
+	if err := s.client.send(ctx, http.MethodPost, path, body, nil, &result, newRequestOptions(opts)); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Get calls GET /customers/{customerId}
+//
+// Retrieve a single customer by ID
+func (s *CustomersService) Get(ctx context.Context, customerID string, opts ...RequestOption) (*models.Customer, error) {
+	path := "/customers/{customerId}"
+	var result models.Customer
+	if err := s.client.send(ctx, http.MethodGet, path, nil, nil, &result, pathOptions(opts, PathParams{"customerId": customerID})); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Delete calls DELETE /customers/{customerId}
+//
+// Delete a customer
+func (s *CustomersService) Delete(ctx context.Context, customerID string, opts ...RequestOption) error {
+	path := "/customers/{customerId}"
+	return s.client.send(ctx, http.MethodDelete, path, nil, nil, nil, pathOptions(opts, PathParams{"customerId": customerID}))
+}
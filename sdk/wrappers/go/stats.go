@@ -0,0 +1,129 @@
+package yourapi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// statsWindow is the number of recent samples kept per endpoint for
+// percentile calculations
+const statsWindow = 512
+
+// EndpointStats summarizes the latency of one endpoint
+type EndpointStats struct {
+	Method       string
+	PathTemplate string
+	// Count and Errors are totals since the client was created
+	Count  int64
+	Errors int64
+	// Latency percentiles over the most recent requests (up to 512),
+	// including retries and backoff
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+	Mean time.Duration
+}
+
+type endpointKey struct {
+	method   string
+	template string
+}
+
+type endpointSamples struct {
+	count   int64
+	errors  int64
+	samples []time.Duration
+	next    int
+}
+
+// latencyStats tracks per-endpoint latencies in fixed-size rings
+type latencyStats struct {
+	mu        sync.Mutex
+	endpoints map[endpointKey]*endpointSamples
+}
+
+func newLatencyStats() *latencyStats {
+	return &latencyStats{endpoints: make(map[endpointKey]*endpointSamples)}
+}
+
+func (s *latencyStats) record(method, template string, d time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := endpointKey{method, template}
+	e, ok := s.endpoints[key]
+	if !ok {
+		e = &endpointSamples{samples: make([]time.Duration, 0, statsWindow)}
+		s.endpoints[key] = e
+	}
+
+	e.count++
+	if failed {
+		e.errors++
+	}
+	if len(e.samples) < statsWindow {
+		e.samples = append(e.samples, d)
+	} else {
+		e.samples[e.next] = d
+		e.next = (e.next + 1) % statsWindow
+	}
+}
+
+func (s *latencyStats) snapshot() []EndpointStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]EndpointStats, 0, len(s.endpoints))
+	for key, e := range s.endpoints {
+		sorted := append([]time.Duration(nil), e.samples...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		var total time.Duration
+		for _, d := range sorted {
+			total += d
+		}
+
+		st := EndpointStats{
+			Method:       key.method,
+			PathTemplate: key.template,
+			Count:        e.count,
+			Errors:       e.errors,
+		}
+		if n := len(sorted); n > 0 {
+			st.P50 = percentile(sorted, 0.50)
+			st.P90 = percentile(sorted, 0.90)
+			st.P99 = percentile(sorted, 0.99)
+			st.Max = sorted[n-1]
+			st.Mean = total / time.Duration(n)
+		}
+		out = append(out, st)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PathTemplate != out[j].PathTemplate {
+			return out[i].PathTemplate < out[j].PathTemplate
+		}
+		return out[i].Method < out[j].Method
+	})
+	return out
+}
+
+// percentile returns the nearest-rank percentile of sorted samples
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// Stats returns latency statistics per method and path template, sorted by
+// path, so applications can spot slow endpoints without external tooling
+func (c *Client) Stats() []EndpointStats {
+	return c.stats.snapshot()
+}
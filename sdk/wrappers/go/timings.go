@@ -0,0 +1,98 @@
+package yourapi
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ConnTimings breaks down where the last attempt of a request spent its
+// time. Phases skipped because a connection was reused are zero.
+type ConnTimings struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// TTFB is the time from the start of the attempt to the first response byte
+	TTFB time.Duration
+	// Server is the time from the request being written to the first
+	// response byte, approximating server processing time
+	Server time.Duration
+	// Reused reports whether an idle connection was reused
+	Reused bool
+}
+
+// timingTrace collects ConnTimings for one attempt
+type timingTrace struct {
+	mu                     sync.Mutex
+	start                  time.Time
+	dnsStart, connStart    time.Time
+	tlsStart, wroteRequest time.Time
+	timings                ConnTimings
+}
+
+// withTimings attaches an httptrace.ClientTrace recording connection-level
+// timings for the attempt into ci
+func (c *Client) withTimings(ctx context.Context, ci *callInfo) context.Context {
+	tt := &timingTrace{start: time.Now()}
+	ci.timings = &tt.timings
+	ci.timingsMu = &tt.mu
+	since := func(t time.Time) time.Duration {
+		if t.IsZero() {
+			return 0
+		}
+		return time.Since(t)
+	}
+	record := func(fn func()) {
+		tt.mu.Lock()
+		fn()
+		tt.mu.Unlock()
+	}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { record(func() { tt.dnsStart = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { record(func() { tt.timings.DNS = since(tt.dnsStart) }) },
+		ConnectStart: func(string, string) {
+			record(func() {
+				if tt.connStart.IsZero() {
+					tt.connStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			record(func() {
+				if err == nil && tt.timings.Connect == 0 {
+					tt.timings.Connect = since(tt.connStart)
+				}
+			})
+		},
+		TLSHandshakeStart: func() { record(func() { tt.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			record(func() { tt.timings.TLS = since(tt.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			record(func() { tt.timings.Reused = info.Reused })
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			record(func() { tt.wroteRequest = time.Now() })
+		},
+		GotFirstResponseByte: func() {
+			record(func() {
+				tt.timings.TTFB = since(tt.start)
+				tt.timings.Server = since(tt.wroteRequest)
+			})
+		},
+	})
+}
+
+// connTimings returns a copy of the timings of the call's last attempt, or
+// nil when timing collection is disabled
+func (ci *callInfo) connTimings() *ConnTimings {
+	if ci.timings == nil {
+		return nil
+	}
+	ci.timingsMu.Lock()
+	defer ci.timingsMu.Unlock()
+	t := *ci.timings
+	return &t
+}
@@ -0,0 +1,69 @@
+package yourapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSOptions configures how the client verifies the API's TLS certificate,
+// for private deployments behind an internal certificate authority
+type TLSOptions struct {
+	// RootCAsFile is a PEM file of the CA certificates to trust instead of
+	// the system's (optional)
+	RootCAsFile string
+	// RootCAs are the CA certificates to trust instead of the system's
+	// (optional)
+	RootCAs *x509.CertPool
+	// MinVersion is the lowest TLS version accepted, e.g. tls.VersionTLS13
+	// (default: TLS 1.2)
+	MinVersion uint16
+	// ServerName is the name the certificate must be valid for, when it
+	// differs from the base URL's host (optional)
+	ServerName string
+	// InsecureSkipVerify accepts any certificate the server presents.
+	// INSECURE: anyone on the network path can then read and alter the
+	// traffic, API keys included. Use it only against local test servers;
+	// the client logs a warning when it is set.
+	InsecureSkipVerify bool
+}
+
+// config returns the tls.Config of t, or nil when t is empty
+func (t TLSOptions) config() (*tls.Config, error) {
+	if t == (TLSOptions{}) {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		RootCAs:            t.RootCAs,
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	switch t.MinVersion {
+	case 0:
+	case tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13:
+		cfg.MinVersion = t.MinVersion
+	default:
+		return nil, fmt.Errorf("TLS.MinVersion: unknown TLS version %#04x", t.MinVersion)
+	}
+	if t.InsecureSkipVerify && (t.RootCAs != nil || t.RootCAsFile != "") {
+		return nil, errors.New("TLS.InsecureSkipVerify cannot be combined with RootCAs or RootCAsFile, which it would ignore")
+	}
+	if t.RootCAsFile != "" {
+		if t.RootCAs != nil {
+			return nil, errors.New("TLS.RootCAs and TLS.RootCAsFile cannot both be set")
+		}
+		pem, err := os.ReadFile(t.RootCAsFile)
+		if err != nil {
+			return nil, fmt.Errorf("TLS.RootCAsFile: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("TLS.RootCAsFile: no PEM certificates in %s", t.RootCAsFile)
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
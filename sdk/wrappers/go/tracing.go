@@ -0,0 +1,120 @@
+package yourapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TracerName is the instrumentation name passed to TracerProvider.Tracer
+const TracerName = "github.com/devdraft/devdraft-sdk-go"
+
+// TracerProvider creates tracers. It mirrors the OpenTelemetry API so an
+// OTel TracerProvider can be plugged in with a thin adapter, without the SDK
+// depending on OpenTelemetry itself.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// Tracer starts spans
+type Tracer interface {
+	// Start creates a span and returns a context carrying it
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a single traced operation
+type Span interface {
+	// SetAttributes records attributes on the span
+	SetAttributes(attrs ...slog.Attr)
+	// AddEvent records a timestamped event on the span
+	AddEvent(name string, attrs ...slog.Attr)
+	// RecordError records err and marks the span as failed
+	RecordError(err error)
+	// End completes the span
+	End()
+}
+
+type spanContextKey struct{}
+
+// startSpan starts the span covering one logical request, retries included
+func (c *Client) startSpan(ctx context.Context, method, path string) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+
+	template := pathTemplate(path)
+	if ci := callFromContext(ctx); ci != nil {
+		template = ci.template
+	}
+	ctx, span := c.tracer.Start(ctx, method+" "+template)
+	span.SetAttributes(
+		slog.String("http.request.method", method),
+		slog.String("url.full", sanitizeURL(c.requestURL(ctx, path))),
+		slog.String("url.template", template),
+	)
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// endSpan records the outcome of the request and ends the span
+func endSpan(span Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.SetAttributes(slog.String("error.type", errorType(err)))
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// spanFromContext returns the request span started by the client, if any
+func spanFromContext(ctx context.Context) Span {
+	span, _ := ctx.Value(spanContextKey{}).(Span)
+	return span
+}
+
+// traceResponse records the status of an attempt on the request span
+func traceResponse(ctx context.Context, status int) {
+	if span := spanFromContext(ctx); span != nil {
+		span.SetAttributes(slog.Int("http.response.status_code", status))
+	}
+}
+
+// traceRetry records a scheduled retry as a span event
+func traceRetry(ctx context.Context, attempt int, backoff time.Duration, reason string) {
+	if span := spanFromContext(ctx); span != nil {
+		span.AddEvent("retry",
+			slog.Int("http.request.resend_count", attempt+1),
+			slog.Duration("backoff", backoff),
+			slog.String("reason", reason),
+		)
+	}
+}
+
+// sanitizeURL drops credentials, query and fragment, which may carry secrets
+// or personal data
+func sanitizeURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// errorType returns a low-cardinality description of err for error.type
+func errorType(err error) string {
+	key := errorMetricKey("", "", err)
+	switch {
+	case key.Code != "":
+		return key.Code
+	case key.Status != 0:
+		return http.StatusText(key.Status)
+	default:
+		return "_OTHER"
+	}
+}
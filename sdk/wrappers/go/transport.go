@@ -0,0 +1,169 @@
+package yourapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// transport returns the transport built from the transport options of o,
+// or nil when none is set and the default transport serves. It expects a
+// normalized BaseURL.
+func (o *ClientOptions) transport() (http.RoundTripper, error) {
+	socket, err := parseUnixBaseURL(o.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if o.ProxyURL == "" && o.NoProxy == nil && o.TLS == (TLSOptions{}) && o.DialContext == nil && o.Resolver == nil && o.Hosts == nil && socket == "" {
+		return nil, nil
+	}
+	if o.HTTPClient != nil {
+		if socket != "" {
+			return nil, errors.New("a unix:// BaseURL cannot be combined with HTTPClient; dial the socket from its transport instead")
+		}
+		return nil, errors.New("ProxyURL, NoProxy, TLS, DialContext, Resolver and Hosts cannot be combined with HTTPClient; configure its transport instead")
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if socket != "" {
+		if o.ProxyURL != "" || o.DialContext != nil || o.Resolver != nil || o.Hosts != nil {
+			return nil, errors.New("a unix:// BaseURL cannot be combined with ProxyURL, DialContext, Resolver or Hosts")
+		}
+		t.Proxy, t.DialContext = nil, dialUnix(socket)
+		return t, nil
+	}
+	proxy, err := proxyFunc(o.ProxyURL, o.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+	t.Proxy = proxy
+	if t.DialContext, err = o.dialContext(t.DialContext); err != nil {
+		return nil, err
+	}
+	if t.TLSClientConfig, err = o.TLS.config(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// dialContext wraps dial, the default transport's dialer, with the
+// DialContext, Resolver and Hosts options of o
+func (o *ClientOptions) dialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	switch {
+	case o.DialContext != nil && o.Resolver != nil:
+		return nil, errors.New("Resolver cannot be combined with DialContext, which resolves hosts itself")
+	case o.DialContext != nil:
+		dial = o.DialContext
+	case o.Resolver != nil:
+		dial = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Resolver:  o.Resolver,
+		}).DialContext
+	}
+	if o.Hosts == nil {
+		return dial, nil
+	}
+	hosts := make(map[string]string, len(o.Hosts))
+	for host, ip := range o.Hosts {
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("Hosts[%q]: %q is not an IP address", host, ip)
+		}
+		hosts[strings.ToLower(host)] = ip
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if ip, ok := hosts[strings.ToLower(host)]; ok {
+				addr = net.JoinHostPort(ip, port)
+			}
+		}
+		return dial(ctx, network, addr)
+	}, nil
+}
+
+// proxyFunc returns the transport's Proxy function: requests go through
+// proxyURL, or the HTTP_PROXY and HTTPS_PROXY proxies when it is empty,
+// except to hosts matching noProxy
+func proxyFunc(proxyURL string, noProxy []string) (func(*http.Request) (*url.URL, error), error) {
+	fixed, err := parseProxyURL(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if matchNoProxy(noProxy, req.URL) {
+			return nil, nil
+		}
+		if fixed != nil {
+			return fixed, nil
+		}
+		return http.ProxyFromEnvironment(req)
+	}, nil
+}
+
+// parseProxyURL validates a proxy URL, returning nil for an empty one
+func parseProxyURL(s string) (*url.URL, error) {
+	if s == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ProxyURL %q: %w", s, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid ProxyURL %q: scheme must be http, https or socks5", s)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid ProxyURL %q: missing host", s)
+	}
+	return u, nil
+}
+
+// matchNoProxy reports whether u's host matches an entry of patterns,
+// written like NO_PROXY entries: "*" for every host, a domain matching
+// itself and its subdomains (with or without a leading dot), an IP address
+// or a CIDR range, each optionally followed by a port
+func matchNoProxy(patterns []string, u *url.URL) bool {
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	ip := net.ParseIP(host)
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if p == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(p); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, pp, err := net.SplitHostPort(p); err == nil {
+			if pp != port {
+				continue
+			}
+			p = h
+		}
+		if pip := net.ParseIP(strings.Trim(p, "[]")); pip != nil {
+			if ip != nil && pip.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		domain := strings.TrimPrefix(p, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
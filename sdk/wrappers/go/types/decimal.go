@@ -0,0 +1,268 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, the type of format: decimal and
+// format: money fields. It keeps the digits it was given, so "19.90" stays
+// "19.90" through a round trip, where float64 would turn currency amounts
+// into approximations. It encodes as a JSON number and decodes from a
+// number or a numeric string. The zero value is 0.
+type Decimal struct {
+	s string
+}
+
+// ParseDecimal parses a decimal such as "19.99", "-0.5" or "1.2e3"
+func ParseDecimal(s string) (Decimal, error) {
+	canonical, ok := canonicalDecimal(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("types: invalid decimal %q", s)
+	}
+	return Decimal{s: canonical}, nil
+}
+
+// MustDecimal is like ParseDecimal but panics on an invalid decimal. It is
+// meant for constants.
+func MustDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromInt returns unscaled divided by 10^scale, e.g.
+// DecimalFromInt(1999, 2) is 19.99. It is the natural way to turn minor
+// currency units into an amount.
+func DecimalFromInt(unscaled int64, scale int) Decimal {
+	return fromParts(big.NewInt(unscaled), scale)
+}
+
+// String returns the decimal's digits
+func (d Decimal) String() string {
+	if d.s == "" {
+		return "0"
+	}
+	return d.s
+}
+
+// IsZero reports whether the decimal equals 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Sign returns -1, 0 or +1 by the decimal's sign
+func (d Decimal) Sign() int {
+	u, _ := d.parts()
+	return u.Sign()
+}
+
+// Cmp compares d and other by value, returning -1, 0 or +1. 1.5 and 1.50
+// are equal.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Add returns d + other, exactly
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return fromParts(a.Add(a, b), scale)
+}
+
+// Sub returns d - other, exactly
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return fromParts(a.Sub(a, b), scale)
+}
+
+// Mul returns d × other, exactly
+func (d Decimal) Mul(other Decimal) Decimal {
+	a, sa := d.parts()
+	b, sb := other.parts()
+	return fromParts(a.Mul(a, b), sa+sb)
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	u, scale := d.parts()
+	return fromParts(u.Neg(u), scale)
+}
+
+// Rat returns the decimal as an exact rational
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// Float64 returns the nearest float64, for display or math where exactness
+// does not matter
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// MarshalJSON encodes the decimal as a JSON number with its digits intact
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a string holding one
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+	parsed, err := ParseDecimal(string(data))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalText encodes the decimal's digits, for query parameters
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a decimal's digits
+func (d *Decimal) UnmarshalText(data []byte) error {
+	parsed, err := ParseDecimal(string(data))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// DecimalString is a Decimal that encodes as a JSON string, the type of
+// type: string fields with format: decimal or money
+type DecimalString struct {
+	Decimal
+}
+
+// MarshalJSON encodes the decimal as a JSON string
+func (d DecimalString) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// maxExponent bounds the exponent of a parsed decimal
+const maxExponent = 1000
+
+// canonicalDecimal validates s and returns it as a JSON number: no leading
+// plus sign or redundant zeros, and digits on both sides of the point
+func canonicalDecimal(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	var b strings.Builder
+	if strings.HasPrefix(s, "-") {
+		b.WriteByte('-')
+		s = s[1:]
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	mantissa, exp := s, ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exp = s[:i], s[i+1:]
+		digits := strings.TrimLeft(exp, "+-")
+		// Huge exponents would expand to huge numbers in arithmetic
+		if n, err := strconv.Atoi(exp); err != nil || !isDigits(digits) || n > maxExponent || n < -maxExponent {
+			return "", false
+		}
+	}
+	intPart, fracPart, hasPoint := strings.Cut(mantissa, ".")
+	if intPart == "" && fracPart == "" || intPart != "" && !isDigits(intPart) || fracPart != "" && !isDigits(fracPart) {
+		return "", false
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	b.WriteString(intPart)
+	if hasPoint && fracPart != "" {
+		b.WriteString("." + fracPart)
+	}
+	if exp != "" {
+		b.WriteString("e" + exp)
+	}
+	return b.String(), true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parts returns the decimal as unscaled × 10^-scale
+func (d Decimal) parts() (*big.Int, int) {
+	s := d.String()
+	scale := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, _ := strconv.Atoi(s[i+1:])
+		scale = -exp
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		scale += len(s) - i - 1
+		s = s[:i] + s[i+1:]
+	}
+	u, _ := new(big.Int).SetString(s, 10)
+	if scale < 0 {
+		u.Mul(u, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-scale)), nil))
+		scale = 0
+	}
+	return u, scale
+}
+
+// align returns the unscaled values of a and b at their common scale
+func align(a, b Decimal) (*big.Int, *big.Int, int) {
+	ua, sa := a.parts()
+	ub, sb := b.parts()
+	for ; sa < sb; sa++ {
+		ua.Mul(ua, big.NewInt(10))
+	}
+	for ; sb < sa; sb++ {
+		ub.Mul(ub, big.NewInt(10))
+	}
+	return ua, ub, sa
+}
+
+// fromParts formats unscaled × 10^-scale
+func fromParts(u *big.Int, scale int) Decimal {
+	if scale < 0 {
+		u = new(big.Int).Mul(u, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-scale)), nil))
+		scale = 0
+	}
+	digits := new(big.Int).Abs(u).String()
+	sign := ""
+	if u.Sign() < 0 {
+		sign = "-"
+	}
+	if scale == 0 {
+		return Decimal{s: sign + digits}
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return Decimal{s: sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]}
+}
@@ -0,0 +1,89 @@
+// Package types holds the field types used by the generated models:
+// Nullable, telling an absent field from a null one, exact decimals, and
+// the date and epoch time types time.Time cannot decode
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Nullable is a value that may be absent, explicitly null, or set. It is
+// what PATCH requests need: an absent field leaves the stored value alone,
+// null clears it, and a value replaces it. Generated models give structs
+// holding Nullable fields a MarshalJSON leaving unset ones out.
+type Nullable[T any] struct {
+	value T
+	state nullState
+}
+
+type nullState uint8
+
+const (
+	unset nullState = iota
+	null
+	present
+)
+
+// Value returns a Nullable holding v
+func Value[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, state: present}
+}
+
+// Null returns a Nullable that encodes as null
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{state: null}
+}
+
+// Get returns the value and whether one is set; it returns false for both
+// null and absent
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.state == present
+}
+
+// Or returns the value, or def when it is null or absent
+func (n Nullable[T]) Or(def T) T {
+	if n.state != present {
+		return def
+	}
+	return n.value
+}
+
+// IsNull reports whether the value is explicitly null
+func (n Nullable[T]) IsNull() bool {
+	return n.state == null
+}
+
+// IsSet reports whether the field is present, as null or as a value
+func (n Nullable[T]) IsSet() bool {
+	return n.state != unset
+}
+
+// IsZero reports whether the field is absent
+func (n Nullable[T]) IsZero() bool {
+	return n.state == unset
+}
+
+// MarshalJSON encodes the value, or null when it is null or absent
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.state != present {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.value)
+}
+
+// UnmarshalJSON sets the value or null. It is only called for fields
+// present in the input, so absent fields stay unset.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		var zero T
+		n.value, n.state = zero, null
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.value, n.state = v, present
+	return nil
+}
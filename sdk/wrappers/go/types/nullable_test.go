@@ -0,0 +1,74 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/devdraft/devdraft-sdk-go/models"
+	"github.com/devdraft/devdraft-sdk-go/types"
+)
+
+func TestNullableStates(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         types.Nullable[int]
+		set, null bool
+		value     int
+		ok        bool
+		json      string
+	}{
+		{name: "absent", json: "null"},
+		{name: "null", n: types.Null[int](), set: true, null: true, json: "null"},
+		{name: "zero value", n: types.Value(0), set: true, ok: true, json: "0"},
+		{name: "value", n: types.Value(7), set: true, value: 7, ok: true, json: "7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := tt.n.Get()
+			if tt.n.IsSet() != tt.set || tt.n.IsNull() != tt.null || v != tt.value || ok != tt.ok || tt.n.IsZero() == tt.set {
+				t.Errorf("got set %v, null %v, Get %v, %v", tt.n.IsSet(), tt.n.IsNull(), v, ok)
+			}
+			if got := tt.n.Or(-1); ok && got != tt.value || !ok && got != -1 {
+				t.Errorf("Or(-1) = %d", got)
+			}
+			data, err := json.Marshal(tt.n)
+			if err != nil || string(data) != tt.json {
+				t.Errorf("Marshal = %s, %v; want %s", data, err, tt.json)
+			}
+		})
+	}
+}
+
+// The generated models leave unset Nullable fields out, so absent, null and
+// a value survive a round trip
+func TestNullableRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone types.Nullable[string]
+		json  string
+	}{
+		{"absent", types.Nullable[string]{}, `{"email":"ada@example.com","name":"Ada"}`},
+		{"null", types.Null[string](), `{"email":"ada@example.com","name":"Ada","phone":null}`},
+		{"empty", types.Value(""), `{"email":"ada@example.com","name":"Ada","phone":""}`},
+		{"value", types.Value("+1 555 0100"), `{"email":"ada@example.com","name":"Ada","phone":"+1 555 0100"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := models.CustomerCreateRequest{Email: "ada@example.com", Name: "Ada", Phone: tt.phone}
+			data, err := json.Marshal(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("encoded %s, want %s", data, tt.json)
+			}
+			var back models.CustomerCreateRequest
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatal(err)
+			}
+			if back.Phone != tt.phone {
+				t.Errorf("decoded %+v, want %+v", back.Phone, tt.phone)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// dateLayout is the full-date form of RFC 3339 used by format: date
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date without a time of day or time zone, the type of
+// format: date fields. It encodes as "2006-01-02", which time.Time cannot
+// decode.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date of t in t's location
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// ParseDate parses a "2006-01-02" date. A full RFC 3339 timestamp is also
+// accepted, keeping its date as written.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		ts, tsErr := time.Parse(time.RFC3339Nano, s)
+		if tsErr != nil {
+			return Date{}, fmt.Errorf("types: invalid date %q", s)
+		}
+		t = ts
+	}
+	return DateOf(t), nil
+}
+
+// String returns the date as "2006-01-02"
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero reports whether the date is unset
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// In returns midnight at the start of the date in loc
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Before reports whether d comes before other
+func (d Date) Before(other Date) bool {
+	if d.Year != other.Year {
+		return d.Year < other.Year
+	}
+	if d.Month != other.Month {
+		return d.Month < other.Month
+	}
+	return d.Day < other.Day
+}
+
+// MarshalText encodes the date as "2006-01-02"
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a date, see ParseDate
+func (d *Date) UnmarshalText(data []byte) error {
+	parsed, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// UnixTime is an instant encoded as seconds since the Unix epoch, the type
+// of unix-time fields. Fractional seconds are kept when decoding. The zero
+// Time encodes as 0, and 0 decodes to the zero Time.
+type UnixTime struct {
+	time.Time
+}
+
+// Unix returns the UnixTime of a count of seconds
+func Unix(sec int64) UnixTime {
+	if sec == 0 {
+		return UnixTime{}
+	}
+	return UnixTime{time.Unix(sec, 0)}
+}
+
+// MarshalJSON encodes the time as whole seconds
+func (t UnixTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("0"), nil
+	}
+	return strconv.AppendInt(nil, t.Unix(), 10), nil
+}
+
+// UnmarshalJSON decodes seconds given as a number or numeric string
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	v, ok, err := epochValue(data)
+	if err != nil || !ok {
+		return err
+	}
+	sec, frac := math.Modf(v)
+	t.Time = epochTime(int64(sec), int64(math.Round(frac*1e9)))
+	return nil
+}
+
+// MarshalText encodes the time as whole seconds, replacing time.Time's
+// RFC 3339 text in query parameters
+func (t UnixTime) MarshalText() ([]byte, error) {
+	return t.MarshalJSON()
+}
+
+// UnmarshalText decodes seconds
+func (t *UnixTime) UnmarshalText(data []byte) error {
+	return t.UnmarshalJSON(data)
+}
+
+// UnixMilliTime is an instant encoded as milliseconds since the Unix epoch,
+// the type of unix-time-ms fields. The zero Time encodes as 0, and 0
+// decodes to the zero Time.
+type UnixMilliTime struct {
+	time.Time
+}
+
+// UnixMilli returns the UnixMilliTime of a count of milliseconds
+func UnixMilli(msec int64) UnixMilliTime {
+	if msec == 0 {
+		return UnixMilliTime{}
+	}
+	return UnixMilliTime{time.UnixMilli(msec)}
+}
+
+// MarshalJSON encodes the time as whole milliseconds
+func (t UnixMilliTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("0"), nil
+	}
+	return strconv.AppendInt(nil, t.UnixMilli(), 10), nil
+}
+
+// UnmarshalJSON decodes milliseconds given as a number or numeric string
+func (t *UnixMilliTime) UnmarshalJSON(data []byte) error {
+	v, ok, err := epochValue(data)
+	if err != nil || !ok {
+		return err
+	}
+	msec, frac := math.Modf(v)
+	t.Time = epochTime(int64(msec)/1e3, int64(msec)%1e3*1e6+int64(math.Round(frac*1e6)))
+	return nil
+}
+
+// MarshalText encodes the time as whole milliseconds
+func (t UnixMilliTime) MarshalText() ([]byte, error) {
+	return t.MarshalJSON()
+}
+
+// UnmarshalText decodes milliseconds
+func (t *UnixMilliTime) UnmarshalText(data []byte) error {
+	return t.UnmarshalJSON(data)
+}
+
+// epochValue decodes a JSON number, or a string holding one, reporting
+// false for null
+func epochValue(data []byte) (float64, bool, error) {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return 0, false, nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return 0, false, err
+		}
+		data = []byte(s)
+	}
+	v, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("types: invalid epoch time %s", data)
+	}
+	return v, true, nil
+}
+
+// epochTime returns the time of an epoch offset, mapping the epoch itself
+// to the zero Time
+func epochTime(sec, nsec int64) time.Time {
+	if sec == 0 && nsec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, nsec)
+}
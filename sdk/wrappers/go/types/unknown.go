@@ -0,0 +1,73 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// UnknownFields returns the members of the JSON object data whose names are
+// not in known, or nil when there are none. Models generated with unknown
+// field preservation keep them, so members added to the API after the SDK
+// was generated survive a read-modify-write.
+func UnknownFields(data []byte, known ...string) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for _, name := range known {
+		delete(members, name)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return members, nil
+}
+
+// MergeUnknown adds the members of unknown to the encoded JSON object data,
+// in name order. Members data already holds take precedence.
+func MergeUnknown(data []byte, unknown map[string]json.RawMessage) ([]byte, error) {
+	if len(unknown) == 0 {
+		return data, nil
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return nil, fmt.Errorf("types: unknown fields can only be added to an object")
+	}
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(data, &present); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		if _, ok := present[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return data, nil
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for i, name := range names {
+		if len(present) > 0 || i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value := unknown[name]
+		if !json.Valid(value) {
+			return nil, fmt.Errorf("types: unknown field %q holds invalid JSON", name)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,48 @@
+package yourapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// unixRequestBase is the base URL of requests sent over a unix socket: the
+// dialer ignores the host, which only fills the Host header
+const unixRequestBase = "http://localhost"
+
+// parseUnixBaseURL returns the socket path of a unix:// base URL such as
+// "unix:///var/run/yourapi.sock", or "" for any other URL
+func parseUnixBaseURL(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if len(s) < len("unix:") || !strings.EqualFold(s[:len("unix:")], "unix:") {
+		return "", nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", raw, err)
+	}
+	switch {
+	case u.Host != "" || u.Opaque != "" || !strings.HasPrefix(u.Path, "/"):
+		return "", fmt.Errorf("invalid base URL %q: want an absolute socket path, e.g. unix:///var/run/yourapi.sock", raw)
+	case u.RawQuery != "" || u.ForceQuery || u.Fragment != "":
+		return "", fmt.Errorf("invalid base URL %q: must not have a query or fragment", raw)
+	}
+	return u.Path, nil
+}
+
+// dialUnix returns a DialContext connecting every request to socket
+func dialUnix(socket string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	var d net.Dialer
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return d.DialContext(ctx, "unix", socket)
+	}
+}
+
+// sameSocket reports whether requests to baseURL go over socket, so a clone
+// of a client with that base URL can share its transport
+func sameSocket(baseURL, socket string) bool {
+	s, _ := parseUnixBaseURL(baseURL)
+	return s == socket
+}
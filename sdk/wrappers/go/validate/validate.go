@@ -0,0 +1,194 @@
+// Package validate checks request values against the constraints in the
+// OpenAPI spec. The generated models and parameter types call it from their
+// Validate methods.
+package validate
+
+import (
+	"errors"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError describes a validation failure for a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors lists the failures found in a value
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, f := range e {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Err returns e, or nil when it is empty
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Add records a failure of field
+func (e *Errors) Add(field, code, message string) {
+	*e = append(*e, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Nested records the failures of a nested value, prefixing their fields
+// with field
+func (e *Errors) Nested(field string, err error) {
+	if err == nil {
+		return
+	}
+	var nested Errors
+	if !errors.As(err, &nested) {
+		e.Add(field, "invalid", err.Error())
+		return
+	}
+	for _, f := range nested {
+		f.Field = field + "." + f.Field
+		*e = append(*e, f)
+	}
+}
+
+// Index names the element of a list field, e.g. "items[2]"
+func Index(field string, i int) string {
+	return field + "[" + strconv.Itoa(i) + "]"
+}
+
+// Required records a failure when missing is true
+func (e *Errors) Required(field string, missing bool) {
+	if missing {
+		e.Add(field, "required", "is required")
+	}
+}
+
+// Enum records a failure when value is not one of the known values
+func (e *Errors) Enum(field, value string, valid bool) {
+	if !valid {
+		e.Add(field, "enum", strconv.Quote(value)+" is not an allowed value")
+	}
+}
+
+// MinLength checks that s has at least n characters
+func (e *Errors) MinLength(field, s string, n int) {
+	if utf8.RuneCountInString(s) < n {
+		e.Add(field, "min_length", "must be at least "+strconv.Itoa(n)+" characters")
+	}
+}
+
+// MaxLength checks that s has at most n characters
+func (e *Errors) MaxLength(field, s string, n int) {
+	if utf8.RuneCountInString(s) > n {
+		e.Add(field, "max_length", "must be at most "+strconv.Itoa(n)+" characters")
+	}
+}
+
+// MinItems checks that a list of n items has at least min
+func (e *Errors) MinItems(field string, n, min int) {
+	if n < min {
+		e.Add(field, "min_items", "must have at least "+strconv.Itoa(min)+" items")
+	}
+}
+
+// MaxItems checks that a list of n items has at most max
+func (e *Errors) MaxItems(field string, n, max int) {
+	if n > max {
+		e.Add(field, "max_items", "must have at most "+strconv.Itoa(max)+" items")
+	}
+}
+
+// Minimum checks that v is at least min, or above it when exclusive
+func (e *Errors) Minimum(field string, v, min float64, exclusive bool) {
+	switch {
+	case exclusive && v <= min:
+		e.Add(field, "minimum", "must be greater than "+formatNumber(min))
+	case v < min:
+		e.Add(field, "minimum", "must be at least "+formatNumber(min))
+	}
+}
+
+// Maximum checks that v is at most max, or below it when exclusive
+func (e *Errors) Maximum(field string, v, max float64, exclusive bool) {
+	switch {
+	case exclusive && v >= max:
+		e.Add(field, "maximum", "must be less than "+formatNumber(max))
+	case v > max:
+		e.Add(field, "maximum", "must be at most "+formatNumber(max))
+	}
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// patterns caches compiled patterns by source
+var patterns sync.Map
+
+// Pattern checks that s matches the regular expression pattern. Patterns
+// Go cannot compile are not checked, leaving them to the server.
+func (e *Errors) Pattern(field, s, pattern string) {
+	var re *regexp.Regexp
+	if cached, ok := patterns.Load(pattern); ok {
+		re, _ = cached.(*regexp.Regexp)
+	} else {
+		re, _ = regexp.Compile(pattern)
+		patterns.Store(pattern, re)
+	}
+	if re != nil && !re.MatchString(s) {
+		e.Add(field, "pattern", "must match "+pattern)
+	}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// formats are the string formats Format checks
+var formats = map[string]func(string) bool{
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"uuid": uuidPattern.MatchString,
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	},
+	"date": func(s string) bool {
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	},
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool {
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	},
+}
+
+// KnownFormat reports whether Format checks the named format
+func KnownFormat(format string) bool {
+	_, ok := formats[format]
+	return ok
+}
+
+// Format checks that s is a valid value of the named string format. Formats
+// it does not know always pass.
+func (e *Errors) Format(field, s, format string) {
+	if valid, ok := formats[format]; ok && !valid(s) {
+		e.Add(field, "format", "must be a valid "+format)
+	}
+}
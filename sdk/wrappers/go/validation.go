@@ -0,0 +1,45 @@
+package yourapi
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/devdraft/devdraft-sdk-go/validate"
+)
+
+// validator is implemented by the generated models and parameter types
+type validator interface {
+	Validate() error
+}
+
+// errInvalidRequest is the code of locally found ValidationErrors
+const errInvalidRequest = "invalid_request"
+
+// validateRequest runs v's generated checks, returning a local
+// ValidationError when they fail. A nil pointer is checked as its zero
+// value, so missing required parameters are still reported.
+func (c *Client) validateRequest(v interface{}) error {
+	if c.skipValidation || v == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		v = reflect.New(rv.Type().Elem()).Interface()
+	}
+	val, ok := v.(validator)
+	if !ok {
+		return nil
+	}
+	err := val.Validate()
+	if err == nil {
+		return nil
+	}
+	var fields validate.Errors
+	if !errors.As(err, &fields) {
+		fields = validate.Errors{{Code: "invalid", Message: err.Error()}}
+	}
+	return &ValidationError{
+		APIError: &APIError{Message: "request failed validation", Code: errInvalidRequest},
+		Fields:   fields,
+		Local:    true,
+	}
+}
@@ -0,0 +1,96 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidEvent is returned when a payload is not a well-formed event
+// envelope
+var ErrInvalidEvent = errors.New("webhooks: invalid event")
+
+// Event is a parsed webhook delivery
+type Event struct {
+	// ID uniquely identifies the event; redeliveries keep it
+	ID string
+	// Type is the event type, e.g. EventCustomerCreated
+	Type string
+	// CreatedAt is when the event occurred
+	CreatedAt time.Time
+	// Payload is the whole event decoded as the type registered for Type,
+	// e.g. *models.CustomerCreatedEvent whose Data is the typed object, or
+	// *UnknownEvent for a type missing from the registry
+	Payload interface{}
+}
+
+// UnknownEvent is the payload of an event whose type is not registered,
+// such as one added to the API after this SDK was generated. Handling it,
+// or ignoring it, keeps endpoints working as the API grows.
+type UnknownEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// envelope holds the members every event carries
+type envelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt *time.Time      `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ParseEvent parses a webhook request body with the Events registry, see
+// Registry.ParseEvent
+func ParseEvent(payload []byte) (Event, error) {
+	return Events.ParseEvent(payload)
+}
+
+// ParseEvent checks that payload is an event envelope, with an id, type,
+// createdAt and data, and decodes it as the type registered for its event
+// type:
+//
+//	event, err := webhooks.ParseEvent(body)
+//	if err != nil {
+//		return err
+//	}
+//	switch p := event.Payload.(type) {
+//	case *models.CustomerCreatedEvent:
+//		return welcome(ctx, p.Data.Email)
+//	case *webhooks.UnknownEvent:
+//		log.Printf("ignoring %s event %s", p.Type, p.ID)
+//	}
+//
+// Errors wrap ErrInvalidEvent. Event types the registry does not know
+// decode as *UnknownEvent rather than failing.
+func (r *Registry) ParseEvent(payload []byte) (Event, error) {
+	var env envelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	switch {
+	case env.ID == "":
+		return Event{}, fmt.Errorf("%w: missing id", ErrInvalidEvent)
+	case env.Type == "":
+		return Event{}, fmt.Errorf("%w: missing type", ErrInvalidEvent)
+	case env.CreatedAt == nil:
+		return Event{}, fmt.Errorf("%w: missing createdAt", ErrInvalidEvent)
+	case len(env.Data) == 0 || string(env.Data) == "null":
+		return Event{}, fmt.Errorf("%w: missing data", ErrInvalidEvent)
+	}
+	event := Event{ID: env.ID, Type: env.Type, CreatedAt: *env.CreatedAt}
+
+	v, ok := r.New(env.Type)
+	if !ok {
+		event.Payload = &UnknownEvent{ID: env.ID, Type: env.Type, CreatedAt: *env.CreatedAt, Data: env.Data}
+		return event, nil
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return Event{}, fmt.Errorf("%w: decoding %s event: %v", ErrInvalidEvent, env.Type, err)
+	}
+	event.Payload = v
+	return event, nil
+}
@@ -0,0 +1,77 @@
+// Code generated by saligen-go. DO NOT EDIT.
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/devdraft/devdraft-sdk-go/models"
+)
+
+// Event types sent by the API
+const (
+	EventCustomerCreated = "customer.created"
+	EventCustomerUpdated = "customer.updated"
+	EventCustomerDeleted = "customer.deleted"
+)
+
+// registerEvents adds the spec's event types to r
+func registerEvents(r *Registry) {
+	r.types[EventCustomerCreated] = reflect.TypeOf(models.CustomerCreatedEvent{})
+	r.types[EventCustomerUpdated] = reflect.TypeOf(models.CustomerUpdatedEvent{})
+	r.types[EventCustomerDeleted] = reflect.TypeOf(models.CustomerDeletedEvent{})
+}
+
+// Dispatcher routes webhook events to typed handlers. Events whose handler
+// is nil are ignored.
+type Dispatcher struct {
+	// CustomerCreated handles customer.created events
+	CustomerCreated func(ctx context.Context, event *models.CustomerCreatedEvent) error
+	// CustomerUpdated handles customer.updated events
+	CustomerUpdated func(ctx context.Context, event *models.CustomerUpdatedEvent) error
+	// CustomerDeleted handles customer.deleted events
+	CustomerDeleted func(ctx context.Context, event *models.CustomerDeletedEvent) error
+	// Unknown handles event types missing from the spec; nil ignores them
+	Unknown func(ctx context.Context, eventType string, payload []byte) error
+}
+
+// Dispatch decodes payload as eventType's payload type and calls its
+// handler
+func (d *Dispatcher) Dispatch(ctx context.Context, eventType string, payload []byte) error {
+	switch eventType {
+	case EventCustomerCreated:
+		if d.CustomerCreated == nil {
+			return nil
+		}
+		var event models.CustomerCreatedEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return fmt.Errorf("webhooks: decoding %s event: %w", eventType, err)
+		}
+		return d.CustomerCreated(ctx, &event)
+	case EventCustomerUpdated:
+		if d.CustomerUpdated == nil {
+			return nil
+		}
+		var event models.CustomerUpdatedEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return fmt.Errorf("webhooks: decoding %s event: %w", eventType, err)
+		}
+		return d.CustomerUpdated(ctx, &event)
+	case EventCustomerDeleted:
+		if d.CustomerDeleted == nil {
+			return nil
+		}
+		var event models.CustomerDeletedEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return fmt.Errorf("webhooks: decoding %s event: %w", eventType, err)
+		}
+		return d.CustomerDeleted(ctx, &event)
+	}
+	if d.Unknown != nil {
+		return d.Unknown(ctx, eventType, payload)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DefaultMaxBodySize is the default cap on webhook request bodies
+const DefaultMaxBodySize = 1 << 20 // 1 MiB
+
+// HandlerFunc handles one parsed event. Returning an error makes the
+// Handler answer 500, so the API redelivers the event later.
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// Handler is an http.Handler receiving webhook deliveries. It verifies
+// each delivery's signature, parses the event and calls the HandlerFunc
+// registered for its type:
+//
+//	h := webhooks.NewHandler(os.Getenv("WEBHOOK_SECRET"))
+//	h.On(webhooks.EventCustomerCreated, func(ctx context.Context, e webhooks.Event) error {
+//		return welcome(ctx, e.Payload.(*models.CustomerCreatedEvent).Data.Email)
+//	})
+//	http.Handle("/webhooks", h)
+//
+// Its status codes follow the API's redelivery rules, which retry anything
+// but a 2xx: 200 once the event is handled, has no handler or was already
+// claimed, 400 for a malformed event, 401 for a bad or expired signature,
+// 405 for a method other than POST and 413 for an oversized body, none of
+// which a retry would fix, and 500 when the handler or the ReplayStore
+// fails or no secret is configured.
+type Handler struct {
+	// Verifier checks each delivery's signature
+	Verifier Verifier
+	// Registry decodes events (default: Events)
+	Registry *Registry
+	// MaxBodySize caps request bodies (default: DefaultMaxBodySize)
+	MaxBodySize int64
+	// ErrorLog receives the error behind every non-2xx response, and
+	// failures to release the ReplayStore claim of a failed event
+	// (optional)
+	ErrorLog func(r *http.Request, err error)
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	fallback HandlerFunc
+}
+
+// NewHandler returns a Handler verifying signatures with secrets
+func NewHandler(secrets ...string) *Handler {
+	return &Handler{Verifier: Verifier{Secrets: secrets}}
+}
+
+// On registers fn for events of eventType, replacing any previous one
+func (h *Handler) On(eventType string, fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.handlers == nil {
+		h.handlers = make(map[string]HandlerFunc)
+	}
+	h.handlers[eventType] = fn
+}
+
+// OnOther registers fn for events of every type without a handler of its
+// own, including unknown ones; without it they are acknowledged unhandled
+func (h *Handler) OnOther(fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fallback = fn
+}
+
+// handler returns the HandlerFunc for eventType, or nil
+func (h *Handler) handler(eventType string) HandlerFunc {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if fn, ok := h.handlers[eventType]; ok {
+		return fn
+	}
+	return h.fallback
+}
+
+// ServeHTTP handles a webhook delivery
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, http.StatusMethodNotAllowed, fmt.Errorf("webhooks: method %s not allowed", r.Method))
+		return
+	}
+	limit := h.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.fail(w, r, http.StatusRequestEntityTooLarge, fmt.Errorf("webhooks: body exceeds %d bytes", limit))
+			return
+		}
+		h.fail(w, r, http.StatusBadRequest, fmt.Errorf("webhooks: reading body: %w", err))
+		return
+	}
+
+	if err := h.Verifier.Verify(r.Header.Get(SignatureHeader), body); err != nil {
+		status := http.StatusUnauthorized
+		if errors.Is(err, ErrNoSecrets) {
+			status = http.StatusInternalServerError
+		}
+		h.fail(w, r, status, err)
+		return
+	}
+
+	registry := h.Registry
+	if registry == nil {
+		registry = Events
+	}
+	event, err := registry.ParseEvent(body)
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.Verifier.Claim(r.Context(), event.ID); err != nil {
+		if errors.Is(err, ErrReplayed) {
+			// Handled before or being handled: acknowledge, so the sender
+			// stops redelivering
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if fn := h.handler(event.Type); fn != nil {
+		if err := fn(r.Context(), event); err != nil {
+			if err := h.Verifier.Release(r.Context(), event.ID); err != nil && h.ErrorLog != nil {
+				// The redelivery will be taken for a replay until the claim
+				// expires
+				h.ErrorLog(r, err)
+			}
+			h.fail(w, r, http.StatusInternalServerError, fmt.Errorf("webhooks: handling %s event %s: %w", event.Type, event.ID, err))
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// fail answers status and reports err to ErrorLog
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.ErrorLog != nil {
+		h.ErrorLog(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
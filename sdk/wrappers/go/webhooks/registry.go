@@ -0,0 +1,81 @@
+// Package webhooks receives the events the API sends to webhook endpoints:
+// it verifies their signatures and decodes them into the typed payloads
+// generated from the OpenAPI spec
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownEventType is returned when decoding an event type the registry
+// does not know
+var ErrUnknownEventType = errors.New("webhooks: unknown event type")
+
+// Registry maps event types to the Go types their payloads decode into
+type Registry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+// Events holds every event type in the spec
+var Events = NewRegistry()
+
+// NewRegistry returns a Registry holding the spec's event types
+func NewRegistry() *Registry {
+	r := &Registry{types: make(map[string]reflect.Type)}
+	registerEvents(r)
+	return r
+}
+
+// Register maps eventType to the type of payload, replacing any previous
+// mapping. Pass a value, e.g. models.CustomerCreatedEvent{}, not a pointer.
+func (r *Registry) Register(eventType string, payload interface{}) {
+	t := reflect.TypeOf(payload)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[eventType] = t
+}
+
+// New returns a pointer to a new zero payload for eventType
+func (r *Registry) New(eventType string) (interface{}, bool) {
+	r.mu.RLock()
+	t, ok := r.types[eventType]
+	r.mu.RUnlock()
+	if !ok || t == nil {
+		return nil, false
+	}
+	return reflect.New(t).Interface(), true
+}
+
+// Decode decodes data into a new payload for eventType, returning a pointer
+// such as *models.CustomerCreatedEvent
+func (r *Registry) Decode(eventType string, data []byte) (interface{}, error) {
+	v, ok := r.New(eventType)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownEventType, eventType)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("webhooks: decoding %s event: %w", eventType, err)
+	}
+	return v, nil
+}
+
+// Types returns the registered event types, sorted
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.types))
+	for t := range r.types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
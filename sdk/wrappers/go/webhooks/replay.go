@@ -0,0 +1,66 @@
+package webhooks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReplayStore remembers the IDs of claimed events. Implement it over a
+// shared store such as Redis when several instances receive deliveries,
+// with Claim as a single atomic operation, e.g. SET NX with an expiry.
+type ReplayStore interface {
+	// Claim records id for ttl unless it is already recorded and has not
+	// expired, reporting whether it did. Concurrent claims of an id must
+	// succeed at most once.
+	Claim(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	// Release forgets id, so that a later delivery can claim it again
+	Release(ctx context.Context, id string) error
+}
+
+// MemoryStore is an in-process ReplayStore, suited to a single instance
+type MemoryStore struct {
+	mu        sync.Mutex
+	expires   map[string]time.Time
+	nextPrune int
+	now       func() time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{expires: make(map[string]time.Time), now: time.Now}
+}
+
+// Claim records id for ttl unless it is already recorded and has not
+// expired, reporting whether it did
+func (s *MemoryStore) Claim(_ context.Context, id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if exp, ok := s.expires[id]; ok && now.Before(exp) {
+		return false, nil
+	}
+	s.expires[id] = now.Add(ttl)
+	// Drop expired IDs whenever the map doubles, keeping Claim O(1)
+	// amortized
+	if len(s.expires) >= s.nextPrune {
+		for k, exp := range s.expires {
+			if !now.Before(exp) {
+				delete(s.expires, k)
+			}
+		}
+		s.nextPrune = 2 * len(s.expires)
+		if s.nextPrune < 1024 {
+			s.nextPrune = 1024
+		}
+	}
+	return true, nil
+}
+
+// Release forgets id
+func (s *MemoryStore) Release(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.expires, id)
+	return nil
+}
@@ -0,0 +1,121 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+// delivery returns a signed delivery of an event with id
+func delivery(id string, now time.Time) *http.Request {
+	body := `{"id":"` + id + `","type":"thing.happened","createdAt":"2024-06-01T00:00:00Z","data":{}}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set(SignatureHeader, Sign(testSecret, now, []byte(body)))
+	return req
+}
+
+func TestMemoryStoreClaim(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	steps := []struct {
+		do   func() (bool, error)
+		want bool
+	}{
+		{func() (bool, error) { return s.Claim(ctx, "evt_1", time.Minute) }, true},
+		{func() (bool, error) { return s.Claim(ctx, "evt_1", time.Minute) }, false},
+		{func() (bool, error) { return s.Claim(ctx, "evt_2", time.Minute) }, true},
+		{func() (bool, error) { return true, s.Release(ctx, "evt_1") }, true},
+		{func() (bool, error) { return s.Claim(ctx, "evt_1", time.Minute) }, true},
+		{func() (bool, error) { now = now.Add(time.Minute); return s.Claim(ctx, "evt_2", time.Minute) }, true},
+	}
+	for i, step := range steps {
+		got, err := step.do()
+		if err != nil || got != step.want {
+			t.Errorf("step %d: got %v, %v; want %v", i, got, err, step.want)
+		}
+	}
+}
+
+func TestHandlerClaimsConcurrentDeliveriesOnce(t *testing.T) {
+	h := NewHandler(testSecret)
+	h.Verifier.ReplayStore = NewMemoryStore()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	h.OnOther(func(ctx context.Context, e Event) error {
+		calls.Add(1)
+		<-release
+		return nil
+	})
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	codes := make(chan int, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, delivery("evt_1", now))
+			codes <- w.Code
+		}()
+	}
+	// Every copy but the one being handled is acknowledged straight away
+	for i := 0; i < 7; i++ {
+		if code := <-codes; code != http.StatusOK {
+			t.Errorf("duplicate answered %d, want 200", code)
+		}
+	}
+	close(release)
+	wg.Wait()
+	if code := <-codes; code != http.StatusOK {
+		t.Errorf("handled delivery answered %d, want 200", code)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("handler called %d times, want 1", n)
+	}
+}
+
+func TestHandlerReleasesFailedEvents(t *testing.T) {
+	h := NewHandler(testSecret)
+	h.Verifier.ReplayStore = NewMemoryStore()
+	fail := true
+	var calls int
+	h.OnOther(func(ctx context.Context, e Event) error {
+		calls++
+		if fail {
+			return errors.New("database down")
+		}
+		return nil
+	})
+
+	now := time.Now()
+	for _, tt := range []struct {
+		fail bool
+		want int
+	}{
+		{true, http.StatusInternalServerError},
+		{false, http.StatusOK},
+		{false, http.StatusOK},
+	} {
+		fail = tt.fail
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, delivery("evt_1", now))
+		if w.Code != tt.want {
+			t.Errorf("answered %d, want %d", w.Code, tt.want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2: once failing, once on redelivery", calls)
+	}
+}
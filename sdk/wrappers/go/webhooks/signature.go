@@ -0,0 +1,183 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries a delivery's signature, as
+// "t=<unix seconds>,v1=<hex HMAC-SHA256>". The HMAC covers the timestamp, a
+// dot and the raw body; during secret rotation one v1 entry is sent per
+// active secret.
+const SignatureHeader = "X-Webhook-Signature"
+
+// DefaultTolerance is how far a signature's timestamp may be from the
+// current time by default. Every delivery attempt is signed anew, so only
+// replays and badly skewed clocks fall outside it.
+const DefaultTolerance = 5 * time.Minute
+
+// DefaultReplayTTL is how long a Verifier's ReplayStore remembers claimed
+// event IDs by default, covering the API's redelivery schedule
+const DefaultReplayTTL = 24 * time.Hour
+
+var (
+	// ErrInvalidSignature is returned when a delivery's signature is
+	// missing, malformed or matches none of the secrets
+	ErrInvalidSignature = errors.New("webhooks: invalid signature")
+	// ErrNoSecrets is returned when a Verifier has no secret to check
+	// signatures with
+	ErrNoSecrets = errors.New("webhooks: no signing secrets configured")
+	// ErrReplayed is returned for an event whose ID was already claimed
+	ErrReplayed = errors.New("webhooks: event already handled")
+)
+
+// Verifier checks the signatures of webhook deliveries
+type Verifier struct {
+	// Secrets are the endpoint's signing secrets. A signature matching any
+	// of them is accepted, so a new secret can be added before the old one
+	// is retired.
+	Secrets []string
+	// Tolerance is how far a signature's timestamp may be from Now, so a
+	// captured delivery cannot be replayed later (default:
+	// DefaultTolerance). Negative disables the check.
+	Tolerance time.Duration
+	// ReplayStore remembers the IDs of claimed events, so redeliveries and
+	// replays within the tolerance are not handled twice (optional)
+	ReplayStore ReplayStore
+	// ReplayTTL is how long ReplayStore remembers an event ID (default:
+	// DefaultReplayTTL)
+	ReplayTTL time.Duration
+	// Now is the time source of the tolerance check (default: time.Now)
+	Now func() time.Time
+}
+
+// Verify checks header, the delivery's SignatureHeader, against body, and
+// that its timestamp is within Tolerance
+func (v *Verifier) Verify(header string, body []byte) error {
+	if len(v.Secrets) == 0 {
+		return ErrNoSecrets
+	}
+	ts, sigs, err := parseSignature(header)
+	if err != nil {
+		return err
+	}
+	for _, secret := range v.Secrets {
+		want := signature(secret, ts, body)
+		for _, sig := range sigs {
+			if hmac.Equal(sig, want) {
+				return v.checkTimestamp(ts)
+			}
+		}
+	}
+	return fmt.Errorf("%w: no signature matches", ErrInvalidSignature)
+}
+
+// checkTimestamp checks that the unix time ts is within Tolerance of Now
+func (v *Verifier) checkTimestamp(ts int64) error {
+	tolerance := v.Tolerance
+	if tolerance < 0 {
+		return nil
+	}
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	age := now().Sub(time.Unix(ts, 0))
+	if age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp is %s off, outside the %s tolerance", ErrInvalidSignature, age.Round(time.Second), tolerance)
+	}
+	return nil
+}
+
+// Claim claims the event id in ReplayStore before it is handled, returning
+// ErrReplayed when it was already claimed. Without a ReplayStore it always
+// succeeds.
+func (v *Verifier) Claim(ctx context.Context, id string) error {
+	if v.ReplayStore == nil {
+		return nil
+	}
+	ttl := v.ReplayTTL
+	if ttl <= 0 {
+		ttl = DefaultReplayTTL
+	}
+	claimed, err := v.ReplayStore.Claim(ctx, id, ttl)
+	if err != nil {
+		return fmt.Errorf("webhooks: claiming event %s: %w", id, err)
+	}
+	if !claimed {
+		return fmt.Errorf("%w: %s", ErrReplayed, id)
+	}
+	return nil
+}
+
+// Release releases the claim on the event id. Call it when handling
+// failed, so the redelivered event is handled.
+func (v *Verifier) Release(ctx context.Context, id string) error {
+	if v.ReplayStore == nil {
+		return nil
+	}
+	if err := v.ReplayStore.Release(ctx, id); err != nil {
+		return fmt.Errorf("webhooks: releasing event %s: %w", id, err)
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value of body sent at t, to test
+// endpoints or to sign deliveries sent from elsewhere
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := t.Unix()
+	return "t=" + strconv.FormatInt(ts, 10) + ",v1=" + hex.EncodeToString(signature(secret, ts, body))
+}
+
+// signature returns the HMAC of body sent at the unix time ts
+func signature(secret string, ts int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// parseSignature splits a SignatureHeader value into its timestamp and v1
+// signatures. Entries of other schemes are skipped.
+func parseSignature(header string) (int64, [][]byte, error) {
+	if header == "" {
+		return 0, nil, fmt.Errorf("%w: missing %s header", ErrInvalidSignature, SignatureHeader)
+	}
+	var ts int64
+	var sigs [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, nil, fmt.Errorf("%w: bad timestamp %q", ErrInvalidSignature, value)
+			}
+			ts = n
+		case "v1":
+			sig, err := hex.DecodeString(value)
+			if err != nil {
+				return 0, nil, fmt.Errorf("%w: bad v1 signature", ErrInvalidSignature)
+			}
+			sigs = append(sigs, sig)
+		}
+	}
+	switch {
+	case ts == 0:
+		return 0, nil, fmt.Errorf("%w: missing timestamp", ErrInvalidSignature)
+	case len(sigs) == 0:
+		return 0, nil, fmt.Errorf("%w: missing v1 signature", ErrInvalidSignature)
+	}
+	return ts, sigs, nil
+}
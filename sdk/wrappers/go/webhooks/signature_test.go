@@ -0,0 +1,75 @@
+package webhooks
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"evt_1"}`)
+	valid := Sign(testSecret, now, body)
+
+	tests := []struct {
+		name    string
+		secrets []string
+		header  string
+		wantErr error
+	}{
+		{name: "valid", secrets: []string{testSecret}, header: valid},
+		{name: "rotated secret", secrets: []string{"whsec_new", testSecret}, header: valid},
+		{name: "several signatures", secrets: []string{"whsec_new"}, header: valid + ",v1=" + Sign("whsec_new", now, body)[len("t=1700000000,v1="):]},
+		{name: "other schemes skipped", secrets: []string{testSecret}, header: valid + ",v0=deadbeef"},
+		{name: "wrong secret", secrets: []string{"whsec_other"}, header: valid, wantErr: ErrInvalidSignature},
+		{name: "tampered body", secrets: []string{testSecret}, header: Sign(testSecret, now, []byte(`{"id":"evt_2"}`)), wantErr: ErrInvalidSignature},
+		{name: "missing header", secrets: []string{testSecret}, header: "", wantErr: ErrInvalidSignature},
+		{name: "missing timestamp", secrets: []string{testSecret}, header: "v1=00", wantErr: ErrInvalidSignature},
+		{name: "missing signature", secrets: []string{testSecret}, header: "t=1700000000", wantErr: ErrInvalidSignature},
+		{name: "bad hex", secrets: []string{testSecret}, header: "t=1700000000,v1=zz", wantErr: ErrInvalidSignature},
+		{name: "no secrets", header: valid, wantErr: ErrNoSecrets},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Verifier{Secrets: tt.secrets, Now: func() time.Time { return now }}
+			err := v.Verify(tt.header, body)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyTolerance(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"evt_1"}`)
+	header := Sign(testSecret, now, body)
+
+	tests := []struct {
+		name      string
+		skew      time.Duration
+		tolerance time.Duration
+		wantErr   bool
+	}{
+		{name: "on time"},
+		{name: "within the default", skew: 4 * time.Minute},
+		{name: "too old", skew: 6 * time.Minute, wantErr: true},
+		{name: "from the future", skew: -6 * time.Minute, wantErr: true},
+		{name: "custom tolerance", skew: 6 * time.Minute, tolerance: 10 * time.Minute},
+		{name: "outside a custom tolerance", skew: 2 * time.Minute, tolerance: time.Minute, wantErr: true},
+		{name: "check disabled", skew: 48 * time.Hour, tolerance: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Verifier{
+				Secrets:   []string{testSecret},
+				Tolerance: tt.tolerance,
+				Now:       func() time.Time { return now.Add(tt.skew) },
+			}
+			err := v.Verify(header, body)
+			if tt.wantErr != (err != nil) || err != nil && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("got %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Package yourapimock provides a hand-rolled mock of yourapi.API for unit
+// testing code that uses the SDK without an HTTP server.
+//
+//	mock := &yourapimock.Client{
+//		GetFunc: func(ctx context.Context, path string, result interface{}, opts ...yourapi.RequestOption) error {
+//			return yourapimock.SetResult(result, map[string]interface{}{"id": "cus_123"})
+//		},
+//	}
+//	svc := NewCustomerService(mock)
+package yourapimock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+)
+
+// Call records one invocation of the mock
+type Call struct {
+	// Method is the HTTP method the call maps to
+	Method string
+	Path   string
+	// Body is the request body for Post, Patch and Put, nil otherwise
+	Body interface{}
+	Opts []yourapi.RequestOption
+}
+
+// Client is a mock yourapi.API. Each method calls the matching Func field;
+// calls to methods whose Func is nil fail with an error. All calls are
+// recorded and available from Calls.
+type Client struct {
+	GetFunc            func(ctx context.Context, path string, result interface{}, opts ...yourapi.RequestOption) error
+	PostFunc           func(ctx context.Context, path string, body interface{}, result interface{}, opts ...yourapi.RequestOption) error
+	PatchFunc          func(ctx context.Context, path string, body interface{}, result interface{}, opts ...yourapi.RequestOption) error
+	PutFunc            func(ctx context.Context, path string, body interface{}, result interface{}, opts ...yourapi.RequestOption) error
+	DeleteFunc         func(ctx context.Context, path string, opts ...yourapi.RequestOption) error
+	PaginateCursorFunc func(ctx context.Context, path string, callback func(interface{}) error, opts ...yourapi.RequestOption) error
+	GetAllCursorFunc   func(ctx context.Context, path string, opts ...yourapi.RequestOption) ([]interface{}, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ yourapi.API = (*Client)(nil)
+
+// Get implements yourapi.API
+func (m *Client) Get(ctx context.Context, path string, result interface{}, opts ...yourapi.RequestOption) error {
+	m.record(http.MethodGet, path, nil, opts)
+	if m.GetFunc == nil {
+		return notConfigured("Get")
+	}
+	return m.GetFunc(ctx, path, result, opts...)
+}
+
+// Post implements yourapi.API
+func (m *Client) Post(ctx context.Context, path string, body interface{}, result interface{}, opts ...yourapi.RequestOption) error {
+	m.record(http.MethodPost, path, body, opts)
+	if m.PostFunc == nil {
+		return notConfigured("Post")
+	}
+	return m.PostFunc(ctx, path, body, result, opts...)
+}
+
+// Patch implements yourapi.API
+func (m *Client) Patch(ctx context.Context, path string, body interface{}, result interface{}, opts ...yourapi.RequestOption) error {
+	m.record(http.MethodPatch, path, body, opts)
+	if m.PatchFunc == nil {
+		return notConfigured("Patch")
+	}
+	return m.PatchFunc(ctx, path, body, result, opts...)
+}
+
+// Put implements yourapi.API
+func (m *Client) Put(ctx context.Context, path string, body interface{}, result interface{}, opts ...yourapi.RequestOption) error {
+	m.record(http.MethodPut, path, body, opts)
+	if m.PutFunc == nil {
+		return notConfigured("Put")
+	}
+	return m.PutFunc(ctx, path, body, result, opts...)
+}
+
+// Delete implements yourapi.API
+func (m *Client) Delete(ctx context.Context, path string, opts ...yourapi.RequestOption) error {
+	m.record(http.MethodDelete, path, nil, opts)
+	if m.DeleteFunc == nil {
+		return notConfigured("Delete")
+	}
+	return m.DeleteFunc(ctx, path, opts...)
+}
+
+// PaginateCursor implements yourapi.API
+func (m *Client) PaginateCursor(ctx context.Context, path string, callback func(interface{}) error, opts ...yourapi.RequestOption) error {
+	m.record(http.MethodGet, path, nil, opts)
+	if m.PaginateCursorFunc == nil {
+		return notConfigured("PaginateCursor")
+	}
+	return m.PaginateCursorFunc(ctx, path, callback, opts...)
+}
+
+// GetAllCursor implements yourapi.API. Without GetAllCursorFunc it collects
+// the items of PaginateCursorFunc, if set.
+func (m *Client) GetAllCursor(ctx context.Context, path string, opts ...yourapi.RequestOption) ([]interface{}, error) {
+	if m.GetAllCursorFunc != nil {
+		m.record(http.MethodGet, path, nil, opts)
+		return m.GetAllCursorFunc(ctx, path, opts...)
+	}
+	if m.PaginateCursorFunc == nil {
+		m.record(http.MethodGet, path, nil, opts)
+		return nil, notConfigured("GetAllCursor")
+	}
+	var items []interface{}
+	err := m.PaginateCursor(ctx, path, func(item interface{}) error {
+		items = append(items, item)
+		return nil
+	}, opts...)
+	return items, err
+}
+
+// Calls returns the calls made so far, in order
+func (m *Client) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// Reset clears the recorded calls
+func (m *Client) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *Client) record(method, path string, body interface{}, opts []yourapi.RequestOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Path: path, Body: body, Opts: opts})
+}
+
+func notConfigured(method string) error {
+	return fmt.Errorf("yourapimock: %s called but %sFunc is not set", method, method)
+}
+
+// SetResult copies value into result the way the client decodes responses,
+// by round-tripping it through JSON
+func SetResult(result, value interface{}) error {
+	if result == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("yourapimock: failed to marshal result: %w", err)
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,461 @@
+// Package yourapisandbox provides an in-memory implementation of
+// yourapi.API that simulates resource CRUD, cursor pagination and ID
+// generation without a server, for demos and offline development.
+//
+// A POST to a collection path such as /customers stores the body as a new
+// resource with generated id, createdAt and updatedAt fields. The resource
+// is then served at /customers/{id}, where it can be updated with PATCH
+// (merge) or PUT (replace) and removed with DELETE. A GET on the collection
+// returns a yourapi.CursorPaginatedResponse honouring the limit and cursor
+// query parameters; any other query parameter filters on the field of the
+// same name.
+//
+//	api := yourapisandbox.New()
+//	api.Seed("/customers", map[string]interface{}{"email": "ada@example.com", "name": "Ada"})
+//	svc := NewCustomerService(api)
+package yourapisandbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+)
+
+// DefaultPageSize is the page size of collection listings without a limit
+const DefaultPageSize = 20
+
+// MaxPageSize is the largest limit a listing accepts, matching the API
+const MaxPageSize = 100
+
+// Client is an in-memory yourapi.API. The exported fields may be set before
+// first use; the zero value is not usable, create one with New.
+type Client struct {
+	// NewID returns the ID of a resource created in collection (default:
+	// yourapi.NewUUID)
+	NewID func(collection string) (string, error)
+	// Now returns the time stamped on createdAt and updatedAt (default:
+	// time.Now in UTC)
+	Now func() time.Time
+
+	mu          sync.Mutex
+	collections map[string]*collection
+	// idempotent holds the response of each POST sent with an idempotency
+	// key, replayed when the key is sent again
+	idempotent map[string]map[string]interface{}
+	seq        int
+	requests   int
+}
+
+type resource struct {
+	seq  int
+	data map[string]interface{}
+}
+
+type collection struct {
+	items map[string]*resource
+}
+
+var _ yourapi.API = (*Client)(nil)
+
+// New returns an empty sandbox
+func New() *Client {
+	return &Client{
+		NewID:       func(string) (string, error) { return yourapi.NewUUID() },
+		Now:         func() time.Time { return time.Now().UTC() },
+		collections: make(map[string]*collection),
+		idempotent:  make(map[string]map[string]interface{}),
+	}
+}
+
+// SequentialIDs returns a NewID function producing prefix1, prefix2, ...
+// per collection, for reproducible demos
+func SequentialIDs(prefix string) func(collection string) (string, error) {
+	var mu sync.Mutex
+	next := make(map[string]int)
+	return func(collection string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		next[collection]++
+		return prefix + strconv.Itoa(next[collection]), nil
+	}
+}
+
+// Seed creates resources in collection as if each had been POSTed, and
+// returns their IDs. A resource carrying an "id" keeps it.
+func (c *Client) Seed(collectionPath string, resources ...interface{}) ([]string, error) {
+	ids := make([]string, 0, len(resources))
+	for _, r := range resources {
+		var created map[string]interface{}
+		if err := c.Post(context.Background(), collectionPath, r, &created); err != nil {
+			return ids, err
+		}
+		ids = append(ids, fmt.Sprint(created["id"]))
+	}
+	return ids, nil
+}
+
+// Items returns the resources stored in collection, in creation order
+func (c *Client) Items(collectionPath string) []map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	col := c.collections[cleanPath(collectionPath)]
+	if col == nil {
+		return nil
+	}
+	var out []map[string]interface{}
+	for _, r := range col.sorted() {
+		var item map[string]interface{}
+		setResult(&item, r.data)
+		out = append(out, item)
+	}
+	return out
+}
+
+// Reset removes every stored resource and forgets the idempotency keys
+// used
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.collections = make(map[string]*collection)
+	c.idempotent = make(map[string]map[string]interface{})
+}
+
+// Get implements yourapi.API, returning a resource or a collection listing
+func (c *Client) Get(ctx context.Context, path string, result interface{}, opts ...yourapi.RequestOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	req, err := yourapi.ResolveRequest(path, opts...)
+	if err != nil {
+		return err
+	}
+	p, query := splitQuery(req.Path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r, _, _ := c.lookup(p); r != nil {
+		return setResult(result, r.data)
+	}
+	if parent, _ := splitLast(p); c.collections[parent] != nil && c.collections[p] == nil {
+		return c.notFound(p)
+	}
+	page, err := c.list(p, query)
+	if err != nil {
+		return err
+	}
+	return setResult(result, page)
+}
+
+// Post implements yourapi.API, creating a resource in the collection at
+// path. A POST repeating an idempotency key gets the first one's response,
+// as from the API.
+func (c *Client) Post(ctx context.Context, path string, body interface{}, result interface{}, opts ...yourapi.RequestOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	req, err := yourapi.ResolveRequest(path, opts...)
+	if err != nil {
+		return err
+	}
+	p, _ := splitQuery(req.Path)
+	data, err := c.decodeBody(body)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if created, ok := c.idempotent[req.IdempotencyKey]; ok {
+		return setResult(result, created)
+	}
+	id, _ := data["id"].(string)
+	if id == "" {
+		if id, err = c.NewID(p); err != nil {
+			return fmt.Errorf("failed to generate id: %w", err)
+		}
+	}
+	col := c.collections[p]
+	if col == nil {
+		col = &collection{items: make(map[string]*resource)}
+		c.collections[p] = col
+	}
+	if _, exists := col.items[id]; exists {
+		return c.apiError(http.StatusConflict, "conflict", fmt.Sprintf("resource %s already exists", id))
+	}
+	now := c.Now().Format(time.RFC3339)
+	data["id"] = id
+	if _, ok := data["createdAt"]; !ok {
+		data["createdAt"] = now
+	}
+	data["updatedAt"] = now
+	c.seq++
+	col.items[id] = &resource{seq: c.seq, data: data}
+	if req.IdempotencyKey != "" {
+		var created map[string]interface{}
+		setResult(&created, data)
+		c.idempotent[req.IdempotencyKey] = created
+	}
+	return setResult(result, data)
+}
+
+// Patch implements yourapi.API, merging body into the resource at path.
+// Fields set to null are removed.
+func (c *Client) Patch(ctx context.Context, path string, body interface{}, result interface{}, opts ...yourapi.RequestOption) error {
+	return c.update(ctx, path, body, result, true, opts)
+}
+
+// Put implements yourapi.API, replacing the resource at path while keeping
+// its id and createdAt
+func (c *Client) Put(ctx context.Context, path string, body interface{}, result interface{}, opts ...yourapi.RequestOption) error {
+	return c.update(ctx, path, body, result, false, opts)
+}
+
+func (c *Client) update(ctx context.Context, path string, body interface{}, result interface{}, merge bool, opts []yourapi.RequestOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	req, err := yourapi.ResolveRequest(path, opts...)
+	if err != nil {
+		return err
+	}
+	p, _ := splitQuery(req.Path)
+	data, err := c.decodeBody(body)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, _, id := c.lookup(p)
+	if r == nil {
+		return c.notFound(p)
+	}
+	next := data
+	if merge {
+		next = r.data
+		for k, v := range data {
+			if v == nil {
+				delete(next, k)
+			} else {
+				next[k] = v
+			}
+		}
+	}
+	next["id"] = id
+	next["createdAt"] = r.data["createdAt"]
+	next["updatedAt"] = c.Now().Format(time.RFC3339)
+	r.data = next
+	return setResult(result, next)
+}
+
+// Delete implements yourapi.API, removing the resource at path
+func (c *Client) Delete(ctx context.Context, path string, opts ...yourapi.RequestOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	req, err := yourapi.ResolveRequest(path, opts...)
+	if err != nil {
+		return err
+	}
+	p, _ := splitQuery(req.Path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, col, id := c.lookup(p)
+	if r == nil {
+		return c.notFound(p)
+	}
+	delete(col.items, id)
+	return nil
+}
+
+// PaginateCursor implements yourapi.API
+func (c *Client) PaginateCursor(ctx context.Context, path string, callback func(interface{}) error, opts ...yourapi.RequestOption) error {
+	cursor := ""
+	for {
+		fullPath := path
+		if cursor != "" {
+			sep := "?"
+			if strings.Contains(path, "?") {
+				sep = "&"
+			}
+			fullPath = path + sep + "cursor=" + url.QueryEscape(cursor)
+		}
+		var page yourapi.CursorPaginatedResponse
+		if err := c.Get(ctx, fullPath, &page, opts...); err != nil {
+			return err
+		}
+		for _, item := range page.Items {
+			if err := callback(item); err != nil {
+				return err
+			}
+		}
+		if !page.HasMore || page.NextCursor == nil {
+			return nil
+		}
+		cursor = *page.NextCursor
+	}
+}
+
+// GetAllCursor implements yourapi.API
+func (c *Client) GetAllCursor(ctx context.Context, path string, opts ...yourapi.RequestOption) ([]interface{}, error) {
+	var items []interface{}
+	err := c.PaginateCursor(ctx, path, func(item interface{}) error {
+		items = append(items, item)
+		return nil
+	}, opts...)
+	return items, err
+}
+
+// lookup finds the resource at path, e.g. /customers/{id}
+func (c *Client) lookup(path string) (*resource, *collection, string) {
+	parent, id := splitLast(path)
+	col := c.collections[parent]
+	if col == nil {
+		return nil, nil, ""
+	}
+	return col.items[id], col, id
+}
+
+// list returns a page of the collection at path. The cursor is the creation
+// sequence of the last item returned, so deletes do not shift pages.
+func (c *Client) list(path string, query url.Values) (*yourapi.CursorPaginatedResponse, error) {
+	limit := DefaultPageSize
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxPageSize {
+			return nil, c.apiError(http.StatusBadRequest, "invalid_parameter", fmt.Sprintf("limit must be between 1 and %d", MaxPageSize))
+		}
+		limit = n
+	}
+	after := 0
+	if v := query.Get("cursor"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, c.apiError(http.StatusBadRequest, "invalid_cursor", "cursor is invalid")
+		}
+		after = n
+	}
+
+	page := &yourapi.CursorPaginatedResponse{Items: []interface{}{}}
+	col := c.collections[path]
+	if col == nil {
+		return page, nil
+	}
+	var last int
+	for _, r := range col.sorted() {
+		if r.seq <= after || !matches(r.data, query) {
+			continue
+		}
+		if len(page.Items) == limit {
+			page.HasMore = true
+			cursor := strconv.Itoa(last)
+			page.NextCursor = &cursor
+			break
+		}
+		page.Items = append(page.Items, r.data)
+		last = r.seq
+	}
+	return page, nil
+}
+
+func (col *collection) sorted() []*resource {
+	out := make([]*resource, 0, len(col.items))
+	for _, r := range col.items {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].seq < out[j].seq })
+	return out
+}
+
+// matches reports whether data has every filter field in query
+func matches(data map[string]interface{}, query url.Values) bool {
+	for key, values := range query {
+		if key == "limit" || key == "cursor" {
+			continue
+		}
+		v, ok := data[key]
+		if !ok || fmt.Sprint(v) != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeBody converts a request body to a JSON object the way the client
+// would encode it
+func (c *Client) decodeBody(body interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil || obj == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return nil, c.apiError(http.StatusBadRequest, "invalid_body", "request body must be a JSON object")
+	}
+	return obj, nil
+}
+
+func (c *Client) notFound(path string) error {
+	return c.apiError(http.StatusNotFound, "not_found", fmt.Sprintf("%s not found", path))
+}
+
+// apiError builds the error the API would return; c.mu must be held
+func (c *Client) apiError(status int, code, message string) error {
+	c.requests++
+	requestID := fmt.Sprintf("req_sandbox_%d", c.requests)
+	return &yourapi.APIError{
+		Status:    status,
+		Code:      code,
+		Message:   message,
+		RequestID: requestID,
+		Body:      map[string]interface{}{"code": code, "message": message, "requestId": requestID},
+	}
+}
+
+// setResult copies value into result by round-tripping it through JSON, so
+// stored resources are never aliased by callers
+func setResult(result, value interface{}) error {
+	if result == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// splitQuery separates a request path from its query string
+func splitQuery(path string) (string, url.Values) {
+	p, rawQuery, _ := strings.Cut(path, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	return cleanPath(p), query
+}
+
+func cleanPath(p string) string {
+	return "/" + strings.Trim(p, "/")
+}
+
+// splitLast splits /a/b/c into /a/b and c
+func splitLast(p string) (string, string) {
+	i := strings.LastIndex(p, "/")
+	if i <= 0 {
+		return "/", strings.TrimPrefix(p, "/")
+	}
+	return p[:i], p[i+1:]
+}
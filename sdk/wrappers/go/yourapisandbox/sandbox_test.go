@@ -0,0 +1,68 @@
+package yourapisandbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+)
+
+func TestRequestOptions(t *testing.T) {
+	ctx := context.Background()
+	api := New()
+	api.NewID = SequentialIDs("cus_")
+	if _, err := api.Seed("/customers",
+		map[string]interface{}{"name": "Ada", "plan": "pro"},
+		map[string]interface{}{"name": "Alan", "plan": "free"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := api.Get(ctx, "/customers/{id}", &got, yourapi.WithPathParams(yourapi.PathParams{"id": "cus_2"})); err != nil {
+		t.Fatal(err)
+	}
+	if got["name"] != "Alan" {
+		t.Errorf("WithPathParams: got %v", got)
+	}
+
+	var page yourapi.CursorPaginatedResponse
+	if err := api.Get(ctx, "/customers", &page, yourapi.WithQuery("plan", "pro")); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 {
+		t.Errorf("WithQuery: got %d items, want 1", len(page.Items))
+	}
+
+	key := yourapi.WithIdempotencyKey("key-1")
+	var first, second map[string]interface{}
+	if err := api.Post(ctx, "/customers", map[string]string{"name": "Grace"}, &first, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.Post(ctx, "/customers", map[string]string{"name": "Grace"}, &second, key); err != nil {
+		t.Fatal(err)
+	}
+	if first["id"] != second["id"] || len(api.Items("/customers")) != 3 {
+		t.Errorf("repeated idempotency key created %v and %v", first["id"], second["id"])
+	}
+
+	if err := api.Delete(ctx, "/customers/{id}", yourapi.WithPathParams(yourapi.PathParams{"id": "cus_1"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.Delete(ctx, "/customers/{id}"); err == nil {
+		t.Error("Delete without its path parameters succeeded")
+	}
+}
+
+func TestNewIDErrors(t *testing.T) {
+	api := New()
+	failing := errors.New("entropy exhausted")
+	api.NewID = func(string) (string, error) { return "", failing }
+	if err := api.Post(context.Background(), "/customers", map[string]string{"name": "Ada"}, nil); !errors.Is(err, failing) {
+		t.Errorf("got %v, want the NewID error", err)
+	}
+	if items := api.Items("/customers"); len(items) != 0 {
+		t.Errorf("stored %d resources", len(items))
+	}
+}
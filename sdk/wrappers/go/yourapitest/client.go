@@ -0,0 +1,39 @@
+package yourapitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+)
+
+// NewClient starts an httptest server running handler and returns a client
+// pointed at it, with a test API key and FastRetries. The server is closed
+// when the test ends. configure functions may adjust the options before
+// the client is built, e.g. to set a Clock or MaxRetries:
+//
+//	client := yourapitest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		yourapitest.WriteJSON(w, http.StatusOK, map[string]string{"id": "cus_123"})
+//	}))
+func NewClient(t testing.TB, handler http.Handler, configure ...func(*yourapi.ClientOptions)) *yourapi.Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	opts := yourapi.ClientOptions{
+		BaseURL:      srv.URL,
+		APIKey:       "test-api-key",
+		MaxRetries:   1,
+		RetryBackoff: FastRetries,
+	}
+	for _, fn := range configure {
+		fn(&opts)
+	}
+
+	client, err := yourapi.NewClient(opts)
+	if err != nil {
+		t.Fatalf("yourapitest: creating client: %v", err)
+	}
+	return client
+}
@@ -0,0 +1,163 @@
+package yourapitest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+)
+
+// FakeClock is a yourapi.Clock that only moves when told to, so retry
+// schedules, Retry-After waits and expiry windows can be tested to the
+// millisecond without sleeping. Set it as ClientOptions.Clock:
+//
+//	clock := yourapitest.NewFakeClock(time.Time{})
+//	opts := srv.ClientOptions()
+//	opts.Clock = clock
+//	opts.RetryBackoff = yourapi.RetryBackoff{}
+//	go func() { errc <- client.Get(ctx, "/flaky", nil) }()
+//
+//	clock.BlockUntil(1)          // the client is waiting to retry
+//	clock.Advance(time.Second)   // fire the first backoff
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+	waits  []time.Duration
+}
+
+var _ yourapi.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock reading start, or a fixed date in 2024
+// if start is zero
+func NewFakeClock(start time.Time) *FakeClock {
+	if start.IsZero() {
+		start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	c := &FakeClock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now implements yourapi.Clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer implements yourapi.Clock. The timer fires once Advance moves
+// the clock to or past its deadline; a non-positive d fires immediately.
+func (c *FakeClock) NewTimer(d time.Duration) yourapi.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, deadline: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.waits = append(c.waits, d)
+	if d <= 0 {
+		t.ch <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance moves the clock forward by d, firing every timer whose deadline
+// is reached, in deadline order
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(c.now.Add(d))
+}
+
+// Set moves the clock to t, firing every timer whose deadline is reached.
+// Moving backwards fires nothing.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(t)
+}
+
+func (c *FakeClock) setLocked(t time.Time) {
+	c.now = t
+	sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].deadline.Before(c.timers[j].deadline) })
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(t) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.ch <- timer.deadline
+	}
+	c.timers = pending
+	c.cond.Broadcast()
+}
+
+// AdvanceToNext moves the clock to the earliest pending timer's deadline
+// and fires it, returning how far the clock moved. It returns false when no
+// timer is pending.
+func (c *FakeClock) AdvanceToNext() (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.timers) == 0 {
+		return 0, false
+	}
+	next := c.timers[0].deadline
+	for _, t := range c.timers[1:] {
+		if t.deadline.Before(next) {
+			next = t.deadline
+		}
+	}
+	d := next.Sub(c.now)
+	c.setLocked(next)
+	return d, true
+}
+
+// BlockUntil waits until at least n timers are pending, i.e. until the
+// code under test is blocked waiting on the clock
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// Pending returns the number of timers waiting to fire
+func (c *FakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// Waits returns the duration of every timer created so far, in order. For
+// a client this is its schedule of retry backoffs and pauses.
+func (c *FakeClock) Waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.waits...)
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package yourapitest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	a := c.NewTimer(time.Second)
+	b := c.NewTimer(3 * time.Second)
+	stopped := c.NewTimer(2 * time.Second)
+	immediate := c.NewTimer(0)
+
+	fired := func(timer interface{ C() <-chan time.Time }) bool {
+		select {
+		case <-timer.C():
+			return true
+		default:
+			return false
+		}
+	}
+	if !fired(immediate) {
+		t.Error("zero timer did not fire at once")
+	}
+	if !stopped.Stop() || stopped.Stop() {
+		t.Error("Stop should report true once, for the pending timer")
+	}
+	if n := c.Pending(); n != 2 {
+		t.Fatalf("%d timers pending, want 2", n)
+	}
+
+	c.Advance(999 * time.Millisecond)
+	if fired(a) {
+		t.Error("timer fired before its deadline")
+	}
+	c.Advance(time.Millisecond)
+	if !fired(a) || fired(b) {
+		t.Error("Advance fired the wrong timers")
+	}
+	if d, ok := c.AdvanceToNext(); !ok || d != 2*time.Second || !fired(b) {
+		t.Errorf("AdvanceToNext moved %v, %v", d, ok)
+	}
+	if _, ok := c.AdvanceToNext(); ok {
+		t.Error("AdvanceToNext with no timer pending")
+	}
+	if got := c.Now(); !got.Equal(start.Add(3 * time.Second)) {
+		t.Errorf("Now = %v", got)
+	}
+	want := []time.Duration{time.Second, 3 * time.Second, 2 * time.Second, 0}
+	waits := c.Waits()
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("Waits = %v, want %v", waits, want)
+			break
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.BlockUntil(1)
+		close(done)
+	}()
+	c.NewTimer(time.Minute)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("BlockUntil did not return once a timer was pending")
+	}
+}
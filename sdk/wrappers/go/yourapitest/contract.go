@@ -0,0 +1,247 @@
+package yourapitest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+	"github.com/devdraft/devdraft-sdk-go/internal/openapi"
+)
+
+// Contract validates traffic against an OpenAPI document, so drift between
+// the SDK, stubbed responses and the spec fails tests
+type Contract struct {
+	doc      *openapi.Document
+	basePath string
+}
+
+// LoadContract reads the JSON OpenAPI document at path, failing the test if
+// it cannot be parsed
+func LoadContract(t testing.TB, path string) *Contract {
+	t.Helper()
+	doc, err := openapi.Load(path)
+	if err != nil {
+		t.Fatalf("yourapitest: loading contract: %v", err)
+	}
+	return newContract(doc)
+}
+
+// ParseContract parses a JSON OpenAPI document
+func ParseContract(data []byte) (*Contract, error) {
+	doc, err := openapi.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return newContract(doc), nil
+}
+
+func newContract(doc *openapi.Document) *Contract {
+	return &Contract{doc: doc, basePath: doc.BasePath()}
+}
+
+// operation finds the operation for a request path, which may or may not
+// carry the spec's server base path (e.g. /v1)
+func (c *Contract) operation(method, path string) (*openapi.PathItem, *openapi.Operation, map[string]string, error) {
+	if c.basePath != "" && strings.HasPrefix(path, c.basePath+"/") {
+		path = strings.TrimPrefix(path, c.basePath)
+	}
+	template, item, params, ok := c.doc.Match(path)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%s %s: path is not in the spec", method, path)
+	}
+	op := item.Operations()[method]
+	if op == nil {
+		return nil, nil, nil, fmt.Errorf("%s %s: method is not allowed on %s", method, path, template)
+	}
+	return item, op, params, nil
+}
+
+// ValidateRequest checks a request's path, method, parameters and JSON body
+// against the spec, returning one message per violation
+func (c *Contract) ValidateRequest(method, path, rawQuery string, header http.Header, body []byte) []string {
+	item, op, pathParams, err := c.operation(method, path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var problems []string
+	add := func(vs []openapi.Violation) {
+		for _, v := range vs {
+			problems = append(problems, fmt.Sprintf("%s %s: %s", method, path, v))
+		}
+	}
+
+	params, err := c.doc.OperationParameters(item, op)
+	if err != nil {
+		return []string{fmt.Sprintf("%s %s: %v", method, path, err)}
+	}
+	query, _ := url.ParseQuery(rawQuery)
+	known := make(map[string]bool)
+	for _, p := range params {
+		var value string
+		var present bool
+		switch p.In {
+		case "path":
+			value, present = pathParams[p.Name]
+		case "query":
+			known[p.Name] = true
+			if vs, ok := query[p.Name]; ok {
+				value, present = vs[0], true
+			}
+		case "header":
+			if vs := header.Values(p.Name); len(vs) > 0 {
+				value, present = vs[0], true
+			}
+		default:
+			continue
+		}
+		if !present {
+			if p.Required {
+				problems = append(problems, fmt.Sprintf("%s %s: missing required %s parameter %q", method, path, p.In, p.Name))
+			}
+			continue
+		}
+		add(c.doc.ValidateParam(p, value))
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		problems = append(problems, fmt.Sprintf("%s %s: undocumented query parameter %q", method, path, name))
+	}
+
+	rb, err := c.doc.RequestBody(op.RequestBody)
+	if err != nil {
+		return append(problems, fmt.Sprintf("%s %s: %v", method, path, err))
+	}
+	switch {
+	case rb == nil:
+		if len(bytes.TrimSpace(body)) > 0 {
+			problems = append(problems, fmt.Sprintf("%s %s: operation takes no request body", method, path))
+		}
+	case len(bytes.TrimSpace(body)) == 0:
+		if rb.Required {
+			problems = append(problems, fmt.Sprintf("%s %s: missing required request body", method, path))
+		}
+	default:
+		if schema, ok := openapi.JSONSchema(rb.Content); ok {
+			add(c.validateJSON(schema, body, "body"))
+		}
+	}
+	return problems
+}
+
+// ValidateResponse checks that status is documented for the request's
+// operation and that a JSON body matches the documented schema
+func (c *Contract) ValidateResponse(method, path string, status int, header http.Header, body []byte) []string {
+	_, op, _, err := c.operation(method, path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	resp, err := c.doc.ResponseFor(op, status)
+	if err != nil {
+		return []string{fmt.Sprintf("%s %s: %v", method, path, err)}
+	}
+	if resp == nil {
+		return []string{fmt.Sprintf("%s %s: status %d is not documented", method, path, status)}
+	}
+	schema, ok := openapi.JSONSchema(resp.Content)
+	if !ok {
+		return nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return []string{fmt.Sprintf("%s %s: status %d response has no body", method, path, status)}
+	}
+	if mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type")); err == nil && !isJSONMediaType(mediaType) {
+		return []string{fmt.Sprintf("%s %s: status %d response has content type %s", method, path, status, mediaType)}
+	}
+	var problems []string
+	for _, v := range c.validateJSON(schema, body, "response") {
+		problems = append(problems, fmt.Sprintf("%s %s: %d %s", method, path, status, v))
+	}
+	return problems
+}
+
+func (c *Contract) validateJSON(schema *openapi.Schema, data []byte, root string) []openapi.Violation {
+	value, err := openapi.DecodeJSON(data)
+	if err != nil {
+		return []openapi.Violation{{Path: root, Message: "invalid JSON: " + err.Error()}}
+	}
+	return c.doc.Validate(schema, value, root)
+}
+
+// Transport wraps base so every request sent and every response received
+// is validated against the contract, reporting violations through t.Errorf.
+// A nil base uses http.DefaultTransport.
+func (c *Contract) Transport(t testing.TB, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &contractTransport{t: t, contract: c, base: base}
+}
+
+// ClientOptions returns opts with its HTTP client's transport wrapped by
+// Transport, e.g. contract.ClientOptions(t, stub.ClientOptions())
+func (c *Contract) ClientOptions(t testing.TB, opts yourapi.ClientOptions) yourapi.ClientOptions {
+	var base http.RoundTripper
+	httpClient := &http.Client{}
+	if opts.HTTPClient != nil {
+		clone := *opts.HTTPClient
+		httpClient = &clone
+		base = opts.HTTPClient.Transport
+	}
+	httpClient.Transport = c.Transport(t, base)
+	opts.HTTPClient = httpClient
+	return opts
+}
+
+type contractTransport struct {
+	t        testing.TB
+	contract *Contract
+	base     http.RoundTripper
+}
+
+func (ct *contractTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	for _, p := range ct.contract.ValidateRequest(req.Method, req.URL.Path, req.URL.RawQuery, req.Header, body) {
+		ct.t.Errorf("yourapitest: contract: request %s", p)
+	}
+
+	resp, err := ct.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	for _, p := range ct.contract.ValidateResponse(req.Method, req.URL.Path, resp.StatusCode, resp.Header, respBody) {
+		ct.t.Errorf("yourapitest: contract: response %s", p)
+	}
+	return resp, nil
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
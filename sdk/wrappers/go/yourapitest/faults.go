@@ -0,0 +1,176 @@
+package yourapitest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Faults configures the failures a FaultTransport injects. Rates are shares
+// of all requests, between 0 and 1. A request gets at most one of a reset,
+// an error and a truncation, so ResetRate, ErrorRate and TruncateRate
+// should add up to 1 at most; LatencyRate is drawn independently and
+// combines with any of them.
+type Faults struct {
+	// Latency is added before LatencyRate of requests
+	Latency     time.Duration
+	LatencyRate float64
+	// ResetRate of requests fail with a connection reset before reaching
+	// the server
+	ResetRate float64
+	// ErrorRate of requests get a 5xx from ErrorStatuses without reaching
+	// the server, as from a failing gateway
+	ErrorRate     float64
+	ErrorStatuses []int
+	// TruncateRate of responses have their body cut off halfway, ending in
+	// io.ErrUnexpectedEOF
+	TruncateRate float64
+	// Seed makes the injected faults reproducible; 0 uses a random seed
+	Seed int64
+}
+
+// FaultStats counts the faults a FaultTransport has injected
+type FaultStats struct {
+	Requests    int
+	Delayed     int
+	Resets      int
+	Errors      int
+	Truncations int
+}
+
+// FaultTransport wraps an http.RoundTripper and injects latency, connection
+// resets, 5xx responses and truncated bodies, to validate retry and error
+// handling under failure
+type FaultTransport struct {
+	base   http.RoundTripper
+	faults Faults
+
+	mu    sync.Mutex
+	rng   *rand.Rand
+	stats FaultStats
+}
+
+// NewFaultTransport wraps base, or http.DefaultTransport if base is nil
+func NewFaultTransport(base http.RoundTripper, faults Faults) *FaultTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if len(faults.ErrorStatuses) == 0 {
+		faults.ErrorStatuses = []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+	}
+	seed := faults.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &FaultTransport{base: base, faults: faults, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Stats returns the faults injected so far
+func (f *FaultTransport) Stats() FaultStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+// plan draws the faults for one request
+type plan struct {
+	delay, reset, truncate bool
+	status                 int
+}
+
+func (f *FaultTransport) draw() plan {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats.Requests++
+	var p plan
+	if f.rng.Float64() < f.faults.LatencyRate {
+		p.delay = true
+		f.stats.Delayed++
+	}
+	// One draw split into consecutive bands, so each rate is a share of
+	// all requests rather than of those the previous faults spared
+	r := f.rng.Float64()
+	switch {
+	case r < f.faults.ResetRate:
+		p.reset = true
+		f.stats.Resets++
+	case r < f.faults.ResetRate+f.faults.ErrorRate:
+		p.status = f.faults.ErrorStatuses[f.rng.Intn(len(f.faults.ErrorStatuses))]
+		f.stats.Errors++
+	case r < f.faults.ResetRate+f.faults.ErrorRate+f.faults.TruncateRate:
+		p.truncate = true
+		f.stats.Truncations++
+	}
+	return p
+}
+
+// RoundTrip implements http.RoundTripper
+func (f *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	p := f.draw()
+	if p.delay {
+		timer := time.NewTimer(f.faults.Latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	if p.reset {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+	if p.status != 0 {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		body := fmt.Sprintf(`{"message":"injected fault","code":"injected_%d"}`, p.status)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", p.status, http.StatusText(p.status)),
+			StatusCode:    p.status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := f.base.RoundTrip(req)
+	if err != nil || !p.truncate {
+		return resp, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &truncatedBody{r: bytes.NewReader(data[:len(data)/2])}
+	return resp, nil
+}
+
+// truncatedBody ends with io.ErrUnexpectedEOF, as a connection dropped
+// mid-body does
+type truncatedBody struct {
+	r *bytes.Reader
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (b *truncatedBody) Close() error { return nil }
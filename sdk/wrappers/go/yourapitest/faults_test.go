@@ -0,0 +1,35 @@
+package yourapitest
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFaultRatesAreSharesOfAllRequests(t *testing.T) {
+	const n = 200000
+	f := NewFaultTransport(nil, Faults{
+		LatencyRate:  0.5,
+		ResetRate:    0.2,
+		ErrorRate:    0.3,
+		TruncateRate: 0.4,
+		Seed:         1,
+	})
+	for i := 0; i < n; i++ {
+		f.draw()
+	}
+	stats := f.Stats()
+	for _, tt := range []struct {
+		name  string
+		count int
+		rate  float64
+	}{
+		{"latency", stats.Delayed, 0.5},
+		{"resets", stats.Resets, 0.2},
+		{"errors", stats.Errors, 0.3},
+		{"truncations", stats.Truncations, 0.4},
+	} {
+		if got := float64(tt.count) / n; math.Abs(got-tt.rate) > 0.01 {
+			t.Errorf("%s: rate %.3f, want %.2f", tt.name, got, tt.rate)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package yourapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// FixtureDir is the directory fixtures are read from, relative to the
+// package under test
+var FixtureDir = "testdata"
+
+// UpdateGoldenEnv names the environment variable that makes Golden rewrite
+// fixtures instead of comparing against them
+const UpdateGoldenEnv = "YOURAPITEST_UPDATE"
+
+// ReadFixture returns the contents of FixtureDir/name, failing the test if
+// it cannot be read
+func ReadFixture(t testing.TB, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(FixtureDir, name))
+	if err != nil {
+		t.Fatalf("yourapitest: reading fixture: %v", err)
+	}
+	return data
+}
+
+// LoadFixture decodes the JSON fixture FixtureDir/name into v, rejecting
+// fields v does not declare so fixtures and types cannot drift apart
+func LoadFixture(t testing.TB, name string, v interface{}) {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(ReadFixture(t, name)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		t.Fatalf("yourapitest: decoding fixture %s: %v", name, err)
+	}
+}
+
+// Fixture responds to method and path with status and the JSON fixture
+// FixtureDir/name
+func (s *Server) Fixture(method, path string, status int, name string) {
+	s.t.Helper()
+	body := ReadFixture(s.t, name)
+	s.HandleFunc(method, path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	})
+}
+
+// FixtureResponse is a StubResponse with status and the JSON fixture
+// FixtureDir/name as its body
+func FixtureResponse(t testing.TB, status int, name string) StubResponse {
+	t.Helper()
+	return StubResponse{
+		Status: status,
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   string(ReadFixture(t, name)),
+	}
+}
+
+// Golden compares got, encoded as indented JSON, with the fixture
+// FixtureDir/name. With YOURAPITEST_UPDATE=1 set it writes the fixture
+// instead.
+func Golden(t testing.TB, name string, got interface{}) {
+	t.Helper()
+	data, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("yourapitest: encoding golden value: %v", err)
+	}
+	data = append(data, '\n')
+
+	path := filepath.Join(FixtureDir, name)
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("yourapitest: writing golden file: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("yourapitest: writing golden file: %v", err)
+		}
+		return
+	}
+	want := ReadFixture(t, name)
+	if !bytes.Equal(bytes.TrimSpace(want), bytes.TrimSpace(data)) {
+		t.Errorf("yourapitest: %s does not match golden file (rerun with %s=1 to update)\ngot:\n%s\nwant:\n%s", name, UpdateGoldenEnv, data, want)
+	}
+}
@@ -0,0 +1,137 @@
+package yourapitest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Load describes a load generation run. The run ends once Requests calls
+// have been made or Duration has elapsed, whichever comes first; with
+// neither set it runs until the context is done.
+type Load struct {
+	// Concurrency is the number of workers calling the function (default: 1)
+	Concurrency int
+	// Requests caps the total number of calls (optional)
+	Requests int
+	// Duration caps the run time (optional)
+	Duration time.Duration
+}
+
+// LoadResult summarizes a load generation run
+type LoadResult struct {
+	Requests int
+	Errors   int
+	Elapsed  time.Duration
+	// RPS is completed calls per second
+	RPS float64
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	Max time.Duration
+	// FirstErr is the first error returned by the function, if any
+	FirstErr error
+}
+
+func (r LoadResult) String() string {
+	return fmt.Sprintf("%d requests (%d errors) in %v: %.0f req/s, p50=%v p95=%v p99=%v max=%v",
+		r.Requests, r.Errors, r.Elapsed.Round(time.Millisecond), r.RPS, r.P50, r.P95, r.P99, r.Max)
+}
+
+// Run calls fn from l.Concurrency workers until the load is exhausted or
+// ctx is done, and reports throughput and latency. fn is passed the worker
+// number, e.g.
+//
+//	res := yourapitest.Load{Concurrency: 8, Duration: 5 * time.Second}.Run(ctx,
+//		func(ctx context.Context, worker int) error {
+//			return client.Get(ctx, "/customers/cus_123", nil)
+//		})
+func (l Load) Run(ctx context.Context, fn func(ctx context.Context, worker int) error) LoadResult {
+	workers := l.Concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	if l.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.Duration)
+		defer cancel()
+	}
+
+	var (
+		issued    atomic.Int64
+		mu        sync.Mutex
+		latencies []time.Duration
+		errs      int
+		firstErr  error
+		wg        sync.WaitGroup
+	)
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			var local []time.Duration
+			var localErrs int
+			var localErr error
+			for ctx.Err() == nil {
+				if l.Requests > 0 && issued.Add(1) > int64(l.Requests) {
+					break
+				}
+				t0 := time.Now()
+				err := fn(ctx, worker)
+				// A call cut short by the end of the run is not a result
+				if err != nil && ctx.Err() != nil {
+					break
+				}
+				local = append(local, time.Since(t0))
+				if err != nil {
+					localErrs++
+					if localErr == nil {
+						localErr = err
+					}
+				}
+			}
+			mu.Lock()
+			latencies = append(latencies, local...)
+			errs += localErrs
+			if firstErr == nil {
+				firstErr = localErr
+			}
+			mu.Unlock()
+		}(w)
+	}
+	wg.Wait()
+
+	res := LoadResult{
+		Requests: len(latencies),
+		Errors:   errs,
+		Elapsed:  time.Since(start),
+		FirstErr: firstErr,
+	}
+	if res.Elapsed > 0 {
+		res.RPS = float64(res.Requests) / res.Elapsed.Seconds()
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		res.P50 = percentile(latencies, 0.50)
+		res.P95 = percentile(latencies, 0.95)
+		res.P99 = percentile(latencies, 0.99)
+		res.Max = latencies[len(latencies)-1]
+	}
+	return res
+}
+
+// percentile returns the q quantile of sorted latencies
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	i := int(q*float64(len(sorted))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
@@ -0,0 +1,313 @@
+// Package yourapitest provides an httptest-based fake of the API for
+// integration-style tests of code using the SDK. It speaks the API's
+// conventions: cursor and page pagination envelopes, {message, code,
+// requestId} error bodies, validation errors and X-RateLimit-* headers.
+//
+//	srv := yourapitest.NewServer(t)
+//	srv.JSON("GET", "/customers/{id}", 200, map[string]interface{}{"id": "cus_123"})
+//	srv.Paginate("/events", events, 50)
+//	srv.Error("POST", "/payments", 402, "card_declined", "Your card was declined")
+//
+//	client, err := yourapi.NewClient(srv.ClientOptions())
+package yourapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+)
+
+// Request is a request received by the Server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a fake API server. Routes match on method and path, where a
+// {param} segment matches any single segment; unmatched requests get a 404
+// error body.
+type Server struct {
+	*httptest.Server
+
+	t         testing.TB
+	mu        sync.Mutex
+	routes    []route
+	requests  []Request
+	nextID    int
+	rateLimit *rateWindow
+}
+
+type route struct {
+	method   string
+	segments []string
+	handler  http.Handler
+}
+
+// NewServer starts a Server that is closed when the test ends
+func NewServer(t testing.TB) *Server {
+	s := &Server{t: t}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// ClientOptions returns options pointing a client at the server, with
+// retry waits (including Retry-After) capped at a millisecond so tests of
+// failure paths run instantly
+func (s *Server) ClientOptions() yourapi.ClientOptions {
+	return yourapi.ClientOptions{
+		BaseURL:      s.URL,
+		APIKey:       "test-api-key",
+		MaxRetries:   1,
+		RetryBackoff: FastRetries,
+	}
+}
+
+// FastRetries is a RetryBackoff for tests: every wait between retries,
+// including those requested through Retry-After, is at most a millisecond
+var FastRetries = yourapi.RetryBackoff{
+	Base:          time.Millisecond,
+	Max:           time.Millisecond,
+	MaxRetryAfter: time.Millisecond,
+}
+
+// Handle registers h for method and path. Later registrations take
+// precedence, so tests can override a default route.
+func (s *Server) Handle(method, path string, h http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append([]route{{method: method, segments: splitPath(path), handler: h}}, s.routes...)
+}
+
+// HandleFunc registers f for method and path
+func (s *Server) HandleFunc(method, path string, f func(http.ResponseWriter, *http.Request)) {
+	s.Handle(method, path, http.HandlerFunc(f))
+}
+
+// JSON responds to method and path with status and body encoded as JSON
+func (s *Server) JSON(method, path string, status int, body interface{}) {
+	s.HandleFunc(method, path, func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, status, body)
+	})
+}
+
+// Error responds to method and path with an API error body
+func (s *Server) Error(method, path string, status int, code, message string) {
+	s.HandleFunc(method, path, func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, status, code, message)
+	})
+}
+
+// ValidationError responds to method and path with a 422 whose details list
+// a message for each field
+func (s *Server) ValidationError(method, path string, fields map[string]string) {
+	s.HandleFunc(method, path, func(w http.ResponseWriter, r *http.Request) {
+		WriteValidationError(w, fields)
+	})
+}
+
+// Paginate serves items from GET path in cursor-paginated pages of pageSize,
+// as {items, nextCursor, hasMore}
+func (s *Server) Paginate(path string, items []interface{}, pageSize int) {
+	s.HandleFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		start := 0
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(cursor, "c"))
+			if err != nil || n < 0 || n > len(items) {
+				WriteError(w, http.StatusBadRequest, "invalid_cursor", "invalid cursor")
+				return
+			}
+			start = n
+		}
+		end := start + pageSize
+		if end > len(items) {
+			end = len(items)
+		}
+		page := yourapi.CursorPaginatedResponse{Items: items[start:end], HasMore: end < len(items)}
+		if page.HasMore {
+			next := "c" + strconv.Itoa(end)
+			page.NextCursor = &next
+		}
+		if page.Items == nil {
+			page.Items = []interface{}{}
+		}
+		WriteJSON(w, http.StatusOK, page)
+	})
+}
+
+// PaginatePages serves items from GET path in numbered pages selected by the
+// page and perPage query parameters (defaults 1 and perPage), as
+// {items, page, perPage, totalPages, totalItems}
+func (s *Server) PaginatePages(path string, items []interface{}, perPage int) {
+	s.HandleFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		page, size := 1, perPage
+		if v, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && v > 0 {
+			page = v
+		}
+		if v, err := strconv.Atoi(r.URL.Query().Get("perPage")); err == nil && v > 0 {
+			size = v
+		}
+		start := (page - 1) * size
+		if start > len(items) {
+			start = len(items)
+		}
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		WriteJSON(w, http.StatusOK, yourapi.PagePaginatedResponse{
+			Items:      append([]interface{}{}, items[start:end]...),
+			Page:       page,
+			PerPage:    size,
+			TotalPages: (len(items) + size - 1) / size,
+			TotalItems: len(items),
+		})
+	})
+}
+
+// RateLimit enforces a fixed window of limit requests per window across all
+// routes. Every response carries X-RateLimit-Limit, X-RateLimit-Remaining
+// and X-RateLimit-Reset; requests over the limit get a 429 with Retry-After.
+func (s *Server) RateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimit = &rateWindow{limit: limit, window: window}
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	s.nextID++
+	w.Header().Set("X-Request-Id", fmt.Sprintf("req_test_%d", s.nextID))
+	limited := s.rateLimit != nil && !s.rateLimit.take(w.Header(), time.Now())
+	handler := s.match(r.Method, r.URL.Path)
+	s.mu.Unlock()
+
+	if limited {
+		WriteError(w, http.StatusTooManyRequests, "rate_limited", "rate limit exceeded")
+		return
+	}
+	if handler == nil {
+		WriteError(w, http.StatusNotFound, "not_found", fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+		return
+	}
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+	handler.ServeHTTP(w, r)
+}
+
+// match returns the handler for the request; s.mu must be held
+func (s *Server) match(method, path string) http.Handler {
+	segments := splitPath(path)
+	for _, rt := range s.routes {
+		if rt.method == method && segmentsMatch(rt.segments, segments) {
+			return rt.handler
+		}
+	}
+	return nil
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// rateWindow is a fixed-window request counter
+type rateWindow struct {
+	limit  int
+	window time.Duration
+	start  time.Time
+	count  int
+}
+
+// take counts a request, sets the rate limit headers and reports whether
+// the request is within the limit
+func (rw *rateWindow) take(h http.Header, now time.Time) bool {
+	if now.Sub(rw.start) >= rw.window {
+		rw.start = now
+		rw.count = 0
+	}
+	reset := rw.start.Add(rw.window)
+	rw.count++
+	remaining := rw.limit - rw.count
+	if remaining < 0 {
+		remaining = 0
+	}
+	h.Set("X-RateLimit-Limit", strconv.Itoa(rw.limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	if rw.count <= rw.limit {
+		return true
+	}
+	retryAfter := int(reset.Sub(now).Seconds() + 0.999)
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	h.Set("Retry-After", strconv.Itoa(retryAfter))
+	return false
+}
+
+// WriteJSON writes body as a JSON response with status
+func WriteJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+// WriteError writes an API error body {message, code, requestId}, taking the
+// request ID from the X-Request-Id response header if set
+func WriteError(w http.ResponseWriter, status int, code, message string) {
+	WriteJSON(w, status, map[string]interface{}{
+		"message":   message,
+		"code":      code,
+		"requestId": w.Header().Get("X-Request-Id"),
+	})
+}
+
+// WriteValidationError writes a 422 error whose details list the message
+// for each field
+func WriteValidationError(w http.ResponseWriter, fields map[string]string) {
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	details := make([]map[string]string, 0, len(fields))
+	for _, field := range names {
+		details = append(details, map[string]string{"field": field, "code": "invalid", "message": fields[field]})
+	}
+	WriteJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+		"message":   "validation failed",
+		"code":      "validation_error",
+		"requestId": w.Header().Get("X-Request-Id"),
+		"details":   details,
+	})
+}
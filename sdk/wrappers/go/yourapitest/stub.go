@@ -0,0 +1,200 @@
+package yourapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	yourapi "github.com/devdraft/devdraft-sdk-go"
+)
+
+// StubResponse is a canned outcome for a request matched by a StubTransport
+type StubResponse struct {
+	Status int
+	Header http.Header
+	// Body is sent as is; JSON, if set, is encoded and takes precedence
+	Body string
+	JSON interface{}
+	// Delay is waited before responding, or before failing with Err
+	Delay time.Duration
+	// Err makes the round trip fail with this transport error
+	Err error
+}
+
+// Matcher selects the requests a stub applies to
+type Matcher func(*http.Request) bool
+
+// Match matches requests by method and path, where a {param} segment
+// matches any single segment. An empty method matches any method.
+func Match(method, path string) Matcher {
+	want := splitPath(path)
+	return func(r *http.Request) bool {
+		if method != "" && r.Method != method {
+			return false
+		}
+		return segmentsMatch(want, splitPath(r.URL.Path))
+	}
+}
+
+// Any matches every request
+func Any() Matcher {
+	return func(*http.Request) bool { return true }
+}
+
+type stub struct {
+	match     Matcher
+	responses []StubResponse
+}
+
+// StubTransport is an http.RoundTripper serving queued responses without a
+// server. Responses queued for a matcher are used once each, in order;
+// requests matching no remaining stub fail. Every request is captured for
+// later assertions.
+type StubTransport struct {
+	mu       sync.Mutex
+	stubs    []*stub
+	requests []Request
+}
+
+// NewStubTransport creates an empty StubTransport
+func NewStubTransport() *StubTransport {
+	return &StubTransport{}
+}
+
+// Enqueue queues responses for requests selected by match
+func (s *StubTransport) Enqueue(match Matcher, responses ...StubResponse) *StubTransport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stubs = append(s.stubs, &stub{match: match, responses: responses})
+	return s
+}
+
+// On queues responses for requests with method and path
+func (s *StubTransport) On(method, path string, responses ...StubResponse) *StubTransport {
+	return s.Enqueue(Match(method, path), responses...)
+}
+
+// Requests returns the captured requests, in order
+func (s *StubTransport) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Pending returns the number of queued responses not yet used
+func (s *StubTransport) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, st := range s.stubs {
+		n += len(st.responses)
+	}
+	return n
+}
+
+// ClientOptions returns options for a client sending all requests through
+// the transport, with FastRetries
+func (s *StubTransport) ClientOptions() yourapi.ClientOptions {
+	return yourapi.ClientOptions{
+		BaseURL:      "http://yourapi.test",
+		APIKey:       "test-api-key",
+		HTTPClient:   &http.Client{Transport: s},
+		MaxRetries:   1,
+		RetryBackoff: FastRetries,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (s *StubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+	var resp *StubResponse
+	for _, st := range s.stubs {
+		if len(st.responses) > 0 && st.match(req) {
+			resp = &st.responses[0]
+			st.responses = st.responses[1:]
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if resp == nil {
+		return nil, fmt.Errorf("yourapitest: no stub for %s %s", req.Method, req.URL.Path)
+	}
+	if resp.Delay > 0 {
+		timer := time.NewTimer(resp.Delay)
+		defer timer.Stop()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	return resp.response(req)
+}
+
+func (r *StubResponse) response(req *http.Request) (*http.Response, error) {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	body := []byte(r.Body)
+	if r.JSON != nil {
+		var err error
+		if body, err = json.Marshal(r.JSON); err != nil {
+			return nil, fmt.Errorf("yourapitest: failed to marshal stub body: %w", err)
+		}
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", "application/json")
+		}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// segmentsMatch reports whether path segments match a pattern whose
+// {param} segments match anything
+func segmentsMatch(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, seg := range pattern {
+		if seg != segments[i] && !(strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")) {
+			return false
+		}
+	}
+	return true
+}